@@ -0,0 +1,158 @@
+package stickyfields
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// Config configures analyzers created by NewAnalyzer and validations of ValidateFunc.
+// Its fields mirror the flags of the analyzer.
+type Config = sf.Config
+
+// Option types of Config fields.
+type (
+	StringList    = sf.StringList
+	Regexp        = sf.Regexp
+	RegexpList    = sf.RegexpList
+	FieldMapping  = sf.FieldMapping
+	FieldMappings = sf.FieldMappings
+	Severities    = sf.Severities
+	ChangedLines  = sf.ChangedLines
+	LineRange     = sf.LineRange
+	Layer         = sf.Layer
+	Layers        = sf.Layers
+	LayerRule     = sf.LayerRule
+	LayerRules    = sf.LayerRules
+)
+
+// Presets of Config.Mode.
+const (
+	ModeStrict  = sf.ModeStrict
+	ModeDefault = sf.ModeDefault
+	ModeLenient = sf.ModeLenient
+)
+
+// Sides of converters checked, see Config.Check.
+const (
+	CheckBoth   = sf.CheckBoth
+	CheckInput  = sf.CheckInput
+	CheckOutput = sf.CheckOutput
+)
+
+// Severities of findings, see Config.Severities.
+const (
+	SeverityError   = sf.SeverityError
+	SeverityWarning = sf.SeverityWarning
+	SeverityInfo    = sf.SeverityInfo
+)
+
+// Codes of findings, see Config.Disable.
+const (
+	CodeMissingOutput      = sf.CodeMissingOutput
+	CodeMissingInput       = sf.CodeMissingInput
+	CodeHardcoded          = sf.CodeHardcoded
+	CodeDuplicateWrites    = sf.CodeDuplicateWrites
+	CodeSwapped            = sf.CodeSwapped
+	CodeUnkeyedLiteral     = sf.CodeUnkeyedLiteral
+	CodeUnhandledOneof     = sf.CodeUnhandledOneof
+	CodeUnmapped           = sf.CodeUnmapped
+	CodeUnknownCoverage    = sf.CodeUnknownCoverage
+	CodeUnregistered       = sf.CodeUnregistered
+	CodeRoundTrip          = sf.CodeRoundTrip
+	CodeMissingReverse     = sf.CodeMissingReverse
+	CodeDeadField          = sf.CodeDeadField
+	CodeNaming             = sf.CodeNaming
+	CodeDuplicateConverter = sf.CodeDuplicateConverter
+	CodeLayering           = sf.CodeLayering
+	CodeInputMutation      = sf.CodeInputMutation
+	CodeNilResult          = sf.CodeNilResult
+	CodeMissingError       = sf.CodeMissingError
+	CodeNilInput           = sf.CodeNilInput
+)
+
+// DefaultConfig returns the configuration used when no flags are given.
+func DefaultConfig() *Config {
+	return sf.DefaultConfig()
+}
+
+// NewAnalyzer creates an analyzer reporting all findings with the configuration,
+// e.g. to bundle it with other analyzers in a custom linter.
+func NewAnalyzer(cfg *Config) *analysis.Analyzer {
+	return sf.NewAnalyzer(cfg)
+}
+
+// Func is a function that may be a converter: a declared function or a function literal.
+type Func = sf.Func
+
+// NewFunc creates a Func from a function declaration of the package of the pass.
+func NewFunc(pass *analysis.Pass, decl *ast.FuncDecl) *Func {
+	return sf.NewFuncFromDecl(pass, decl)
+}
+
+// NewFuncLit creates a Func from a function literal of the package of the pass,
+// named after the identifier it's assigned to, if any.
+func NewFuncLit(pass *analysis.Pass, lit *ast.FuncLit, name *ast.Ident) *Func {
+	return sf.NewFuncFromLit(pass, lit, name)
+}
+
+// Candidate is the struct a converter converts from or into.
+type Candidate = sf.Candidate
+
+// ContainerType tells how a candidate struct is passed.
+type ContainerType = sf.ContainerType
+
+// Containers of candidates.
+const (
+	ContainerNone    = sf.ContainerNone
+	ContainerPointer = sf.ContainerPointer
+	ContainerSlice   = sf.ContainerSlice
+	ContainerMap     = sf.ContainerMap
+)
+
+// Candidates returns the input and output structs of the function, or an error if it has none.
+func Candidates(pass *analysis.Pass, fn *Func) (in, out Candidate, err error) {
+	return sf.Candidates(fn, pass)
+}
+
+// IsConverter reports whether the analyzer considers the function a converter with the configuration.
+// A nil cfg stands for DefaultConfig.
+func IsConverter(pass *analysis.Pass, fn *Func, cfg *Config) bool {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return sf.IsPossibleConverter(fn, pass, cfg)
+}
+
+// Result is the result of validating a converter: its missing fields, coverage and other findings.
+type Result = sf.ConverterValidationResult
+
+// ValidateFunc validates the function as a converter, whether the analyzer would consider it one or not.
+// A nil cfg stands for DefaultConfig; configuration files and //sf:config directives of the package
+// apply on top of it. The pass may be one of any analyzer, though structs of other packages are
+// known better when the analyzer requires FactsAnalyzer.
+func ValidateFunc(pass *analysis.Pass, fn *Func, cfg *Config) (Result, error) {
+	return sf.ValidateFunc(pass, fn, cfg)
+}
+
+// FactsAnalyzer exports metadata of struct fields (e.g. //sf:ignore) as facts for converters
+// of other packages. Analyzers calling ValidateFunc should require it.
+var FactsAnalyzer = sf.FactsAnalyzer
+
+// Mapping is the mapping table of a converter: where every output field comes from
+// and where every input field goes to.
+type Mapping = sf.ConverterMapping
+
+// Rows of Mapping.
+type (
+	OutputFieldMapping = sf.OutputFieldMapping
+	InputFieldMapping  = sf.InputFieldMapping
+)
+
+// ExtractMapping returns the mapping table of the function, e.g. to generate documentation
+// of converters or review artifacts. It returns an error if the function has no candidates.
+func ExtractMapping(pass *analysis.Pass, fn *Func) (Mapping, error) {
+	return sf.ExtractMapping(fn, pass)
+}
@@ -0,0 +1,184 @@
+// Command stickyfields-graph prints the graph of which types convert into which, e.g.
+//
+//	stickyfields-graph ./... | dot -Tsvg > conversions.svg
+//	stickyfields-graph -format=json ./...
+//
+// Nodes are the models of converters, grouped by package, and edges the converters along with their
+// positions, so data flows between layers can be visualized. Converters are detected like stickyfields
+// does, with the same options.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// Output formats of the graph.
+const (
+	formatDOT  = "dot"
+	formatJSON = "json"
+)
+
+var (
+	format = flag.String("format", formatDOT, "output format of the graph: "+formatDOT+" or "+formatJSON)
+	tests  = flag.Bool("test", false, "analyze test files too")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("stickyfields-graph: ")
+
+	cfg := sf.DefaultConfig()
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stickyfields-graph [-flag] [package]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *format != formatDOT && *format != formatJSON {
+		log.Fatalf("unknown format %q: expected %q or %q", *format, formatDOT, formatJSON)
+	}
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	g, err := conversionGraph(cfg, patterns)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *format == formatJSON {
+		err = writeJSON(os.Stdout, g)
+	} else {
+		err = writeDOT(os.Stdout, g)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// graph is the conversion graph as printed.
+type graph struct {
+	Nodes []node `json:"nodes"`
+	Edges []edge `json:"edges"`
+}
+
+// node is a model converted by converters.
+type node struct {
+	// ID is the fully qualified name of the model, e.g. example.com/db.User.
+	ID      string `json:"id"`
+	Package string `json:"package"`
+	Name    string `json:"name"`
+}
+
+// edge is a converter from one model into another.
+type edge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Converter string `json:"converter"`
+	Package   string `json:"package"`
+	// Position is the position of the converter relative to the working directory, e.g. conv/user.go:12:6.
+	Position string `json:"position"`
+}
+
+// conversionGraph returns the conversion graph of converters of the packages matching the patterns.
+func conversionGraph(cfg *sf.Config, patterns []string) (*graph, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: *tests}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("loading packages failed")
+	}
+	checked, err := checker.Analyze([]*analysis.Analyzer{sf.NewAnalyzer(cfg)}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*sf.Result
+	var fset *token.FileSet
+	for _, act := range checked.Roots {
+		if result, ok := act.Result.(*sf.Result); ok && act.Err == nil {
+			results = append(results, result)
+			fset = act.Package.Fset
+		}
+	}
+
+	nodes, edges := sf.ConversionGraph(results)
+	g := &graph{Nodes: []node{}, Edges: []edge{}}
+	for _, obj := range nodes {
+		g.Nodes = append(g.Nodes, node{ID: typeKey(obj), Package: obj.Pkg().Path(), Name: obj.Name()})
+	}
+	wd, _ := os.Getwd()
+	for _, e := range edges {
+		pos := fset.Position(e.Pos)
+		if rel, err := filepath.Rel(wd, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = rel
+		}
+		g.Edges = append(g.Edges, edge{
+			From:      typeKey(e.From),
+			To:        typeKey(e.To),
+			Converter: e.Converter,
+			Package:   e.Package,
+			Position:  pos.String(),
+		})
+	}
+	return g, nil
+}
+
+// typeKey returns the fully qualified name of the named type.
+func typeKey(obj *types.TypeName) string {
+	return types.TypeString(obj.Type(), nil)
+}
+
+// writeJSON prints the graph as an indented JSON object.
+func writeJSON(w io.Writer, g *graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// writeDOT prints the graph in the Graphviz DOT language, with a cluster of nodes per package
+// and edges labeled with converter names.
+func writeDOT(w io.Writer, g *graph) error {
+	var b strings.Builder
+	b.WriteString("digraph conversions {\n\trankdir=LR;\n\tnode [shape=box];\n")
+
+	var pkgs []string
+	for _, n := range g.Nodes {
+		if !slices.Contains(pkgs, n.Package) {
+			pkgs = append(pkgs, n.Package)
+		}
+	}
+	for i, pkg := range pkgs {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, pkg)
+		for _, n := range g.Nodes {
+			if n.Package == pkg {
+				fmt.Fprintf(&b, "\t\t%q [label=%q];\n", n.ID, n.Name)
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q, tooltip=%q];\n", e.From, e.To, e.Converter, e.Position)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
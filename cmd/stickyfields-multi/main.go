@@ -0,0 +1,18 @@
+// Command stickyfields-multi runs the sub-checks of stickyfields as separate analyzers,
+// so they can be picked and chosen, e.g.
+//
+//	stickyfields-multi -hardcodedfields -swappedfields ./...
+//
+// All sub-checks run unless some are enabled explicitly. Options are prefixed by the
+// check's name (e.g. -leakingfields.min-coverage=0.9).
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/amberpixels/go-stickyfields"
+)
+
+func main() {
+	multichecker.Main(stickyfields.All()...)
+}
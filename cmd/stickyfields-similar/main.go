@@ -0,0 +1,141 @@
+// Command stickyfields-similar reports pairs of structurally similar structs of different layers
+// having no converter in either direction, e.g.
+//
+//	stickyfields-similar -layers=/model$ -layers=/db$ -layers=/api$ ./...
+//
+// Every expression of -layers matches import paths of the packages of a layer. Structs of different
+// layers sharing fields of the same names (ignoring case) and similar types are likely copied
+// into each other field by field somewhere, e.g. in handlers, rather than by a converter.
+// Converters are detected like stickyfields does, with the same options.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+var (
+	layers     sf.RegexpList
+	minOverlap = flag.Float64("min-overlap", 0.7, "minimal ratio of shared fields to the fields of the larger struct")
+	minShared  = flag.Int("min-shared", 3, "minimal number of shared fields")
+	tests      = flag.Bool("test", false, "scan test files too")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("stickyfields-similar: ")
+
+	flag.Var(&layers, "layers", "regular expression of import paths of layer packages (repeatable), one per layer")
+	cfg := sf.DefaultConfig()
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stickyfields-similar -layers=EXPR -layers=EXPR [-layers=EXPR ...] [-flag] [package]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if len(layers) < 2 {
+		log.Fatal("-layers requires at least two layers")
+	}
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pairs, err := similarStructs(cfg, patterns)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writePairs(os.Stdout, pairs)
+	if len(pairs) > 0 {
+		os.Exit(3)
+	}
+}
+
+// similarStructs returns similar structs of the layers of the packages matching the patterns
+// having no converter in either direction.
+func similarStructs(cfg *sf.Config, patterns []string) ([]sf.SimilarStructs, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: *tests}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("loading packages failed")
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{sf.NewAnalyzer(cfg)}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Models of converters, in both directions.
+	converted := make(map[[2]string]bool)
+	for _, act := range graph.Roots {
+		result, ok := act.Result.(*sf.Result)
+		if !ok || act.Err != nil {
+			continue
+		}
+		for _, c := range result.Converters {
+			in, out := typeKey(c.InputModel), typeKey(c.OutputModel)
+			converted[[2]string{in, out}] = true
+			converted[[2]string{out, in}] = true
+		}
+	}
+
+	structs := make([][]*types.TypeName, len(layers))
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		// Test variants repeat structs of the package.
+		if seen[pkg.PkgPath] || pkg.Types == nil {
+			continue
+		}
+		seen[pkg.PkgPath] = true
+		for i, re := range layers {
+			if !re.MatchString(pkg.PkgPath) {
+				continue
+			}
+			scope := pkg.Types.Scope()
+			for _, name := range scope.Names() {
+				if obj, ok := scope.Lookup(name).(*types.TypeName); ok && !obj.IsAlias() {
+					if _, ok := obj.Type().Underlying().(*types.Struct); ok {
+						structs[i] = append(structs[i], obj)
+					}
+				}
+			}
+			break
+		}
+	}
+
+	var pairs []sf.SimilarStructs
+	for _, pair := range sf.FindSimilarStructs(structs, *minOverlap, *minShared) {
+		if !converted[[2]string{typeKey(pair.A), typeKey(pair.B)}] {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs, nil
+}
+
+// typeKey returns the fully qualified name of the named type, or an empty string for nil.
+func typeKey(obj *types.TypeName) string {
+	if obj == nil {
+		return ""
+	}
+	return types.TypeString(obj.Type(), nil)
+}
+
+// writePairs prints the pairs along with their overlap and shared fields.
+func writePairs(w io.Writer, pairs []sf.SimilarStructs) {
+	for _, pair := range pairs {
+		fmt.Fprintf(w, "%s ~ %s: %.0f%% overlap, no converter\n\tshared fields: %s\n",
+			typeKey(pair.A), typeKey(pair.B), pair.Overlap*100, strings.Join(pair.Shared, ", "))
+	}
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// cache stores findings of packages on disk, keyed by contents of their files and of files
+// of their dependencies along with the configuration and the analyzer version,
+// so packages left unchanged since a previous run aren't loaded and analyzed again.
+// Packages are cached by directory: a package and its test variants form a single entry.
+type cache struct {
+	dir string
+	// header is the hash of the analyzer version and the configuration.
+	header []byte
+
+	keys        map[*packages.Package][]byte
+	configFiles map[string][]byte
+}
+
+// cacheEntry holds findings of the packages of a directory.
+type cacheEntry struct {
+	// Files are the names of analyzed files, for the summary.
+	Files    []string       `json:"files"`
+	Findings []cacheFinding `json:"findings"`
+}
+
+type cacheFinding struct {
+	Package        string              `json:"package"`
+	Category       string              `json:"category"`
+	Message        string              `json:"message"`
+	Pos            cachePos            `json:"pos"`
+	End            cachePos            `json:"end"`
+	Related        []cacheRelatedInfo  `json:"related,omitempty"`
+	SuggestedFixes []cacheSuggestedFix `json:"suggested_fixes,omitempty"`
+}
+
+type cacheRelatedInfo struct {
+	Pos     cachePos `json:"pos"`
+	Message string   `json:"message"`
+}
+
+type cacheSuggestedFix struct {
+	Message string          `json:"message"`
+	Edits   []cacheTextEdit `json:"edits"`
+}
+
+type cacheTextEdit struct {
+	Pos     cachePos `json:"pos"`
+	End     cachePos `json:"end"`
+	NewText string   `json:"new"`
+}
+
+// cachePos is a position as an offset in the file. It's valid as long as the file is unchanged,
+// which the cache key guarantees. The zero value stands for token.NoPos.
+type cachePos struct {
+	Filename string `json:"filename,omitempty"`
+	Offset   int    `json:"offset,omitempty"`
+}
+
+// cacheHits are findings and analyzed files of packages found in the cache.
+type cacheHits struct {
+	findings []finding
+	files    []string
+}
+
+// openCache opens the cache in the directory, creating it if needed.
+func openCache(dir string) (*cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	header, err := cacheHeader()
+	if err != nil {
+		return nil, err
+	}
+	return &cache{
+		dir:         dir,
+		header:      header,
+		keys:        make(map[*packages.Package][]byte),
+		configFiles: make(map[string][]byte),
+	}, nil
+}
+
+// defaultCacheDir returns the default directory of the cache within the user's cache directory.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "stickyfields")
+}
+
+// useCache reports whether findings may come from the cache: fixes, the HTML and mapping reports and dead fields
+// need results of the analysis, and messages of the explain mode and higher verbosities
+// aren't cached.
+func useCache() bool {
+	return *cacheFindings && *cacheDir != "" && !*fix && *reportHTML == "" && *reportMapping == "" && !*deadFields &&
+		len(onlyFiles) == 0 && overlay == nil &&
+		config.Explain == "" && config.Verbosity <= sf.VerbositySummary
+}
+
+// cacheHeader hashes the analyzer version (the executable itself, so development builds are told apart)
+// and the configuration affecting findings: options of the analyzer, changed lines of -diff
+// and the baseline.
+func cacheHeader() ([]byte, error) {
+	h := sha256.New()
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	if err := hashFile(h, exe); err != nil {
+		return nil, err
+	}
+
+	analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(h, "%s=%s\n", f.Name, f.Value)
+	})
+	for _, name := range slices.Sorted(maps.Keys(config.Changed)) {
+		fmt.Fprintf(h, "changed %s %v\n", name, config.Changed[name])
+	}
+	if config.Baseline != "" {
+		if err := hashFile(h, config.Baseline); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// hashFile writes the content of the file to the hash.
+func hashFile(h io.Writer, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// lookup loads the packages matching the patterns without their syntax and looks up their findings
+// in the cache. It returns the findings found and keys of the directories of the packages to analyze
+// (patterns of them).
+func (c *cache) lookup(patterns []string) (cacheHits, map[string][]byte, error) {
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps
+	roots, err := packages.Load(&packages.Config{Mode: mode, Tests: config.IncludeTests}, patterns...)
+	if err != nil {
+		return cacheHits{}, nil, err
+	}
+
+	// Test variants share the directory of the package, test executables are generated.
+	byDir := make(map[string][]*packages.Package)
+	for _, pkg := range roots {
+		if strings.HasSuffix(pkg.ID, ".test") || len(pkg.GoFiles) == 0 {
+			continue
+		}
+		dir := filepath.Dir(pkg.GoFiles[0])
+		byDir[dir] = append(byDir[dir], pkg)
+	}
+
+	var hits cacheHits
+	misses := make(map[string][]byte)
+	fset := newCacheFileSet()
+	for dir, pkgs := range byDir {
+		key, err := c.dirKey(pkgs)
+		if err != nil {
+			return cacheHits{}, nil, err
+		}
+		entry, ok := c.get(key)
+		if !ok {
+			misses[dir] = key
+			continue
+		}
+		findings, err := fset.findings(entry)
+		if err != nil {
+			// Files changed meanwhile.
+			misses[dir] = key
+			continue
+		}
+		hits.findings = append(hits.findings, findings...)
+		hits.files = append(hits.files, entry.Files...)
+	}
+	return hits, misses, nil
+}
+
+// store puts findings and analyzed files of the analyzed packages into the cache.
+func (c *cache) store(misses map[string][]byte, graph *checker.Graph, findings []finding) error {
+	entries := make(map[string]*cacheEntry, len(misses))
+	for dir := range misses {
+		entries[dir] = &cacheEntry{Findings: []cacheFinding{}}
+	}
+	for _, name := range analyzedFiles(graph) {
+		if entry, ok := entries[filepath.Dir(name)]; ok {
+			entry.Files = append(entry.Files, name)
+		}
+	}
+	for _, f := range findings {
+		if entry, ok := entries[filepath.Dir(f.pos.Filename)]; ok {
+			entry.Findings = append(entry.Findings, newCacheFinding(f))
+		}
+	}
+
+	var errs []error
+	for dir, entry := range entries {
+		errs = append(errs, c.put(misses[dir], entry))
+	}
+	return errors.Join(errs...)
+}
+
+// get returns the entry of the key, if any.
+func (c *cache) get(key []byte) (*cacheEntry, bool) {
+	data, err := os.ReadFile(c.filename(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// put writes the entry of the key. Entries are replaced atomically, as concurrent runs may share the cache.
+func (c *cache) put(key []byte, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.dir, "entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), c.filename(key))
+}
+
+func (c *cache) filename(key []byte) string {
+	return filepath.Join(c.dir, hex.EncodeToString(key))
+}
+
+// dirKey returns the key of the packages of a directory.
+func (c *cache) dirKey(pkgs []*packages.Package) ([]byte, error) {
+	h := sha256.New()
+	h.Write(c.header)
+	for _, pkg := range slices.SortedFunc(slices.Values(pkgs), func(a, b *packages.Package) int {
+		return cmp.Compare(a.ID, b.ID)
+	}) {
+		key, err := c.key(pkg)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(h, "%s %x\n", pkg.ID, key)
+	}
+	return h.Sum(nil), nil
+}
+
+// key returns the key of the package: the hash of its files, configuration files applying to them
+// and keys of its imports. Files of the standard library and of the module cache don't change
+// in place, so their names and modification times are hashed instead of their contents.
+func (c *cache) key(pkg *packages.Package) ([]byte, error) {
+	if key, ok := c.keys[pkg]; ok {
+		return key, nil
+	}
+
+	h := sha256.New()
+	fmt.Fprintln(h, pkg.PkgPath)
+	for _, name := range pkg.GoFiles {
+		fmt.Fprintln(h, name)
+		if dir := filepath.Dir(name); isReadOnly(dir) {
+			info, err := os.Stat(name)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintln(h, info.Size(), info.ModTime().UnixNano())
+			continue
+		}
+		if err := hashFile(h, name); err != nil {
+			return nil, err
+		}
+	}
+	if len(pkg.GoFiles) > 0 && !isReadOnly(filepath.Dir(pkg.GoFiles[0])) {
+		key, err := c.configFilesKey(filepath.Dir(pkg.GoFiles[0]))
+		if err != nil {
+			return nil, err
+		}
+		h.Write(key)
+	}
+	for _, path := range slices.Sorted(maps.Keys(pkg.Imports)) {
+		key, err := c.key(pkg.Imports[path])
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(h, "%s %x\n", path, key)
+	}
+
+	key := h.Sum(nil)
+	c.keys[pkg] = key
+	return key, nil
+}
+
+// configFilesKey returns the hash of configuration files of the directory and its parents
+// up to the module root, which apply to packages of the directory.
+func (c *cache) configFilesKey(dir string) ([]byte, error) {
+	if key, ok := c.configFiles[dir]; ok {
+		return key, nil
+	}
+
+	h := sha256.New()
+	for _, name := range sf.ConfigFileNames {
+		err := hashFile(h, filepath.Join(dir, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		if parent := filepath.Dir(dir); parent != dir {
+			key, err := c.configFilesKey(parent)
+			if err != nil {
+				return nil, err
+			}
+			h.Write(key)
+		}
+	}
+
+	key := h.Sum(nil)
+	c.configFiles[dir] = key
+	return key, nil
+}
+
+// newCacheFinding returns the finding in the cached form.
+func newCacheFinding(f finding) cacheFinding {
+	pos := func(p token.Pos) cachePos {
+		if !p.IsValid() {
+			return cachePos{}
+		}
+		file := f.fset.File(p)
+		return cachePos{Filename: file.Name(), Offset: file.Offset(p)}
+	}
+
+	cf := cacheFinding{
+		Package:  f.pkg,
+		Category: f.Category,
+		Message:  f.Message,
+		Pos:      pos(f.Pos),
+		End:      pos(f.End),
+	}
+	for _, related := range f.Related {
+		cf.Related = append(cf.Related, cacheRelatedInfo{Pos: pos(related.Pos), Message: related.Message})
+	}
+	for _, fix := range f.SuggestedFixes {
+		cfix := cacheSuggestedFix{Message: fix.Message}
+		for _, edit := range fix.TextEdits {
+			cfix.Edits = append(cfix.Edits, cacheTextEdit{Pos: pos(edit.Pos), End: pos(edit.End), NewText: string(edit.NewText)})
+		}
+		cf.SuggestedFixes = append(cf.SuggestedFixes, cfix)
+	}
+	return cf
+}
+
+// cacheFileSet turns cached positions back into positions of files added on demand.
+type cacheFileSet struct {
+	fset  *token.FileSet
+	files map[string]*token.File
+}
+
+func newCacheFileSet() *cacheFileSet {
+	return &cacheFileSet{fset: token.NewFileSet(), files: make(map[string]*token.File)}
+}
+
+// pos returns the position of the cached one.
+func (s *cacheFileSet) pos(p cachePos) (token.Pos, error) {
+	if p.Filename == "" {
+		return token.NoPos, nil
+	}
+	file, ok := s.files[p.Filename]
+	if !ok {
+		content, err := os.ReadFile(p.Filename)
+		if err != nil {
+			return token.NoPos, err
+		}
+		file = s.fset.AddFile(p.Filename, -1, len(content))
+		file.SetLinesForContent(content)
+		s.files[p.Filename] = file
+	}
+	if p.Offset > file.Size() {
+		return token.NoPos, fmt.Errorf("%s: offset %d beyond the end of the file", p.Filename, p.Offset)
+	}
+	return file.Pos(p.Offset), nil
+}
+
+// findings returns findings of the entry.
+func (s *cacheFileSet) findings(entry *cacheEntry) ([]finding, error) {
+	var errs []error
+	pos := func(p cachePos) token.Pos {
+		pos, err := s.pos(p)
+		errs = append(errs, err)
+		return pos
+	}
+
+	findings := make([]finding, 0, len(entry.Findings))
+	for _, cf := range entry.Findings {
+		d := analysis.Diagnostic{
+			Pos:      pos(cf.Pos),
+			End:      pos(cf.End),
+			Category: cf.Category,
+			Message:  cf.Message,
+		}
+		for _, related := range cf.Related {
+			d.Related = append(d.Related, analysis.RelatedInformation{Pos: pos(related.Pos), Message: related.Message})
+		}
+		for _, cfix := range cf.SuggestedFixes {
+			fix := analysis.SuggestedFix{Message: cfix.Message}
+			for _, edit := range cfix.Edits {
+				fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{
+					Pos:     pos(edit.Pos),
+					End:     pos(edit.End),
+					NewText: []byte(edit.NewText),
+				})
+			}
+			d.SuggestedFixes = append(d.SuggestedFixes, fix)
+		}
+		findings = append(findings, finding{
+			Diagnostic: d,
+			fset:       s.fset,
+			pos:        s.fset.Position(d.Pos),
+			pkg:        cf.Package,
+			severity:   config.Severity(cf.Category),
+		})
+	}
+	return findings, errors.Join(errs...)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+func TestCacheHeader(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(baseline, []byte(`{"findings": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(name string, changed sf.ChangedLines) {
+		config.Baseline, config.Changed = name, changed
+	}(config.Baseline, config.Changed)
+	config.Baseline = baseline
+	config.Changed = sf.ChangedLines{"/repo/conv.go": {{Start: 3, End: 5}}}
+
+	header := func() []byte {
+		t.Helper()
+		h, err := cacheHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	base := header()
+	if !bytes.Equal(header(), base) {
+		t.Fatal("header of the same configuration changed")
+	}
+
+	// Each change of the configuration invalidates findings cached with the base one.
+	tests := []struct {
+		name   string
+		change func() (undo func())
+	}{
+		{"analyzer flag", func() func() {
+			old := analyzer.Flags.Lookup("report-hardcoded").Value.String()
+			if err := analyzer.Flags.Set("report-hardcoded", strconv.FormatBool(old != "true")); err != nil {
+				t.Fatal(err)
+			}
+			return func() { analyzer.Flags.Set("report-hardcoded", old) }
+		}},
+		{"tests", func() func() {
+			config.IncludeTests = !config.IncludeTests
+			return func() { config.IncludeTests = !config.IncludeTests }
+		}},
+		{"changed lines", func() func() {
+			config.Changed = sf.ChangedLines{"/repo/conv.go": {{Start: 3, End: 6}}}
+			return func() { config.Changed = sf.ChangedLines{"/repo/conv.go": {{Start: 3, End: 5}}} }
+		}},
+		{"changed file", func() func() {
+			config.Changed = sf.ChangedLines{"/repo/user.go": {{Start: 3, End: 5}}}
+			return func() { config.Changed = sf.ChangedLines{"/repo/conv.go": {{Start: 3, End: 5}}} }
+		}},
+		{"no changed lines", func() func() {
+			config.Changed = nil
+			return func() { config.Changed = sf.ChangedLines{"/repo/conv.go": {{Start: 3, End: 5}}} }
+		}},
+		{"baseline content", func() func() {
+			if err := os.WriteFile(baseline, []byte(`{"findings": [{"code": "SF001"}]}`), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			return func() { os.WriteFile(baseline, []byte(`{"findings": []}`), 0o644) }
+		}},
+		{"missing baseline", func() func() {
+			config.Baseline = baseline + ".missing"
+			return func() { config.Baseline = baseline }
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			undo := test.change()
+			changed := header()
+			undo()
+			if bytes.Equal(changed, base) {
+				t.Error("header didn't change")
+			}
+			if !bytes.Equal(header(), base) {
+				t.Error("header didn't change back")
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// deadFieldFindings returns fields of models mapped by none of the converters of the root packages
+// of the graph (see sf.DeadFields) as findings. They're attributed to the package declaring the model
+// if it's a root package, to the first root package otherwise.
+func deadFieldFindings(graph *checker.Graph) []finding {
+	if graph == nil || len(graph.Roots) == 0 || slices.Contains(config.Disable, sf.CodeDeadField) {
+		return nil
+	}
+
+	var results []*sf.Result
+	roots := make(map[string]*packages.Package)
+	for _, act := range graph.Roots {
+		result, ok := act.Result.(*sf.Result)
+		if !ok || act.Err != nil {
+			continue
+		}
+		results = append(results, result)
+		if _, ok := roots[act.Package.PkgPath]; !ok {
+			roots[act.Package.PkgPath] = act.Package
+		}
+	}
+
+	var findings []finding
+	for _, dead := range sf.DeadFields(results) {
+		pkg := graph.Roots[0].Package
+		if root, ok := roots[dead.Model[:strings.LastIndex(dead.Model, ".")]]; ok {
+			pkg = root
+		}
+		findings = append(findings, newFinding(pkg, analysis.Diagnostic{
+			Pos:      dead.Pos,
+			Category: sf.CodeDeadField,
+			Message: fmt.Sprintf("%s: field %s of %s is mapped by none of its converters",
+				sf.CodeDeadField, dead.Field, dead.Model),
+		}))
+	}
+	return findings
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// changedLines returns the lines of Go files changed since the merge base of HEAD and the git ref
+// (e.g. origin/main), including uncommitted changes.
+func changedLines(ref string) (sf.ChangedLines, error) {
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	// Prefixes are explicit, as diff.noprefix or diff.mnemonicPrefix of the git config change them.
+	diff, err := git("diff", "--merge-base", ref, "-U0", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	return sf.ParseDiff(strings.NewReader(diff), strings.TrimSpace(root))
+}
+
+// git runs the git command and returns its output.
+func git(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
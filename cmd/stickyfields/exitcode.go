@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// exitFindings is the exit code of runs failed by findings.
+const exitFindings = 3
+
+// failOn lists codes and severities of findings failing the run. Findings of the info severity
+// don't fail it by default.
+var failOn = sf.StringList{sf.SeverityWarning, sf.SeverityError}
+
+// checkFailOn checks entries of -fail-on are known codes or severities, normalizing codes to upper case.
+func checkFailOn() error {
+	for i, entry := range failOn {
+		switch entry {
+		case sf.SeverityError, sf.SeverityWarning, sf.SeverityInfo:
+			continue
+		}
+		code := strings.ToUpper(entry)
+		if _, ok := sf.CodeDocs[code]; !ok {
+			return fmt.Errorf("-fail-on: unknown code or severity %q", entry)
+		}
+		failOn[i] = code
+	}
+	return nil
+}
+
+// fails reports whether the findings fail the run: findings matching -fail-on by their code
+// or severity outnumber -fail-threshold.
+func fails(findings []finding) bool {
+	n := 0
+	for _, f := range findings {
+		if f.Category == sf.CategoryOmitted {
+			continue
+		}
+		if slices.Contains(failOn, f.Category) || slices.Contains(failOn, f.severity) {
+			n++
+		}
+	}
+	return n > *failThreshold
+}
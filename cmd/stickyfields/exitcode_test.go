@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"log"
+	"slices"
+	"testing"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+func TestExitCode(t *testing.T) {
+	defer func(f string, codes sf.StringList, threshold, maxIssues int, cache bool) {
+		*format, failOn, *failThreshold, config.MaxIssues, *cacheFindings = f, codes, threshold, maxIssues, cache
+	}(*format, failOn, *failThreshold, config.MaxIssues, *cacheFindings)
+	*cacheFindings = false
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+
+	// Both converters of the conv package leak fields (SF001 errors).
+	tests := []struct {
+		name      string
+		pkg       string
+		format    string
+		failOn    sf.StringList
+		threshold int
+		maxIssues int
+		want      int
+	}{
+		{name: "findings", pkg: "conv", want: exitFindings},
+		{name: "annotations", pkg: "conv", format: formatGitHub, want: exitFindings},
+		{name: "json", pkg: "conv", format: formatJSON, want: 0},
+		{name: "sarif", pkg: "conv", format: formatSARIF, want: 0},
+		{name: "code", pkg: "conv", failOn: sf.StringList{"sf001"}, want: exitFindings},
+		{name: "other code", pkg: "conv", failOn: sf.StringList{"SF005"}, want: 0},
+		{name: "other severity", pkg: "conv", failOn: sf.StringList{sf.SeverityInfo}, want: 0},
+		{name: "below threshold", pkg: "conv", threshold: 1, want: exitFindings},
+		{name: "at threshold", pkg: "conv", threshold: 2, want: 0},
+		// The number of omitted findings isn't a finding of its own.
+		{name: "omitted findings", pkg: "conv", threshold: 1, maxIssues: 1, want: 0},
+		{name: "load error", pkg: "broken", want: 1},
+		{name: "load error below threshold", pkg: "broken", threshold: 2, want: 1},
+		{name: "load error as json", pkg: "broken", format: formatJSON, want: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			*format = formatText
+			if test.format != "" {
+				*format = test.format
+			}
+			failOn = sf.StringList{sf.SeverityWarning, sf.SeverityError}
+			if test.failOn != nil {
+				failOn = append(sf.StringList(nil), test.failOn...)
+			}
+			if err := checkFailOn(); err != nil {
+				t.Fatal(err)
+			}
+			*failThreshold = test.threshold
+			config.MaxIssues = test.maxIssues
+
+			if got := run(io.Discard, []string{"./testdata/src/" + test.pkg}); got != test.want {
+				t.Errorf("run() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckFailOn(t *testing.T) {
+	defer func(codes sf.StringList) { failOn = codes }(failOn)
+
+	tests := []struct {
+		in      sf.StringList
+		want    sf.StringList
+		wantErr bool
+	}{
+		{in: sf.StringList{"error", "sf005"}, want: sf.StringList{"error", "SF005"}},
+		{in: sf.StringList{"info"}, want: sf.StringList{"info"}},
+		{in: sf.StringList{"SF999"}, wantErr: true},
+		{in: sf.StringList{"fatal"}, wantErr: true},
+	}
+	for _, test := range tests {
+		failOn = append(sf.StringList(nil), test.in...)
+		err := checkFailOn()
+		if (err != nil) != test.wantErr {
+			t.Errorf("checkFailOn(%v) error = %v, want error %t", test.in, err, test.wantErr)
+			continue
+		}
+		if err == nil && !slices.Equal(failOn, test.want) {
+			t.Errorf("checkFailOn(%v) normalized to %v, want %v", test.in, failOn, test.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// onlyFiles are absolute names of files given instead of packages: findings of these files are reported only.
+	onlyFiles []string
+	// overlay holds the source read from stdin for -stdin-filename.
+	overlay map[string][]byte
+)
+
+// filePatterns prepares the analysis of single files, e.g. for editors and pre-commit hooks:
+// .go files are turned into queries of the packages containing them (so their types are known),
+// and the source of -stdin-filename is read from stdin. It returns the patterns to load.
+func filePatterns(patterns []string) ([]string, error) {
+	if *stdinFilename != "" {
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		abs, err := filepath.Abs(*stdinFilename)
+		if err != nil {
+			return nil, err
+		}
+		overlay = map[string][]byte{abs: src}
+		patterns = append(patterns, abs)
+	}
+
+	var queries []string
+	for _, pattern := range patterns {
+		if !strings.HasSuffix(pattern, ".go") {
+			continue
+		}
+		abs, err := filepath.Abs(pattern)
+		if err != nil {
+			return nil, err
+		}
+		onlyFiles = append(onlyFiles, abs)
+		queries = append(queries, "file="+abs)
+	}
+	if len(queries) == 0 {
+		return patterns, nil
+	}
+	if len(queries) != len(patterns) {
+		return nil, errors.New("files and packages cannot be analyzed together")
+	}
+	return queries, nil
+}
+
+// fileFindings returns the findings of the files given instead of packages, if any.
+func fileFindings(findings []finding) []finding {
+	if len(onlyFiles) == 0 {
+		return findings
+	}
+	return slices.DeleteFunc(findings, func(f finding) bool {
+		return !reportedFile(f.pos.Filename)
+	})
+}
+
+// reportedFile reports whether findings of the file are reported: all files are unless files
+// were given instead of packages.
+func reportedFile(filename string) bool {
+	return len(onlyFiles) == 0 || slices.Contains(onlyFiles, filename)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCollectFindings(t *testing.T) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: true}, "./testdata/src/conv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) < 2 {
+		t.Fatalf("got %d packages, want the package along with its test variant", len(pkgs))
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := collectFindings(graph)
+	if len(findings) == 0 {
+		t.Fatal("no findings")
+	}
+	var reported int
+	for _, act := range graph.Roots {
+		reported += len(act.Diagnostics)
+	}
+	if reported <= len(findings) {
+		t.Errorf("got %d findings of %d diagnostics, want diagnostics of the test variant deduplicated", len(findings), reported)
+	}
+	for i, f := range findings {
+		// Findings of files of both the package and its test variant are attributed to the package.
+		if f.pkg != pkgs[0].ID {
+			t.Errorf("finding %q of package %s, want %s", f.Message, f.pkg, pkgs[0].ID)
+		}
+		if filepath.Base(f.pos.Filename) != "conv.go" {
+			t.Errorf("finding %q of file %s", f.Message, f.pos.Filename)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := findings[i-1]
+		if prev.key() == f.key() {
+			t.Errorf("duplicate finding %s: %q", f.pos, f.Message)
+		}
+		if f.pos.Line < prev.pos.Line {
+			t.Errorf("finding of line %d follows one of line %d", f.pos.Line, prev.pos.Line)
+		}
+	}
+}
+
+func TestSortFindings(t *testing.T) {
+	findings := testFindings(t)
+	reversed := make([]finding, len(findings))
+	for i, f := range findings {
+		reversed[len(findings)-1-i] = f
+	}
+	sortFindings(reversed)
+	for i := range findings {
+		if reversed[i].key() != findings[i].key() {
+			t.Errorf("finding %d is %s %s, want %s %s",
+				i, reversed[i].pos, reversed[i].Category, findings[i].pos, findings[i].Category)
+		}
+	}
+	// Findings of the same position are ordered by code.
+	if findings[0].Category > findings[1].Category || findings[0].pos != findings[1].pos {
+		t.Errorf("findings of %s are not ordered by code: %s, %s", findings[0].pos, findings[0].Category, findings[1].Category)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// edit is a text edit resolved to byte offsets of a file.
+type edit struct {
+	start, end int
+	text       []byte
+}
+
+// applyFixes applies the first suggested fix of every finding.
+// Identical edits (e.g. of files analyzed as part of foo and foo.test) are applied once,
+// edits overlapping previous ones are dropped.
+func applyFixes(findings []finding) error {
+	edits := make(map[string][]edit)
+	var files []string
+	for _, f := range findings {
+		if len(f.SuggestedFixes) == 0 {
+			continue
+		}
+		for _, te := range f.SuggestedFixes[0].TextEdits {
+			file := f.fset.File(te.Pos)
+			end := te.End
+			if !end.IsValid() {
+				end = te.Pos
+			}
+			name := file.Name()
+			if _, ok := edits[name]; !ok {
+				files = append(files, name)
+			}
+			edits[name] = append(edits[name], edit{start: file.Offset(te.Pos), end: file.Offset(end), text: te.NewText})
+		}
+	}
+
+	for _, name := range files {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		fixed, err := applyEdits(content, edits[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.WriteFile(name, fixed, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEdits returns the content with the edits applied.
+func applyEdits(content []byte, edits []edit) ([]byte, error) {
+	slices.SortStableFunc(edits, func(a, b edit) int {
+		return cmp.Or(cmp.Compare(a.start, b.start), cmp.Compare(a.end, b.end))
+	})
+
+	var buf bytes.Buffer
+	last := 0
+	var prev *edit
+	for i := range edits {
+		e := &edits[i]
+		if e.start < 0 || e.end > len(content) || e.start > e.end {
+			return nil, fmt.Errorf("invalid edit range [%d, %d)", e.start, e.end)
+		}
+		if prev != nil && e.start == prev.start && e.end == prev.end && bytes.Equal(e.text, prev.text) {
+			continue
+		}
+		if e.start < last {
+			continue
+		}
+		buf.Write(content[last:e.start])
+		buf.Write(e.text)
+		last = e.end
+		prev = e
+	}
+	buf.Write(content[last:])
+	return buf.Bytes(), nil
+}
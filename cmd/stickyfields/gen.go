@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	gofmt "go/format"
+	"go/types"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// genLoadMode loads types of packages along with their dependencies for the gen subcommand.
+const genLoadMode = packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps
+
+// runGen implements the gen subcommand: it writes a converter function between two structs
+// (see sf.GenerateConverter) along with the package clause and imports it needs, e.g.
+//
+//	stickyfields gen -from model.Sample -to dbmodel.Sample -o conv/sample.go
+//
+// Types are given as package.Type, where package is either an import path or the name
+// of a package of the module in the current directory.
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	from := fs.String("from", "", "input struct of the converter (package.Type)")
+	to := fs.String("to", "", "output struct of the converter (package.Type)")
+	name := fs.String("name", "", "name of the converter (default: To followed by the output type)")
+	pkgName := fs.String("package", "", "package of the generated file (default: the package in the output directory)")
+	output := fs.String("o", "", "file to write the converter to (default: stdout)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stickyfields gen -from package.Type -to package.Type [-flag]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	dir := "."
+	if *output != "" {
+		dir = filepath.Dir(*output)
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: genLoadMode}, "./...")
+	if err != nil {
+		return err
+	}
+	in, err := lookupType(pkgs, *from)
+	if err != nil {
+		return err
+	}
+	out, err := lookupType(pkgs, *to)
+	if err != nil {
+		return err
+	}
+
+	target, err := targetPackage(dir, *pkgName)
+	if err != nil {
+		return err
+	}
+	imports := make(map[string]string)
+	qualifier := func(pkg *types.Package) string {
+		if pkg.Path() == target.PkgPath {
+			return ""
+		}
+		imports[pkg.Path()] = pkg.Name()
+		return pkg.Name()
+	}
+	converter, err := sf.GenerateConverter(*name, in, out, qualifier)
+	if err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", target.Name)
+	if len(imports) > 0 {
+		src.WriteString("import (\n")
+		for _, path := range slices.Sorted(maps.Keys(imports)) {
+			fmt.Fprintf(&src, "\t%q\n", path)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.WriteString(converter)
+	formatted, err := gofmt.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting the converter: %w", err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(formatted)
+		return err
+	}
+	if _, err := os.Stat(*output); err == nil {
+		return fmt.Errorf("%s already exists", *output)
+	}
+	return os.WriteFile(*output, formatted, 0o644)
+}
+
+// lookupType returns the named type of the spec (package.Type). Packages given by name rather than
+// import path are looked up among the packages and their dependencies, they must be unambiguous.
+func lookupType(pkgs []*packages.Package, spec string) (*types.TypeName, error) {
+	dot := strings.LastIndex(spec, ".")
+	if dot <= 0 || dot == len(spec)-1 {
+		return nil, fmt.Errorf("invalid type %q: expected package.Type", spec)
+	}
+	pkgSpec, typeName := spec[:dot], spec[dot+1:]
+
+	var found []*types.Package
+	if strings.Contains(pkgSpec, "/") {
+		loaded, err := packages.Load(&packages.Config{Mode: genLoadMode}, pkgSpec)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range loaded {
+			if pkg.Types != nil && len(pkg.Errors) == 0 {
+				found = append(found, pkg.Types)
+			}
+		}
+	} else {
+		// Packages imported by the matched ones count too, e.g. models of other modules.
+		packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+			if pkg.Types != nil && pkg.Name == pkgSpec {
+				found = append(found, pkg.Types)
+			}
+		})
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("package %s of %s not found", pkgSpec, spec)
+	case 1:
+	default:
+		paths := make([]string, len(found))
+		for i, pkg := range found {
+			paths[i] = pkg.Path()
+		}
+		return nil, fmt.Errorf("package %s of %s is ambiguous: use one of %s", pkgSpec, spec, strings.Join(paths, ", "))
+	}
+
+	obj, ok := found[0].Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, found[0].Path())
+	}
+	return obj, nil
+}
+
+// targetPackage returns the package in the directory the converter is generated into.
+// The name is taken from -package if the directory has no package yet.
+func targetPackage(dir, name string) (*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: dir}, ".")
+	if err == nil && len(pkgs) == 1 && pkgs[0].Name != "" {
+		if name != "" && name != pkgs[0].Name {
+			return nil, fmt.Errorf("-package %s differs from package %s in %s", name, pkgs[0].Name, dir)
+		}
+		return pkgs[0], nil
+	}
+	if name == "" {
+		return nil, errors.New("no package found in " + dir + ": use -package")
+	}
+	target := &packages.Package{Name: name}
+	if err == nil && len(pkgs) == 1 {
+		target.PkgPath = pkgs[0].PkgPath
+	}
+	return target, nil
+}
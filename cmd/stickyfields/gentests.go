@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// runGenTests implements the gen-tests subcommand: it writes a test file into every package of the patterns
+// with converters, checking at run time that they set their output fields (see sf.GenerateTests), e.g.
+//
+//	stickyfields gen-tests ./convert/...
+//
+// Converters are detected like the analyzer does, with the same options. Files written before are
+// regenerated, but hand-written files of the same name are left alone.
+func runGenTests(args []string) error {
+	fs := flag.NewFlagSet("gen-tests", flag.ExitOnError)
+	file := fs.String("file", "stickyfields_converters_test.go", "name of the test files written into the packages")
+	stdout := fs.Bool("stdout", false, "print the test files rather than writing them")
+	cfg := sf.DefaultConfig()
+	cfg.RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: stickyfields gen-tests [-flag] [package]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax}, patterns...)
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("loading packages failed")
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{sf.NewAnalyzer(cfg)}, pkgs, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, act := range graph.Roots {
+		result, ok := act.Result.(*sf.Result)
+		if !ok || act.Err != nil || len(act.Package.GoFiles) == 0 {
+			continue
+		}
+		src, skipped, err := sf.GenerateTests(act.Package.Types, result)
+		if err != nil {
+			return err
+		}
+		for _, s := range skipped {
+			fmt.Fprintf(os.Stderr, "%s: skipped %s\n", act.Package.PkgPath, s)
+		}
+		if src == nil {
+			continue
+		}
+
+		if *stdout {
+			os.Stdout.Write(src)
+			continue
+		}
+		filename := filepath.Join(filepath.Dir(act.Package.GoFiles[0]), *file)
+		if existing, err := os.ReadFile(filename); err == nil && !bytes.HasPrefix(existing, []byte(sf.GeneratedTestsHeader)) {
+			return fmt.Errorf("%s exists and is not generated: use -file", filename)
+		}
+		if err := os.WriteFile(filename, src, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "wrote %s\n", filename)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// githubEscaper escapes data of GitHub Actions workflow commands.
+var githubEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// githubPropertyEscaper escapes property values of GitHub Actions workflow commands.
+var githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+// githubCommand returns the workflow command annotating findings of the severity.
+func githubCommand(severity string) string {
+	switch severity {
+	case sf.SeverityError:
+		return "error"
+	case sf.SeverityInfo:
+		return "notice"
+	}
+	return "warning"
+}
+
+// writeGitHub prints findings as GitHub Actions workflow commands,
+// so they appear as annotations of the pull request's changes.
+func writeGitHub(w io.Writer, findings []finding) {
+	wd, _ := os.Getwd()
+	for _, f := range findings {
+		filename := f.pos.Filename
+		if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = filepath.ToSlash(rel)
+		}
+
+		fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d,title=%s::%s\n",
+			githubCommand(f.severity), githubPropertyEscaper.Replace(filename), f.pos.Line, f.pos.Column,
+			githubPropertyEscaper.Replace("stickyfields: "+f.Category),
+			githubEscaper.Replace(f.Message))
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	writeGitHub(&buf, testFindings(t))
+	// Each finding is a single command line: newlines of messages are escaped.
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("got %d lines of workflow commands, want 3", lines)
+	}
+	checkGolden(t, "github.golden", buf.Bytes())
+}
+
+func TestGitHubEscaper(t *testing.T) {
+	tests := []struct {
+		name     string
+		escaper  interface{ Replace(string) string }
+		in, want string
+	}{
+		{"message", githubEscaper, "100% sure: a,b\r\nc", "100%25 sure: a,b%0D%0Ac"},
+		{"property", githubPropertyEscaper, "100% sure: a,b\r\nc", "100%25 sure%3A a%2Cb%0D%0Ac"},
+		{"plain", githubPropertyEscaper, "conv/user.go", "conv/user.go"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.escaper.Replace(test.in); got != test.want {
+				t.Errorf("Replace(%q) = %q, want %q", test.in, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// htmlPackage is a package listed in the HTML report.
+type htmlPackage struct {
+	Path       string
+	Converters []htmlConverter
+}
+
+// htmlConverter is a converter listed in the HTML report.
+type htmlConverter struct {
+	sf.Converter
+	// Position is the position of the converter, URL links to its source file.
+	Position string
+	URL      template.URL
+	Fields   []htmlField
+
+	pos token.Position
+}
+
+// htmlField is a row of the coverage table of a converter.
+type htmlField struct {
+	Name      string
+	Direction string
+	Missing   bool
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>stickyfields report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: left; }
+.missing { background: #fdd; color: #a00; font-weight: bold; }
+.leaking { color: #a00; }
+code { font-size: 1.1em; }
+</style>
+</head>
+<body>
+<h1>stickyfields report</h1>
+{{range .}}
+<h2>{{.Path}}</h2>
+{{range .Converters}}
+<h3><a href="{{.URL}}"><code>{{.Name}}</code></a>{{if not .Valid}} <span class="leaking">leaking</span>{{end}}</h3>
+<p>
+<code>{{.InputType}}</code> &rarr; <code>{{.OutputType}}</code>,
+coverage: {{printf "%.0f%%" .Percent}}{{if .UnknownCoverage}} (unknown: {{.UnknownCoverage}}){{end}},
+<a href="{{.URL}}">{{.Position}}</a>
+</p>
+{{if .Fields}}
+<table>
+<tr><th>Field</th><th>Direction</th><th>Status</th></tr>
+{{range .Fields}}
+<tr{{if .Missing}} class="missing"{{end}}><td><code>{{.Name}}</code></td><td>{{.Direction}}</td><td>{{if .Missing}}missing{{else}}mapped{{end}}</td></tr>
+{{end}}
+</table>
+{{end}}
+{{end}}
+{{else}}
+<p>No converters found.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// Percent returns the field coverage of the converter in percents.
+func (c htmlConverter) Percent() float64 {
+	return c.Coverage * 100
+}
+
+// writeHTMLReport writes the HTML report of converters of the root packages to the file.
+func writeHTMLReport(filename string, graph *checker.Graph) error {
+	byPath := make(map[string]*htmlPackage)
+	seen := make(map[string]bool)
+	for _, act := range graph.Roots {
+		result, ok := act.Result.(*sf.Result)
+		if !ok || act.Err != nil {
+			continue
+		}
+		for _, c := range result.Converters {
+			pos := act.Package.Fset.Position(c.Pos)
+			// Files of foo are analyzed as part of foo.test too.
+			if seen[pos.String()] {
+				continue
+			}
+			seen[pos.String()] = true
+
+			pkg, ok := byPath[act.Package.PkgPath]
+			if !ok {
+				pkg = &htmlPackage{Path: act.Package.PkgPath}
+				byPath[act.Package.PkgPath] = pkg
+			}
+			abs, err := filepath.Abs(pos.Filename)
+			if err != nil {
+				abs = pos.Filename
+			}
+			pkg.Converters = append(pkg.Converters, htmlConverter{
+				Converter: c,
+				Position:  pos.String(),
+				pos:       pos,
+				URL:       template.URL((&url.URL{Scheme: "file", Path: filepath.ToSlash(abs), Fragment: fmt.Sprintf("L%d", pos.Line)}).String()),
+				Fields:    htmlFields(c.ConverterValidationResult),
+			})
+		}
+	}
+
+	pkgs := make([]*htmlPackage, 0, len(byPath))
+	for _, pkg := range byPath {
+		slices.SortFunc(pkg.Converters, func(a, b htmlConverter) int {
+			return comparePositions(a.pos, b.pos)
+		})
+		pkgs = append(pkgs, pkg)
+	}
+	slices.SortFunc(pkgs, func(a, b *htmlPackage) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := htmlReport.Execute(f, pkgs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// htmlFields returns the coverage table rows of the converter.
+func htmlFields(result sf.ConverterValidationResult) []htmlField {
+	var fields []htmlField
+	add := func(direction string, names, missing []string) {
+		for _, name := range names {
+			fields = append(fields, htmlField{Name: name, Direction: direction, Missing: slices.Contains(missing, name)})
+		}
+	}
+	add("input", result.InputFields, result.MissingInputFields)
+	add("output", result.OutputFields, result.MissingOutputFields)
+	return fields
+}
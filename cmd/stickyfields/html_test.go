@@ -0,0 +1,87 @@
+package main
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	// Characters of file names are escaped in links, the ones of messages in the text.
+	conv := fset.AddFile(filepath.Join(wd, "conv", "user & order.go"), -1, 1000)
+	conv.SetLines([]int{0, 100, 200, 300})
+	dto := fset.AddFile(filepath.Join(wd, "dto", "dto.go"), -1, 1000)
+	dto.SetLines([]int{0, 100, 200})
+
+	root := func(path string, converters ...sf.Converter) *checker.Action {
+		return &checker.Action{
+			Analyzer: analyzer,
+			Package:  &packages.Package{ID: path, PkgPath: path, Fset: fset},
+			IsRoot:   true,
+			Result:   &sf.Result{Package: path, Converters: converters},
+		}
+	}
+	graph := &checker.Graph{Roots: []*checker.Action{
+		// Packages are listed by their path and converters by their position, whatever the order of analysis.
+		root("example.com/dto", sf.Converter{
+			Name: "ToUserDTO",
+			Pos:  dto.LineStart(2) + 5,
+			ConverterValidationResult: sf.ConverterValidationResult{
+				Valid:        true,
+				Coverage:     1,
+				InputType:    "conv.User",
+				OutputType:   "dto.UserDTO",
+				InputFields:  []string{"ID"},
+				OutputFields: []string{"ID"},
+			},
+		}),
+		root("example.com/conv",
+			sf.Converter{
+				Name: "ToOrderRow",
+				Pos:  conv.LineStart(3) + 5,
+				ConverterValidationResult: sf.ConverterValidationResult{
+					Valid:           true,
+					Coverage:        1,
+					InputType:       "conv.Order",
+					OutputType:      "conv.OrderRow",
+					UnknownCoverage: `copier.Copy(&row, <-orders)`,
+				},
+			},
+			sf.Converter{
+				Name: "ToUserRow",
+				Pos:  conv.LineStart(1) + 5,
+				ConverterValidationResult: sf.ConverterValidationResult{
+					MissingInputFields:  []string{"Email"},
+					MissingOutputFields: []string{"Name"},
+					Coverage:            0.5,
+					InputType:           "map[string]*conv.User",
+					OutputType:          "conv.UserRow",
+					InputFields:         []string{"ID", "Email"},
+					OutputFields:        []string{"ID", "Name"},
+				},
+			},
+		),
+		root("example.com/empty"),
+	}}
+
+	filename := filepath.Join(t.TempDir(), "report.html")
+	if err := writeHTMLReport(filename, graph); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "html.golden", got)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonDiagnostic is a finding in the JSON output. It extends the diagnostics
+// printed by the analysis framework's drivers (e.g. go vet -json) with the severity.
+type jsonDiagnostic struct {
+	Category       string             `json:"category,omitempty"`
+	Severity       string             `json:"severity"`
+	Posn           string             `json:"posn"`
+	Message        string             `json:"message"`
+	SuggestedFixes []jsonSuggestedFix `json:"suggested_fixes,omitempty"`
+	Related        []jsonRelatedInfo  `json:"related,omitempty"`
+}
+
+type jsonSuggestedFix struct {
+	Message string         `json:"message"`
+	Edits   []jsonTextEdit `json:"edits"`
+}
+
+type jsonTextEdit struct {
+	Filename string `json:"filename"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	New      string `json:"new"`
+}
+
+type jsonRelatedInfo struct {
+	Posn    string `json:"posn"`
+	Message string `json:"message"`
+}
+
+// writeJSON writes findings as JSON grouped by package ID and analyzer name,
+// the same way the analysis framework's drivers do.
+func writeJSON(w io.Writer, findings []finding) error {
+	tree := make(map[string]map[string][]jsonDiagnostic)
+	for _, f := range findings {
+		if tree[f.pkg] == nil {
+			tree[f.pkg] = make(map[string][]jsonDiagnostic)
+		}
+		tree[f.pkg][analyzer.Name] = append(tree[f.pkg][analyzer.Name], newJSONDiagnostic(f))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(tree)
+}
+
+// newJSONDiagnostic returns the finding in the JSON form.
+func newJSONDiagnostic(f finding) jsonDiagnostic {
+	d := jsonDiagnostic{
+		Category: f.Category,
+		Severity: f.severity,
+		Posn:     f.pos.String(),
+		Message:  f.Message,
+	}
+	for _, fix := range f.SuggestedFixes {
+		jf := jsonSuggestedFix{Message: fix.Message}
+		for _, edit := range fix.TextEdits {
+			file := f.fset.File(edit.Pos)
+			end := edit.End
+			if !end.IsValid() {
+				end = edit.Pos
+			}
+			jf.Edits = append(jf.Edits, jsonTextEdit{
+				Filename: file.Name(),
+				Start:    file.Offset(edit.Pos),
+				End:      file.Offset(end),
+				New:      string(edit.NewText),
+			})
+		}
+		d.SuggestedFixes = append(d.SuggestedFixes, jf)
+	}
+	for _, related := range f.Related {
+		d.Related = append(d.Related, jsonRelatedInfo{
+			Posn:    f.fset.Position(related.Pos).String(),
+			Message: related.Message,
+		})
+	}
+	return d
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"go/types"
+	"io"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// jsonlFinding is a line of the JSONL output: a finding along with the ID of its package.
+type jsonlFinding struct {
+	Package string `json:"package"`
+	jsonDiagnostic
+}
+
+// jsonlStream writes findings of root packages as JSON lines as soon as the analyzer reports them,
+// so editors and other consumers can show them before the whole workspace is analyzed.
+// Unlike other formats, findings are written in the order packages are analyzed.
+type jsonlStream struct {
+	mu   sync.Mutex
+	enc  *json.Encoder
+	seen map[findingKey]bool
+	// err is the first error writing findings.
+	err error
+}
+
+func newJSONLStream(w io.Writer) *jsonlStream {
+	return &jsonlStream{
+		enc:  json.NewEncoder(w),
+		seen: make(map[findingKey]bool),
+	}
+}
+
+// analyzer returns a copy of the analyzer streaming findings of the root packages
+// along with reporting them as usual. Findings of dependencies analyzed for facts are left out.
+func (s *jsonlStream) analyzer(a *analysis.Analyzer, roots []*packages.Package) *analysis.Analyzer {
+	byTypes := make(map[*types.Package]*packages.Package, len(roots))
+	for _, pkg := range roots {
+		byTypes[pkg.Types] = pkg
+	}
+
+	streaming := *a
+	streaming.Run = func(pass *analysis.Pass) (any, error) {
+		if pkg, ok := byTypes[pass.Pkg]; ok {
+			report := pass.Report
+			pass.Report = func(d analysis.Diagnostic) {
+				report(d)
+				s.write(newFinding(pkg, d))
+			}
+		}
+		return a.Run(pass)
+	}
+	return &streaming
+}
+
+// write writes the finding unless it was written already for another package (e.g. foo.test).
+func (s *jsonlStream) write(f finding) {
+	if !reportedFile(f.pos.Filename) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := f.key()
+	if s.seen[k] || s.err != nil {
+		return
+	}
+	s.seen[k] = true
+	s.err = s.enc.Encode(jsonlFinding{Package: f.pkg, jsonDiagnostic: newJSONDiagnostic(f)})
+}
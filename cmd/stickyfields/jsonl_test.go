@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONLStream(t *testing.T) {
+	var buf bytes.Buffer
+	s := newJSONLStream(&buf)
+	for _, pkg := range []string{"example.com/conv", "example.com/conv [example.com/conv.test]"} {
+		for _, f := range testFindings(t) {
+			f.pkg = pkg
+			s.write(f)
+		}
+	}
+	if s.err != nil {
+		t.Fatal(s.err)
+	}
+	// Findings of the test variant of the package are written once, for the package analyzed first.
+	checkGolden(t, "jsonl.golden", buf.Bytes())
+}
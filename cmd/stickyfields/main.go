@@ -1,18 +1,574 @@
+// Command stickyfields reports converter functions leaking fields.
+//
+// It can be run standalone on package patterns (e.g. stickyfields -fix ./...)
+// or as a vet tool (go vet -vettool=$(which stickyfields) ./...).
+// Editors and pre-commit hooks can analyze single files (stickyfields conv/user.go)
+// or the source piped on stdin (stickyfields -stdin-filename=conv/user.go < buffer),
+// with types loaded from the package of the file.
+//
+// Findings are printed as plain text by default. Use -format=json for the JSON
+// output of the analysis framework or -format=sarif for SARIF 2.1.0 logs
+// understood by GitHub code scanning and other SARIF-aware platforms.
+// For editor integrations, -format=jsonl writes a JSON object per line for every finding
+// as soon as it's reported, rather than once all packages are analyzed.
+// Within GitHub Actions, -format=github prints findings as annotations of the pull request.
+// Additionally, -report-html=out.html writes a browsable report of all converters
+// along with their field coverage, -report-mapping=mappings.md writes Markdown tables of the fields
+// converters map into each other, and -dead-fields reports fields of models mapped
+// by none of their converters across all analyzed packages.
+//
+// To adopt the analyzer in an existing code base, record the current findings with
+// -baseline=stickyfields-baseline.json -update-baseline and run it with the same -baseline
+// afterwards to report new findings only. To gate pull requests of large code bases,
+// -diff=origin/main reports findings of functions changed since the merge base with origin/main
+// and of struct fields added since then only.
+//
+// Findings printed as text or annotations fail the run (exit code 3), unless their severity is info.
+// For staged rollouts, -fail-on limits failing findings to some codes or severities, -fail-threshold
+// tolerates a number of them and -report-only always exits with 0.
+//
+// During local development, -watch keeps analyzing again the packages owning changed files
+// and the matched packages importing them until interrupted.
+//
+// Findings of packages are cached in the user's cache directory (or -cache-dir), keyed by contents
+// of their files and dependencies, the configuration and the analyzer: packages unchanged since
+// a previous run are neither loaded nor analyzed again. Use -cache=false to analyze everything.
+//
+// To tell where time goes in large code bases, -timings prints how long loading and analyzing
+// each package took, and -cpuprofile, -memprofile and -trace write profiles for go tool pprof and go tool trace.
+//
+// To start a converter, stickyfields gen -from model.User -to db.UserRow writes a converter function
+// with fields matched by name and type assigned, and TODO markers for the others. As a runtime complement
+// to the analysis, stickyfields gen-tests ./... writes tests checking that converters set every output field
+// from fully populated inputs.
+//
+// Options can also be kept in .stickyfields.yaml (or .yml, .toml) in the module root
+// or in the file given with -config, keyed by flag names. Flags given explicitly take precedence.
 package main
 
 import (
+	"cmp"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
 	"golang.org/x/tools/go/analysis/unitchecker"
+	"golang.org/x/tools/go/packages"
 
 	"github.com/amberpixels/go-stickyfields/internal/sf"
 )
 
+// Output formats of findings.
+const (
+	formatText   = "text"
+	formatJSON   = "json"
+	formatJSONL  = "jsonl"
+	formatSARIF  = "sarif"
+	formatGitHub = "github"
+)
+
+// Flags of the command. They're defined by registerFlags rather than at initialization,
+// so go vet querying flags of the vet tool (-flags) gets the ones of sf.Analyzer only.
+var (
+	format, reportHTML, configFile, diffRef     *string
+	stdinFilename, reportMapping                *string
+	fix, quiet, updateBaseline, watchMode       *bool
+	reportOnly, timings, cacheFindings          *bool
+	deadFields                                  *bool
+	cpuProfile, memProfile, traceFile, cacheDir *string
+	failThreshold                               *int
+)
+
+var (
+	// config of the analyzer run on package patterns. Unlike sf.Analyzer used by go vet,
+	// it writes messages depending on the verbosity to stderr.
+	config = func() *sf.Config {
+		cfg := sf.DefaultConfig()
+		cfg.Output = os.Stderr
+		return cfg
+	}()
+	analyzer = sf.NewAnalyzer(config)
+)
+
 func main() {
-	analyzer := &analysis.Analyzer{
-		Name: "stickyfields",
-		Doc:  "reports all inconsistent converter functions: ensures sticky fields)",
-		Run:  sf.Run,
+	log.SetFlags(0)
+	log.SetPrefix("stickyfields: ")
+
+	if len(os.Args) > 1 {
+		subcommands := map[string]func([]string) error{"gen": runGen, "gen-tests": runGenTests}
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+	if isVetTool(os.Args[1:]) {
+		// Messages stay plain: go vet prints source lines on its own (-c).
+		vet := sf.DefaultConfig()
+		vet.Pretty = false
+		unitchecker.Main(sf.NewAnalyzer(vet))
+	}
+
+	registerFlags()
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\nUsage: stickyfields [-flag] [package | file.go]\n\nFlags:\n", analyzer.Doc)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if err := applyConfigFile(); err != nil {
+		log.Fatal(err)
+	}
+	if *quiet {
+		config.Verbosity = sf.VerbosityQuiet
+	}
+	// The analyzer doesn't know where findings are printed: -color=auto is resolved here.
+	fd := os.Stdout.Fd()
+	config.Color = colorMode(config.Color, isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd))
+	// In the precise mode, the SSA form of packages is the result of buildssa.Analyzer (see sf.NewAnalyzer).
+	if config.Precise {
+		analyzer.Requires = append(analyzer.Requires, buildssa.Analyzer)
+	}
+	config.Mappings = *reportMapping != ""
+	if err := checkFailOn(); err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case formatText:
+	case formatJSON, formatJSONL, formatSARIF, formatGitHub:
+		// Source lines don't belong into machine-readable messages.
+		if err := flag.Set("pretty", "false"); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown format %q: expected %q, %q, %q, %q or %q",
+			*format, formatText, formatJSON, formatJSONL, formatSARIF, formatGitHub)
+	}
+
+	if flag.NArg() == 0 && *stdinFilename == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *fix && *stdinFilename != "" {
+		log.Fatal("-fix cannot be applied to the source read from stdin")
+	}
+	if *watchMode && (*fix || *updateBaseline || *stdinFilename != "") {
+		log.Fatal("-watch cannot be combined with -fix, -update-baseline or -stdin-filename")
+	}
+	patterns, err := filePatterns(flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *diffRef != "" {
+		changed, err := changedLines(*diffRef)
+		if err != nil {
+			log.Fatal(err)
+		}
+		config.Changed = changed
+	}
+	if *updateBaseline && config.Baseline == "" {
+		log.Fatal("-update-baseline requires -baseline")
+	}
+
+	exitCode := profiled(func() int {
+		switch {
+		case *updateBaseline:
+			return writeBaseline(patterns)
+		case *watchMode:
+			return watch(os.Stdout, patterns)
+		}
+		exitCode := run(os.Stdout, patterns)
+		if *reportOnly {
+			// Findings and errors are printed all the same.
+			return 0
+		}
+		return exitCode
+	})
+	os.Exit(exitCode)
+}
+
+// registerFlags defines flags of the command along with the ones of the analyzer.
+func registerFlags() {
+	format = flag.String("format", formatText,
+		"output format of findings: "+formatText+", "+formatJSON+", "+formatJSONL+", "+formatSARIF+" or "+formatGitHub)
+	fix = flag.Bool("fix", false, "apply all suggested fixes")
+	reportHTML = flag.String("report-html", "", "write an HTML report of converters and their field coverage to the file")
+	reportMapping = flag.String("report-mapping", "", "write Markdown tables of field mappings of converters to the file")
+	deadFields = flag.Bool("dead-fields", false,
+		"report fields of models mapped by none of their converters across all analyzed packages ("+sf.CodeDeadField+")")
+	quiet = flag.Bool("q", false, "print nothing but findings (same as -v=0)")
+	configFile = flag.String("config", "",
+		"YAML or TOML file of options keyed by flag names (default .stickyfields.yaml, .yml or .toml in the module root)")
+	diffRef = flag.String("diff", "",
+		"report findings of code changed since the merge base with the git ref only (e.g. origin/main)")
+	flag.Var(&failOn, "fail-on",
+		"comma-separated codes and severities of findings failing the run, e.g. SF001,error")
+	failThreshold = flag.Int("fail-threshold", 0, "fail the run only if more findings than this fail it")
+	reportOnly = flag.Bool("report-only", false, "always exit with 0, e.g. while rolling out the analyzer in CI")
+	cpuProfile = flag.String("cpuprofile", "", "write a CPU profile to the file")
+	memProfile = flag.String("memprofile", "", "write a memory profile to the file")
+	traceFile = flag.String("trace", "", "write an execution trace to the file")
+	timings = flag.Bool("timings", false, "print how long loading and analyzing each package took to stderr")
+	cacheFindings = flag.Bool("cache", true, "reuse findings of packages unchanged since a previous run")
+	cacheDir = flag.String("cache-dir", defaultCacheDir(), "directory of the cache of findings")
+	watchMode = flag.Bool("watch", false,
+		"analyze again packages owning changed files and the ones importing them until interrupted")
+	stdinFilename = flag.String("stdin-filename", "",
+		"analyze the source read from stdin as the content of the file, along with the rest of its package")
+	updateBaseline = flag.Bool("update-baseline", false,
+		"record all current findings in the -baseline file instead of reporting them")
+
+	// Flags of the analyzer are bound to the same configuration,
+	// so presets of -mode respect flags given explicitly on the command line.
+	config.RegisterFlags(flag.CommandLine)
+	flag.Var(flag.Lookup("verbosity").Value, "v", "shorthand for -verbosity")
+}
+
+// applyConfigFile applies options of the -config file or of the configuration file
+// found in the module root, if any.
+func applyConfigFile() error {
+	filename := *configFile
+	if filename == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if filename, err = sf.FindConfigFile(dir); err != nil || filename == "" {
+			return err
+		}
+	}
+	settings, err := sf.ReadConfigFile(filename)
+	if err != nil {
+		return err
+	}
+	if err := sf.ApplySettings(flag.CommandLine, settings); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// isVetTool reports whether the command is invoked by go vet: with a .cfg file describing the package
+// as the last argument (following flags such as -json), or for querying the tool's version or flags.
+func isVetTool(args []string) bool {
+	if len(args) > 0 && strings.HasSuffix(args[len(args)-1], ".cfg") {
+		return true
+	}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-V=") || arg == "-flags" {
+			return true
+		}
+	}
+	return false
+}
+
+// colorMode resolves the auto color mode of messages printed to a terminal or not:
+// they're colored on terminals, unless NO_COLOR is set or TERM is dumb.
+func colorMode(mode string, terminal bool) string {
+	if mode != sf.ColorAuto {
+		return mode
+	}
+	if !terminal || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return sf.ColorNever
+	}
+	return sf.ColorAlways
+}
+
+// run analyzes the packages matching the patterns, prints the findings to w and returns the exit code:
+// 1 if loading or analyzing failed, 3 if findings failing the run (see fails) were printed
+// as text or annotations and 0 otherwise.
+func run(w io.Writer, patterns []string) int {
+	var stream *jsonlStream
+	if *format == formatJSONL {
+		stream = newJSONLStream(w)
+	}
+
+	// Findings of packages unchanged since a previous run come from the cache.
+	var (
+		c      *cache
+		hits   cacheHits
+		misses map[string][]byte
+	)
+	if useCache() {
+		var err error
+		if c, err = openCache(*cacheDir); err == nil {
+			hits, misses, err = c.lookup(patterns)
+		}
+		if err != nil {
+			log.Printf("cache: %v", err)
+			c = nil
+		} else {
+			patterns = slices.Sorted(maps.Keys(misses))
+		}
+		if stream != nil {
+			for _, f := range hits.findings {
+				stream.write(f)
+			}
+		}
 	}
 
-	unitchecker.Main(analyzer)
+	var (
+		graph    *checker.Graph
+		findings []finding
+		exitCode int
+	)
+	if len(patterns) > 0 {
+		if graph, exitCode = analyze(patterns, stream); graph == nil {
+			return exitCode
+		}
+		findings = collectFindings(graph)
+		if *deadFields {
+			findings = append(findings, deadFieldFindings(graph)...)
+		}
+		if c != nil && exitCode == 0 {
+			if err := c.store(misses, graph, findings); err != nil {
+				log.Printf("cache: %v", err)
+			}
+		}
+	}
+	files := slices.Concat(analyzedFiles(graph), hits.files)
+	findings = fileFindings(sortFindings(append(findings, hits.findings...)))
+	var err error
+	failing := fails(findings)
+
+	switch *format {
+	case formatJSON:
+		err = writeJSON(w, findings)
+	case formatJSONL:
+		// Findings were written while analyzing.
+		err = stream.err
+	case formatSARIF:
+		err = writeSARIF(w, findings)
+	case formatGitHub:
+		writeGitHub(w, findings)
+		if failing && exitCode == 0 {
+			exitCode = exitFindings
+		}
+	default:
+		writeText(w, findings)
+		if config.Verbosity >= sf.VerbositySummary {
+			writeSummary(w, files, findings)
+		}
+		if failing && exitCode == 0 {
+			exitCode = exitFindings
+		}
+	}
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+
+	if *reportHTML != "" {
+		if err := writeHTMLReport(*reportHTML, graph); err != nil {
+			log.Print(err)
+			return 1
+		}
+	}
+	if *reportMapping != "" {
+		if err := writeMappingReport(*reportMapping, graph); err != nil {
+			log.Print(err)
+			return 1
+		}
+	}
+
+	if *fix {
+		if err := applyFixes(findings); err != nil {
+			log.Print(err)
+			return 1
+		}
+	}
+	return exitCode
+}
+
+// analyze loads the packages matching the patterns and runs the analyzer on them, writing findings
+// to the stream as soon as they're reported if it's not nil. The graph is nil if loading or analyzing failed;
+// the exit code is 1 if analyzing any package failed.
+func analyze(patterns []string, stream *jsonlStream) (*checker.Graph, int) {
+	start := time.Now()
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: config.IncludeTests, Overlay: overlay}, patterns...)
+	if err != nil {
+		log.Print(err)
+		return nil, 1
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, 1
+	}
+	load := time.Since(start)
+
+	a := analyzer
+	if stream != nil {
+		a = stream.analyzer(analyzer, pkgs)
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		log.Print(err)
+		return nil, 1
+	}
+
+	exitCode := 0
+	for act := range graph.All() {
+		if act.Err != nil {
+			log.Printf("%s: %v", act.Package.PkgPath, act.Err)
+			exitCode = 1
+		}
+	}
+	if *timings {
+		writeTimings(os.Stderr, load, graph)
+	}
+	return graph, exitCode
+}
+
+// writeBaseline records the findings of the packages matching the patterns in the baseline file
+// and returns the exit code.
+func writeBaseline(patterns []string) int {
+	filename := config.Baseline
+	// Every finding is recorded, including the ones of the current baseline.
+	config.Baseline = ""
+
+	graph, exitCode := analyze(patterns, nil)
+	if graph == nil || exitCode != 0 {
+		return 1
+	}
+
+	var baseline sf.Baseline
+	for _, act := range graph.Roots {
+		if result, ok := act.Result.(*sf.Result); ok {
+			baseline.Add(result)
+		}
+	}
+	if err := baseline.Write(filename); err != nil {
+		log.Print(err)
+		return 1
+	}
+	if config.Verbosity >= sf.VerbositySummary {
+		log.Printf("recorded %d findings in %s", len(baseline.Findings), filename)
+	}
+	return 0
+}
+
+// finding is a diagnostic reported at a root package.
+type finding struct {
+	analysis.Diagnostic
+	fset *token.FileSet
+	pos  token.Position
+	// pkg is the ID of the package the finding was reported in.
+	pkg string
+	// severity is the configured severity of the finding's code.
+	severity string
+}
+
+// newFinding returns the diagnostic reported in the package as a finding.
+func newFinding(pkg *packages.Package, d analysis.Diagnostic) finding {
+	return finding{
+		Diagnostic: d,
+		fset:       pkg.Fset,
+		pos:        pkg.Fset.Position(d.Pos),
+		pkg:        pkg.ID,
+		severity:   config.Severity(d.Category),
+	}
+}
+
+// findingKey identifies findings reported in multiple packages (e.g. foo and foo.test).
+type findingKey struct {
+	pos      token.Position
+	category string
+	message  string
+}
+
+func (f finding) key() findingKey {
+	return findingKey{f.pos, f.Category, f.Message}
+}
+
+// collectFindings returns diagnostics of root packages ordered by position, code and message,
+// so outputs of different runs can be compared. Diagnostics of files belonging to multiple packages
+// (e.g. foo and foo.test) are reported once, attributed to the first package ID (foo).
+func collectFindings(graph *checker.Graph) []finding {
+	seen := make(map[findingKey]bool)
+
+	roots := slices.SortedFunc(slices.Values(graph.Roots), func(a, b *checker.Action) int {
+		return cmp.Compare(a.Package.ID, b.Package.ID)
+	})
+
+	var findings []finding
+	for _, act := range roots {
+		for _, d := range act.Diagnostics {
+			f := newFinding(act.Package, d)
+			if k := f.key(); !seen[k] {
+				seen[k] = true
+				findings = append(findings, f)
+			}
+		}
+	}
+	return sortFindings(findings)
+}
+
+// sortFindings orders findings by position, code and message.
+func sortFindings(findings []finding) []finding {
+	slices.SortStableFunc(findings, func(a, b finding) int {
+		return cmp.Or(
+			comparePositions(a.pos, b.pos),
+			cmp.Compare(a.Category, b.Category),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return findings
+}
+
+// comparePositions orders positions by file name, line and column.
+func comparePositions(a, b token.Position) int {
+	return cmp.Or(
+		cmp.Compare(a.Filename, b.Filename),
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Column, b.Column),
+	)
+}
+
+// analyzedFiles returns names of files analyzed in root packages of the graph, if any.
+func analyzedFiles(graph *checker.Graph) []string {
+	if graph == nil {
+		return nil
+	}
+	var files []string
+	for _, act := range graph.Roots {
+		if result, ok := act.Result.(*sf.Result); ok {
+			files = append(files, result.Files...)
+		}
+	}
+	return files
+}
+
+// writeSummary prints the number of analyzed files and findings.
+func writeSummary(w io.Writer, files []string, findings []finding) {
+	files = slices.Compact(slices.Sorted(slices.Values(files)))
+	if len(findings) == 0 {
+		fmt.Fprintf(w, "\nFiles total analyzed: %d. Warnings: 0\n", len(files))
+		return
+	}
+
+	warned := make(map[string]bool)
+	for _, f := range findings {
+		warned[f.pos.Filename] = true
+	}
+	fmt.Fprintf(w, "\nFiles total analyzed: %d. Warnings: %d caught in %d files\n", len(files), len(findings), len(warned))
+}
+
+// writeText prints findings as plain text, followed by their related information.
+func writeText(w io.Writer, findings []finding) {
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s: %s\n", f.pos, f.Message)
+		for _, related := range f.Related {
+			fmt.Fprintf(w, "\t%s: %s\n", f.fset.Position(related.Pos), related.Message)
+		}
+	}
 }
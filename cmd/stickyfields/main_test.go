@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+var update = flag.Bool("update", false, "update golden files of outputs")
+
+func TestMain(m *testing.M) {
+	registerFlags()
+	os.Exit(m.Run())
+}
+
+// testSource is the content of the file of test findings.
+const testSource = `package conv
+
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID, Email: u.Name}
+}
+
+type User struct {
+	ID, Name, Email string
+}
+`
+
+// testFindings returns findings of a file of the working directory covering all parts of findings:
+// related information, suggested fixes, severities and characters escaped by some formats.
+func testFindings(t *testing.T) []finding {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file := fset.AddFile(filepath.Join(wd, "conv", "user.go"), -1, len(testSource))
+	file.SetLinesForContent([]byte(testSource))
+	pos := func(line, column int) token.Pos {
+		return file.LineStart(line) + token.Pos(column-1)
+	}
+
+	diagnostics := []struct {
+		analysis.Diagnostic
+		severity string
+	}{
+		{
+			Diagnostic: analysis.Diagnostic{
+				Pos:      pos(3, 6),
+				Category: sf.CodeMissingOutput,
+				Message:  "SF001, SF002: converter function is leaking fields:\n missing input fields: [u.Email]\n missing output fields: [Name]",
+				Related: []analysis.RelatedInformation{
+					{Pos: pos(7, 6), Message: "input model User declared here"},
+					{Pos: pos(8, 11), Message: "input field u.Email declared here"},
+				},
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   "Suppress with //sf:ignore Email Name",
+					TextEdits: []analysis.TextEdit{{Pos: pos(3, 1), NewText: []byte("//sf:ignore Email Name\n")}},
+				}},
+			},
+			severity: sf.SeverityError,
+		},
+		{
+			Diagnostic: analysis.Diagnostic{
+				Pos:      pos(4, 27),
+				Category: sf.CodeSwapped,
+				Message:  "SF005: 100% sure: Email = u.Name,\r\nu.Email unused",
+			},
+			severity: sf.SeverityWarning,
+		},
+		{
+			Diagnostic: analysis.Diagnostic{
+				Pos:      pos(3, 6),
+				Category: sf.CodeMissingError,
+				Message:  "SF019: converter function can fail but returns no error: strconv.Atoi",
+			},
+			severity: sf.SeverityInfo,
+		},
+	}
+	findings := make([]finding, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		findings = append(findings, finding{
+			Diagnostic: d.Diagnostic,
+			fset:       fset,
+			pos:        fset.Position(d.Pos),
+			pkg:        "example.com/conv",
+			severity:   d.severity,
+		})
+	}
+	return sortFindings(findings)
+}
+
+// checkGolden compares the output with the golden file of testdata, or updates the file with -update.
+// The working directory is replaced with $WD, as absolute file names differ between machines.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = bytes.ReplaceAll(got, []byte(filepath.ToSlash(wd)), []byte("$WD"))
+
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	writeText(&buf, testFindings(t))
+	checkGolden(t, "text.golden", buf.Bytes())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, testFindings(t)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "json.golden", buf.Bytes())
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, testFindings(t)); err != nil {
+		t.Fatal(err)
+	}
+	// Locations of files of the working directory are relative to it.
+	if strings.Contains(buf.String(), "file://") {
+		t.Error("absolute artifact location of a file of the working directory")
+	}
+	checkGolden(t, "sarif.golden", buf.Bytes())
+}
+
+func TestColorMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		terminal bool
+		env      map[string]string
+		want     string
+	}{
+		{"terminal", sf.ColorAuto, true, nil, sf.ColorAlways},
+		{"not a terminal", sf.ColorAuto, false, nil, sf.ColorNever},
+		{"NO_COLOR", sf.ColorAuto, true, map[string]string{"NO_COLOR": "1"}, sf.ColorNever},
+		{"dumb terminal", sf.ColorAuto, true, map[string]string{"TERM": "dumb"}, sf.ColorNever},
+		{"always", sf.ColorAlways, false, map[string]string{"NO_COLOR": "1"}, sf.ColorAlways},
+		{"never", sf.ColorNever, true, nil, sf.ColorNever},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("TERM", "xterm")
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+			if got := colorMode(test.mode, test.terminal); got != test.want {
+				t.Errorf("colorMode(%q, %t) = %q, want %q", test.mode, test.terminal, got, test.want)
+			}
+		})
+	}
+}
+
+func TestStdin(t *testing.T) {
+	defer func(name, f string) {
+		*stdinFilename, *format, onlyFiles, overlay = name, f, nil, nil
+	}(*stdinFilename, *format)
+	defer func(stdin *os.File) { os.Stdin = stdin }(os.Stdin)
+
+	// The buffer of the editor differs from the file on disk: its converter leaking fields moved down.
+	src := `package conv
+
+type User struct {
+	ID, Name, Email string
+}
+
+type UserRow struct {
+	ID, Name, Email string
+}
+
+// ToUserRow is edited.
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID}
+}
+`
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.WriteString(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	os.Stdin = stdin
+	*stdinFilename = filepath.Join("testdata", "src", "conv", "conv.go")
+	*format = formatText
+
+	patterns, err := filePatterns(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if got := run(&buf, patterns); got != exitFindings {
+		t.Errorf("run() = %d, want %d", got, exitFindings)
+	}
+	abs, err := filepath.Abs(*stdinFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if want := abs + ":12:6: "; !strings.HasPrefix(lines[0], want) {
+		t.Errorf("got finding %q, want it at %s", lines[0], want)
+	}
+	// ToUserRowPartial is gone from the buffer.
+	if strings.Contains(buf.String(), "ToUserRowPartial") {
+		t.Errorf("got findings of the file on disk:\n%s", buf.String())
+	}
+}
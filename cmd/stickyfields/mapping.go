@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis/checker"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// mappingConverter is a converter documented in the mapping report.
+type mappingConverter struct {
+	sf.Converter
+	pkg string
+	pos token.Position
+}
+
+// writeMappingReport writes Markdown tables of the field mappings of converters of the root packages
+// to the file, e.g. to publish them along with API docs or to attach them to pull requests changing schemas.
+// Links to converters are relative to the directory of the file.
+func writeMappingReport(filename string, graph *checker.Graph) error {
+	var converters []mappingConverter
+	seen := make(map[string]bool)
+	for _, act := range graph.Roots {
+		result, ok := act.Result.(*sf.Result)
+		if !ok || act.Err != nil {
+			continue
+		}
+		for _, c := range result.Converters {
+			pos := act.Package.Fset.Position(c.Pos)
+			// Files of foo are analyzed as part of foo.test too.
+			if c.Mapping == nil || seen[pos.String()] {
+				continue
+			}
+			seen[pos.String()] = true
+			converters = append(converters, mappingConverter{Converter: c, pkg: act.Package.PkgPath, pos: pos})
+		}
+	}
+	slices.SortFunc(converters, func(a, b mappingConverter) int {
+		return cmp.Or(cmp.Compare(a.pkg, b.pkg), comparePositions(a.pos, b.pos))
+	})
+
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# Field mappings\n")
+	if len(converters) == 0 {
+		buf.WriteString("\nNo converters found.\n")
+	}
+	for i, c := range converters {
+		if i == 0 || converters[i-1].pkg != c.pkg {
+			fmt.Fprintf(&buf, "\n## %s\n", c.pkg)
+		}
+		writeMappingTable(&buf, dir, c)
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0o644)
+}
+
+// writeMappingTable writes the section of the converter: a row per output field along with the input
+// fields it's mapped from, followed by rows of input fields mapped to no output field.
+func writeMappingTable(buf *bytes.Buffer, dir string, c mappingConverter) {
+	m := c.Mapping
+	in, out := modelName(m.In.Type), modelName(m.Out.Type)
+	link := c.pos.Filename
+	if abs, err := filepath.Abs(link); err == nil {
+		if rel, err := filepath.Rel(dir, abs); err == nil {
+			link = rel
+		}
+	}
+	fmt.Fprintf(buf, "\n### %s\n\n`%s` → `%s` ([%s:%d](%s#L%d))\n\n",
+		c.Name, in, out, filepath.Base(c.pos.Filename), c.pos.Line, filepath.ToSlash(link), c.pos.Line)
+
+	buf.WriteString("| Input field | Output field | Source |\n| --- | --- | --- |\n")
+	for _, f := range m.Outputs {
+		from := make([]string, len(f.From))
+		for i, name := range f.From {
+			from[i] = markdownCode(in + "." + name)
+		}
+		source := "_not set_"
+		if len(f.Sources) > 0 {
+			sources := make([]string, len(f.Sources))
+			for i, s := range f.Sources {
+				sources[i] = markdownCode(s)
+			}
+			source = strings.Join(sources, ", ")
+		}
+		fmt.Fprintf(buf, "| %s | %s | %s |\n", cmp.Or(strings.Join(from, ", "), "—"), markdownCode(out+"."+f.Field), source)
+	}
+	for _, f := range m.Inputs {
+		if len(f.To) > 0 {
+			continue
+		}
+		note := "_not mapped_"
+		if f.Read {
+			note = "_read, not mapped_"
+		}
+		fmt.Fprintf(buf, "| %s | — | %s |\n", markdownCode(in+"."+f.Field), note)
+	}
+}
+
+// modelName returns the name of the model qualified by its package name, e.g. dbmodel.Sample.
+func modelName(obj *types.TypeName) string {
+	return types.TypeString(obj.Type(), (*types.Package).Name)
+}
+
+// markdownCode returns s as inline code within a table cell.
+func markdownCode(s string) string {
+	return "`" + strings.ReplaceAll(strings.ReplaceAll(s, "`", "'"), "|", `\|`) + "`"
+}
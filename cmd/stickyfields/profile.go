@@ -0,0 +1,115 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"time"
+
+	"golang.org/x/tools/go/analysis/checker"
+)
+
+// profiled runs the command profiled as requested by -cpuprofile, -memprofile and -trace
+// and returns its exit code, 1 if profiling failed. Profiles are written whatever the exit code.
+func profiled(command func() int) int {
+	stop, err := startProfiling()
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	exitCode := command()
+	if err := stop(); err != nil {
+		log.Print(err)
+		exitCode = cmp.Or(exitCode, 1)
+	}
+	return exitCode
+}
+
+// startProfiling starts the CPU profile and the execution trace if requested.
+// The returned function stops them and writes the memory profile.
+func startProfiling() (func() error, error) {
+	var stops []func() error
+	stop := func() error {
+		var errs []error
+		for _, stop := range slices.Backward(stops) {
+			errs = append(errs, stop())
+		}
+		return cmp.Or(errs...)
+	}
+
+	if *cpuProfile != "" {
+		f, err := os.Create(*cpuProfile)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if *traceFile != "" {
+		f, err := os.Create(*traceFile)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+
+	if *memProfile != "" {
+		stops = append(stops, func() error {
+			f, err := os.Create(*memProfile)
+			if err != nil {
+				return err
+			}
+			// Get up-to-date statistics of allocations.
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		})
+	}
+	return stop, nil
+}
+
+// writeTimings prints how long loading took and how long analyzing each package took,
+// the slowest first. Dependencies are analyzed for facts of their structs too.
+func writeTimings(w io.Writer, load time.Duration, graph *checker.Graph) {
+	durations := make(map[string]time.Duration)
+	var total time.Duration
+	for act := range graph.All() {
+		durations[act.Package.ID] += act.Duration
+		total += act.Duration
+	}
+	ids := slices.SortedFunc(maps.Keys(durations), func(a, b string) int {
+		return cmp.Or(cmp.Compare(durations[b], durations[a]), cmp.Compare(a, b))
+	})
+
+	fmt.Fprintf(w, "%12s  loading\n", load.Round(time.Millisecond))
+	fmt.Fprintf(w, "%12s  analyzing %d packages (sum of all analyzers)\n", total.Round(time.Microsecond), len(durations))
+	for _, id := range ids {
+		fmt.Fprintf(w, "%12s  %s\n", durations[id].Round(time.Microsecond), id)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestProfiled(t *testing.T) {
+	defer func(cpu, mem, trace string, cache bool) {
+		*cpuProfile, *memProfile, *traceFile, *cacheFindings = cpu, mem, trace, cache
+	}(*cpuProfile, *memProfile, *traceFile, *cacheFindings)
+	*cacheFindings = false
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+
+	tests := []struct {
+		name string
+		// missing lists profiles written to a missing directory.
+		missing []string
+		written []string
+		ran     bool
+		want    int
+	}{
+		{name: "failed run", written: []string{"cpu", "mem", "trace"}, ran: true, want: 1},
+		{name: "missing memory profile", missing: []string{"mem"}, written: []string{"cpu", "trace"}, ran: true, want: 1},
+		// Profiling failing to start, the command doesn't run.
+		{name: "missing trace", missing: []string{"trace"}, written: []string{"cpu"}, want: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]*string{"cpu": cpuProfile, "mem": memProfile, "trace": traceFile}
+			for name, flag := range files {
+				*flag = filepath.Join(dir, name+".out")
+			}
+			for _, name := range test.missing {
+				*files[name] = filepath.Join(dir, "missing", name+".out")
+			}
+
+			var ran bool
+			got := profiled(func() int {
+				ran = true
+				return run(io.Discard, []string{"./testdata/src/broken"})
+			})
+			if got != test.want {
+				t.Errorf("profiled() = %d, want %d", got, test.want)
+			}
+
+			// Whatever failed, the CPU profile is stopped and written.
+			if err := pprof.StartCPUProfile(io.Discard); err != nil {
+				t.Errorf("CPU profile not stopped: %v", err)
+			} else {
+				pprof.StopCPUProfile()
+			}
+			for _, name := range test.written {
+				info, err := os.Stat(*files[name])
+				if err != nil {
+					t.Errorf("%s profile not written: %v", name, err)
+				} else if info.Size() == 0 {
+					t.Errorf("%s profile is empty", name)
+				}
+			}
+			if ran != test.ran {
+				t.Errorf("command ran: %t, want %t", ran, test.ran)
+			}
+		})
+	}
+}
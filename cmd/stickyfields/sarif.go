@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSrcRoot is the base of relative artifact locations: the directory the command runs in.
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// sarifLog and the types below are the subset of SARIF 2.1.0 used for reporting findings.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeSARIF writes findings as a SARIF log with a rule for every code of findings.
+// Declarations of the models and their missing fields, along with positions where the missing fields
+// should be handled, are reported as related locations of a finding.
+func writeSARIF(w io.Writer, findings []finding) error {
+	driver := sarifDriver{
+		Name:           analyzer.Name,
+		InformationURI: "https://github.com/amberpixels/go-stickyfields",
+	}
+	for _, code := range slices.Sorted(maps.Keys(sf.CodeDocs)) {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               code,
+			ShortDescription: sarifMessage{Text: sf.CodeDocs[code]},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		if f.Category == sf.CategoryOmitted {
+			continue
+		}
+		result := sarifResult{
+			RuleID:    f.Category,
+			Level:     sarifLevel(f.severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysical(f.pos.Filename, f.pos.Line, f.pos.Column)}},
+		}
+		for i, related := range f.Related {
+			pos := f.fset.Position(related.Pos)
+			result.RelatedLocations = append(result.RelatedLocations, sarifLocation{
+				ID:               i + 1,
+				PhysicalLocation: sarifPhysical(pos.Filename, pos.Line, pos.Column),
+				Message:          &sarifMessage{Text: related.Message},
+			})
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// sarifLevel returns the SARIF level of results of the severity.
+func sarifLevel(severity string) string {
+	switch severity {
+	case sf.SeverityError:
+		return "error"
+	case sf.SeverityInfo:
+		return "note"
+	}
+	return "warning"
+}
+
+// sarifPhysical returns the location of the position, relative to the working directory if possible.
+func sarifPhysical(filename string, line, column int) sarifPhysicalLocation {
+	artifact := sarifArtifactLocation{URI: "file://" + filepath.ToSlash(filename)}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			artifact = sarifArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: sarifSrcRoot}
+		}
+	}
+	return sarifPhysicalLocation{
+		ArtifactLocation: artifact,
+		Region:           sarifRegion{StartLine: line, StartColumn: column},
+	}
+}
@@ -0,0 +1,5 @@
+package broken
+
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID}
+}
@@ -0,0 +1,17 @@
+package conv
+
+type User struct {
+	ID, Name, Email string
+}
+
+type UserRow struct {
+	ID, Name, Email string
+}
+
+func ToUserRowPartial(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name}
+}
+
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID}
+}
@@ -0,0 +1,9 @@
+package conv
+
+import "testing"
+
+func TestToUserRow(t *testing.T) {
+	if ToUserRow(User{ID: "1"}).ID != "1" {
+		t.Fail()
+	}
+}
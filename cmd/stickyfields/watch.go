@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/build"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// watchDelay is how long changes are collected before analyzing again, as editors often write files in several steps.
+const watchDelay = 300 * time.Millisecond
+
+// watch analyzes the packages matching the patterns and, until interrupted, analyzes again
+// the packages owning changed files along with the matching packages importing them.
+func watch(w io.Writer, patterns []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	defer watcher.Close()
+
+	run(w, patterns)
+
+	var roots []*packages.Package
+	refresh := func() bool {
+		var err error
+		if roots, err = watchPackages(watcher, patterns); err != nil {
+			log.Print(err)
+			return false
+		}
+		return true
+	}
+	if !refresh() {
+		return 1
+	}
+
+	debounce(ctx, watcher, watchDelay, func(changed map[string]bool) {
+		affected := affectedPackages(roots, changed)
+		if len(affected) == 0 {
+			return
+		}
+		if config.Verbosity >= sf.VerbositySummary {
+			fmt.Fprintf(w, "\n[%s] analyzing %s\n", time.Now().Format(time.TimeOnly), strings.Join(affected, " "))
+		}
+		run(w, affected)
+		// Files and imports may have changed.
+		refresh()
+	})
+	return 0
+}
+
+// debounce calls analyze with directories of .go files changed according to the watcher,
+// once no file changed for the delay, until the context is done.
+func debounce(ctx context.Context, watcher *fsnotify.Watcher, delay time.Duration, analyze func(changed map[string]bool)) {
+	changed := make(map[string]bool)
+	timer := time.NewTimer(0)
+	<-timer.C
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-watcher.Errors:
+			log.Print(err)
+		case event := <-watcher.Events:
+			if filepath.Ext(event.Name) != ".go" || event.Op == fsnotify.Chmod {
+				continue
+			}
+			changed[filepath.Dir(event.Name)] = true
+			timer.Reset(delay)
+		case <-timer.C:
+			analyze(changed)
+			clear(changed)
+		}
+	}
+}
+
+// watchPackages loads the packages matching the patterns along with their imports
+// and watches directories of their files.
+func watchPackages(watcher *fsnotify.Watcher, patterns []string) ([]*packages.Package, error) {
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps
+	roots, err := packages.Load(&packages.Config{Mode: mode, Tests: config.IncludeTests}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	watched := watcher.WatchList()
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		for _, file := range pkg.GoFiles {
+			// Files of the standard library and the module cache don't change.
+			if dir := filepath.Dir(file); !slices.Contains(watched, dir) && !isReadOnly(dir) {
+				if err := watcher.Add(dir); err != nil {
+					log.Print(err)
+				}
+				watched = append(watched, dir)
+			}
+		}
+	})
+	return roots, nil
+}
+
+// isReadOnly reports whether the directory belongs to the standard library or the module cache.
+func isReadOnly(dir string) bool {
+	roots := []string{build.Default.GOROOT, os.Getenv("GOMODCACHE")}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		roots = append(roots, filepath.Join(gopath, "pkg", "mod"))
+	}
+	for _, root := range roots {
+		if root != "" && strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// affectedPackages returns directories of the root packages having files in the changed directories
+// or importing (directly or not) packages having files there.
+func affectedPackages(roots []*packages.Package, changed map[string]bool) []string {
+	affected := make(map[*packages.Package]bool)
+	var visit func(pkg *packages.Package) bool
+	visit = func(pkg *packages.Package) bool {
+		if v, ok := affected[pkg]; ok {
+			return v
+		}
+		affected[pkg] = false
+		for _, file := range pkg.GoFiles {
+			if changed[filepath.Dir(file)] {
+				affected[pkg] = true
+				return true
+			}
+		}
+		for _, imp := range pkg.Imports {
+			if visit(imp) {
+				affected[pkg] = true
+				return true
+			}
+		}
+		return false
+	}
+
+	var dirs []string
+	for _, pkg := range roots {
+		// Test variants share the directory of the package, test executables are generated.
+		if strings.HasSuffix(pkg.ID, ".test") || len(pkg.GoFiles) == 0 || !visit(pkg) {
+			continue
+		}
+		if dir := filepath.Dir(pkg.GoFiles[0]); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// writeFiles writes the files of the directory, creating their directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAffectedPackages(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"go.mod":         "module example.com/w\n\ngo 1.23\n",
+		"model/model.go": "package model\n\ntype User struct{ ID string }\n",
+		"conv/conv.go": `package conv
+
+import "example.com/w/model"
+
+func ID(u model.User) string { return u.ID }
+`,
+		"other/other.go": "package other\n",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	roots, err := watchPackages(watcher, []string{"./..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range []string{"model", "conv", "other"} {
+		if !slices.Contains(watcher.WatchList(), filepath.Join(dir, pkg)) {
+			t.Errorf("directory of package %s not watched: %v", pkg, watcher.WatchList())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		changed []string
+		want    []string
+	}{
+		{"importer", []string{"conv"}, []string{"conv"}},
+		// Packages importing the changed one are analyzed again too.
+		{"imported", []string{"model"}, []string{"conv", "model"}},
+		{"several", []string{"model", "other"}, []string{"conv", "model", "other"}},
+		{"outside packages", []string{"testdata"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changed := make(map[string]bool)
+			for _, pkg := range test.changed {
+				changed[filepath.Join(dir, pkg)] = true
+			}
+			var want []string
+			for _, pkg := range test.want {
+				want = append(want, filepath.Join(dir, pkg))
+			}
+			if got := affectedPackages(roots, changed); !slices.Equal(got, want) {
+				t.Errorf("affectedPackages() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	dir := t.TempDir()
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan map[string]bool, 10)
+	done := make(chan struct{})
+	const delay = 100 * time.Millisecond
+	go func() {
+		defer close(done)
+		debounce(ctx, watcher, delay, func(changed map[string]bool) {
+			// The set is cleared once analyzed.
+			calls <- maps.Clone(changed)
+		})
+	}()
+
+	// Editors write files in several steps: they're analyzed once, other files are ignored.
+	writeFiles(t, dir, map[string]string{"notes.txt": "notes"})
+	for _, content := range []string{"package a\n", "package a\n\ntype A struct{}\n"} {
+		writeFiles(t, dir, map[string]string{"a.go": content})
+		time.Sleep(delay / 4)
+	}
+	select {
+	case changed := <-calls:
+		if len(changed) != 1 || !changed[dir] {
+			t.Errorf("got changed directories %v, want %s", changed, dir)
+		}
+	case <-time.After(10 * delay):
+		t.Fatal("changes not analyzed")
+	}
+	select {
+	case changed := <-calls:
+		t.Errorf("changes analyzed again: %v", changed)
+	case <-time.After(3 * delay):
+	}
+
+	// Changes made after the analysis are analyzed on their own.
+	writeFiles(t, dir, map[string]string{"b.go": "package a\n"})
+	select {
+	case changed := <-calls:
+		if len(changed) != 1 || !changed[dir] {
+			t.Errorf("got changed directories %v, want %s", changed, dir)
+		}
+	case <-time.After(10 * delay):
+		t.Fatal("changes not analyzed")
+	}
+
+	cancel()
+	<-done
+}
@@ -2,93 +2,571 @@ package sf
 
 import (
 	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
-	"os"
+	"io/fs"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
-	"github.com/fatih/color"
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
-// Configuration variable for including methods (functions with receivers) in the check.
-// Set to false to consider only plain functions.
-var includeMethods = false
+// analyzerName is the name of the analyzer, e.g. in //nolint directives.
+const analyzerName = "stickyfields"
+
+// Analyzer is the stickyfields analyzer using the default configuration.
+var Analyzer = NewAnalyzer(DefaultConfig())
+
+// NewAnalyzer creates the stickyfields analyzer bound to the given configuration.
+// Configuration is also exposed via the analyzer's flags.
+func NewAnalyzer(cfg *Config) *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name: analyzerName,
+		Doc:  "reports all inconsistent converter functions: ensures sticky fields)",
+		Run: func(pass *analysis.Pass) (any, error) {
+			return Run(pass, cfg)
+		},
+		Requires:   []*analysis.Analyzer{inspect.Analyzer, FactsAnalyzer},
+		ResultType: reflect.TypeOf((*Result)(nil)),
+	}
+	// The SSA form of packages is shared with other analyzers of the driver in the precise mode.
+	// Otherwise, it's built only for packages enabling the mode (e.g. with a configuration file).
+	if cfg.Precise {
+		a.Requires = append(a.Requires, buildssa.Analyzer)
+	}
+	cfg.RegisterFlags(&a.Flags)
+
+	return a
+}
+
+// Result is the result of the analyzer for a package.
+type Result struct {
+	// Package is the import path of the package.
+	Package string
+	// Files contains the names of analyzed files (after applying filters).
+	Files []string
+	// Converters contains all validated converter functions of the package.
+	Converters []Converter
+	// Findings is the number of converters reported, not counting further diagnostics
+	// at their fields or literals.
+	Findings int
+}
+
+// Converter is a converter function found by the analyzer.
+type Converter struct {
+	// Name is the name of the function.
+	Name string
+	// Receiver is the name of the receiver type of methods.
+	Receiver string
+	// Pos is the position of the function name.
+	Pos token.Pos
+	// Mapping is the mapping table of the converter if Config.Mappings is set
+	// and the table could be extracted.
+	Mapping *ConverterMapping
+	ConverterValidationResult
+}
+
+// qualifiedName returns the name of the converter qualified by the receiver type for methods.
+func (c Converter) qualifiedName() string {
+	if c.Receiver != "" {
+		return c.Receiver + "." + c.Name
+	}
+	return c.Name
+}
+
+// ValidateFunc validates the function as a converter with the configuration of the package of the pass
+// (including configuration files and //sf:config directives), like the analyzer does but without the baseline.
+// A nil cfg stands for DefaultConfig. The pass may be one of any analyzer.
+func ValidateFunc(pass *analysis.Pass, fn *Func, cfg *Config) (ConverterValidationResult, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	cfg, err := cfg.forPackage(pass)
+	if err != nil {
+		return ConverterValidationResult{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return ConverterValidationResult{}, err
+	}
+	if cfg.Precise {
+		precise := *cfg
+		precise.ssa = newSSAFuncs(pass)
+		cfg = &precise
+	}
+	return ValidateConverter(fn, pass, cfg)
+}
 
 // Run function used in analysis.Analyzer
-func Run(pass *analysis.Pass) (any, error) {
-	color.NoColor = false
+func Run(pass *analysis.Pass, cfg *Config) (*Result, error) {
+	cfg, err := cfg.forPackage(pass)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
-	warningsTotal := 0
-	filesTotal := 0
-	filesWarned := 0
+	// Findings recorded in the baseline are not reported again.
+	if cfg.Baseline != "" {
+		baseline, err := ReadBaseline(resolvePath(pass, cfg.Baseline))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			// Packages of other modules (e.g. dependencies analyzed for facts) have no baseline.
+			cfg.logf(VerbosityNotices, "%s: no baseline: %v", pass.Pkg.Path(), err)
+		case err != nil:
+			return nil, err
+		}
+		withBaseline := *cfg
+		withBaseline.baseline = baseline
+		cfg = &withBaseline
+	}
 
+	// The SSA form of the package is built once, when the first converter needs it.
+	if cfg.Precise {
+		precise := *cfg
+		precise.ssa = newSSAFuncs(pass)
+		cfg = &precise
+	}
+
+	// Diagnostics are buffered to report the most important ones within the limit of the package.
+	var diagnostics []analysis.Diagnostic
+	if cfg.MaxIssues > 0 {
+		buffered := *pass
+		buffered.Report = func(d analysis.Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		}
+		defer reportLimited(pass, cfg.MaxIssues, &diagnostics)
+		pass = &buffered
+	}
+
+	in := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	registries := findRegistries(pass, in)
+	if cfg.CheckRegistries && cfg.enabled(CodeUnregistered) {
+		reportUnregisteredConverters(pass, cfg, registries)
+	}
+
+	result := &Result{Package: pass.Pkg.Path()}
+
+	files := make(map[*ast.File]bool)
 	for _, file := range pass.Files {
 		// Get the filename from the file position.
 		filename := pass.Fset.Position(file.Pos()).Filename
 
-		// Skip files that are test files or are in the vendor directory.
-		if strings.HasSuffix(filename, "_test.go") || strings.Contains(filepath.ToSlash(filename), "/vendor/") {
+		// Skip files in the vendor directory.
+		if strings.Contains(filepath.ToSlash(filename), "/vendor/") {
 			continue
 		}
 
-		filesTotal++
+		// Apply path filters to both the package import path and the file path.
+		if !cfg.pathAllowed(pass.Pkg.Path(), filepath.ToSlash(filename)) {
+			continue
+		}
 
-		// Walk the AST and look for function declarations.
-		var fileContainsWarnings bool
-		ast.Inspect(file, func(n ast.Node) bool {
-			if fn, ok := n.(*ast.FuncDecl); ok {
-				if !IsPossibleConverter(fn, pass) {
-					return true
-				}
+		// Skip test files unless asked otherwise.
+		if !cfg.IncludeTests && strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
 
-				validationResult, err := ValidateConverter(fn, pass)
-				if err != nil {
-					fmt.Println("--> Validation error, ignoring ", fn.Name.Name)
-					return true
-				}
+		// Skip generated files (goverter output, protoc-gen code, mocks) unless asked otherwise.
+		if !cfg.CheckGenerated && ast.IsGenerated(file) {
+			continue
+		}
 
-				if validationResult.Valid {
-					return true
-				}
+		result.Files = append(result.Files, filename)
+		files[file] = true
+	}
+
+	// Functions are checked concurrently, while findings (and messages) are reported
+	// in the order of files and functions afterwards, so they don't depend on scheduling.
+	checks := inspectFuncs(pass, in, registries, files)
+	parallel(len(checks), func(i int) {
+		checks[i].run(pass, cfg)
+	})
+
+	for _, check := range checks {
+		if cfg.Output != nil {
+			check.log.WriteTo(cfg.Output)
+		}
+		if !check.converter || check.err != nil {
+			continue
+		}
+		fn, validationResult := check.fn, check.result
+		converter := Converter{
+			Name:                      fn.Name,
+			Receiver:                  fn.receiverName(),
+			Pos:                       fn.NamePos,
+			ConverterValidationResult: validationResult,
+		}
+		if cfg.Mappings {
+			if mapping, err := ExtractMapping(fn, pass); err == nil {
+				converter.Mapping = &mapping
+			}
+		}
+		result.Converters = append(result.Converters, converter)
+
+		report := func(d analysis.Diagnostic) {
+			reportFunc(pass, cfg, check.filename, fn, d)
+			result.Findings++
+		}
 
-				message := fmt.Sprintf(
-					"converter function is leaking fields:\n missing input fields: %v\n missing output fields: %v",
-					validationResult.MissingInputFields,
-					validationResult.MissingOutputFields,
-				)
+		if cfg.ReportUnkeyed && cfg.reports(pass, fn, CodeUnkeyedLiteral) {
+			for _, cl := range validationResult.UnkeyedLiterals {
+				pass.Report(analysis.Diagnostic{
+					Pos:      cl.Pos(),
+					Category: CodeUnkeyedLiteral,
+					Message: withCodes(fmt.Sprintf("unkeyed composite literal of %s: prefer keyed fields",
+						types.TypeString(pass.TypesInfo.TypeOf(cl), types.RelativeTo(pass.Pkg))), CodeUnkeyedLiteral),
+				})
+			}
+		}
 
-				var buf bytes.Buffer
-				PrettyPrint(&buf, filename, fn, pass, message)
+		if cfg.CheckNaming && cfg.reports(pass, fn, CodeNaming) {
+			if violation := namingViolation(pass, cfg, fn); violation != "" {
+				report(analysis.Diagnostic{
+					Category: CodeNaming,
+					Message:  withCodes(violation, CodeNaming),
+				})
+			}
+		}
 
-				// Now report the diagnostic using pass.Report.
+		if cfg.CheckNilResults && cfg.reports(pass, fn, CodeNilResult) {
+			for _, expr := range nilResultDerefs(fn) {
 				pass.Report(analysis.Diagnostic{
-					Pos:     fn.Name.Pos(),
-					Message: buf.String(),
+					Pos:      expr.Pos(),
+					End:      expr.End(),
+					Category: CodeNilResult,
+					Message: withCodes(fmt.Sprintf("%s dereferences the named result before it's assigned: it's nil there",
+						types.ExprString(expr)), CodeNilResult),
 				})
+			}
+		}
 
-				warningsTotal++
-				fileContainsWarnings = true
+		if cfg.CheckErrors && cfg.reports(pass, fn, CodeMissingError) {
+			if failures := unreturnedFailures(fn); len(failures) > 0 {
+				report(analysis.Diagnostic{
+					Category: CodeMissingError,
+					Message:  withCodes(missingErrorMessage(failures), CodeMissingError),
+				})
+			}
+		}
+		if cfg.CheckErrors && cfg.reports(pass, fn, CodeNilInput) {
+			if input := uncheckedNilInput(pass, fn); input != "" {
+				report(analysis.Diagnostic{
+					Category: CodeNilInput,
+					Message: withCodes("converter function dereferences its pointer input "+input+" without checking it for nil",
+						CodeNilInput),
+				})
 			}
-			return true
+		}
+
+		if cfg.CheckPurity && cfg.reports(pass, fn, CodeInputMutation) {
+			for _, m := range inputMutations(pass, fn) {
+				pass.Report(analysis.Diagnostic{
+					Pos:      m.pos,
+					Category: CodeInputMutation,
+					Message:  withCodes(m.message, CodeInputMutation),
+				})
+			}
+		}
+
+		if cfg.CheckLayers && cfg.reports(pass, fn, CodeLayering) {
+			if violation := layerViolation(pass, cfg, fn); violation != "" {
+				report(analysis.Diagnostic{
+					Category: CodeLayering,
+					Message:  withCodes(violation, CodeLayering),
+				})
+			}
+		}
+
+		if len(validationResult.HardcodedFields) > 0 {
+			report(analysis.Diagnostic{
+				Category: CodeHardcoded,
+				Message: withCodes(fmt.Sprintf("converter function hardcodes output fields instead of mapping input ones: %v",
+					validationResult.HardcodedFields), CodeHardcoded),
+			})
+		}
+
+		if len(validationResult.DuplicateWrites) > 0 {
+			report(analysis.Diagnostic{
+				Category: CodeDuplicateWrites,
+				Message: withCodes(fmt.Sprintf("converter function writes output fields more than once: %v",
+					validationResult.DuplicateWrites), CodeDuplicateWrites),
+			})
+		}
+
+		if len(validationResult.SwappedFields) > 0 {
+			report(analysis.Diagnostic{
+				Category: CodeSwapped,
+				Message: withCodes(fmt.Sprintf("converter function possibly swaps fields: %v",
+					validationResult.SwappedFields), CodeSwapped),
+			})
+		}
+
+		if validationResult.Valid {
+			continue
+		}
+
+		if validationResult.UnknownCoverage != "" {
+			if cfg.reports(pass, fn, CodeUnknownCoverage) {
+				report(analysis.Diagnostic{
+					Category: CodeUnknownCoverage,
+					Message: withCodes("converter field coverage unknown: "+validationResult.UnknownCoverage,
+						CodeUnknownCoverage),
+				})
+			}
+			continue
+		}
+
+		if cfg.PerFieldDiagnostics {
+			reportPerField(pass, cfg, fn, validationResult)
+			result.Findings++
+			continue
+		}
+
+		missingIn, missingOut, more := limitFields(cfg.MaxIssuesPerFunc,
+			validationResult.MissingInputFields, validationResult.MissingOutputFields)
+		message := "converter function is leaking fields:"
+		if cfg.checksInput() {
+			message += fmt.Sprintf("\n missing input fields: %v", missingIn)
+		}
+		if cfg.checksOutput() {
+			message += fmt.Sprintf("\n missing output fields: %v", missingOut)
+		}
+		if more > 0 {
+			message += fmt.Sprintf("\n and %d more missing fields", more)
+		}
+		if cfg.MinCoverage < 1 {
+			message += fmt.Sprintf("\n coverage: %.0f%%", validationResult.Coverage*100)
+		}
+		if len(validationResult.UnhandledOneofs) > 0 {
+			message += fmt.Sprintf("\n unhandled oneof cases: %v", validationResult.UnhandledOneofs)
+		}
+		if len(validationResult.UnmappedFields) > 0 {
+			message += fmt.Sprintf("\n unmapped fields: %v", validationResult.UnmappedFields)
+		}
+		for _, suggestion := range validationResult.Suggestions {
+			message += "\n " + suggestion.String()
+		}
+
+		codes := validationResult.codes()
+		report(analysis.Diagnostic{
+			Category:       cfg.mostSevere(codes),
+			Message:        withCodes(message, codes...),
+			Related:        relatedInformation(validationResult, missingIn, missingOut),
+			SuggestedFixes: suppressFix(fn, validationResult),
 		})
-		if fileContainsWarnings {
-			filesWarned++
+	}
+
+	if cfg.CheckRoundTrips && cfg.enabled(CodeRoundTrip) {
+		result.Findings += reportRoundTrips(pass, cfg, checks)
+	}
+	if cfg.CheckReverse && cfg.enabled(CodeMissingReverse) {
+		result.Findings += reportMissingReverse(pass, cfg, checks)
+	}
+	if cfg.CheckDuplicates && cfg.enabled(CodeDuplicateConverter) {
+		result.Findings += reportDuplicateConverters(pass, cfg, checks)
+	}
+
+	return result, nil
+}
+
+// suppressFix returns a suggested fix inserting the //sf:ignore directive listing missing fields
+// above the converter, for intentionally partial converters. Function literals have no doc comment to hold it.
+func suppressFix(fn *Func, result ConverterValidationResult) []analysis.SuggestedFix {
+	if fn.Decl == nil {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range slices.Concat(result.MissingInputFields, result.MissingOutputFields) {
+		name := field[strings.LastIndex(field, ".")+1:]
+		if !slices.Contains(fields, name) {
+			fields = append(fields, name)
 		}
 	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	directive := ignoreDirective + " " + strings.Join(fields, " ")
+	return []analysis.SuggestedFix{{
+		Message: "Suppress with " + directive,
+		TextEdits: []analysis.TextEdit{{
+			Pos:     fn.Decl.Pos(),
+			End:     fn.Decl.Pos(),
+			NewText: []byte(directive + "\n"),
+		}},
+	}}
+}
 
-	// At the end of processing all files, print the total number of warnings.
-	// Probably temporarily: More for debug purposes.
-	// TODO: find a nice way to output reports in linters
-	if warningsTotal > 0 {
-		fmt.Fprintf(os.Stdout, "\nFiles total analyzed: %d. Warnings: %d caught in %d files\n", filesTotal, warningsTotal, filesWarned)
-	} else {
-		fmt.Fprintf(os.Stdout, "\nFiles total analyzed: %d. Warnings: 0\n", filesTotal)
+// reportFunc reports the diagnostic at the function name,
+// pretty-printing it along with the source line if configured so.
+func reportFunc(pass *analysis.Pass, cfg *Config, filename string, fn *Func, d analysis.Diagnostic) {
+	d.Pos = fn.NamePos
+	if cfg.Pretty {
+		var buf bytes.Buffer
+		PrettyPrint(&buf, filename, fn, pass, d.Message, cfg.colored())
+		d.Message = buf.String()
 	}
+	pass.Report(d)
+}
 
-	return nil, nil
+// relatedInformation returns declarations of the models and their missing fields along with positions
+// where the missing fields should be handled as related information, so editors can jump to them.
+func relatedInformation(result ConverterValidationResult, missingIn, missingOut []string) []analysis.RelatedInformation {
+	var related []analysis.RelatedInformation
+	add := func(kind, model string, modelPos token.Pos, fields []string) {
+		if len(fields) == 0 {
+			return
+		}
+		if modelPos.IsValid() {
+			related = append(related, analysis.RelatedInformation{
+				Pos:     modelPos,
+				Message: kind + " model " + model + " declared here",
+			})
+		}
+		for _, field := range fields {
+			for _, pos := range result.FieldPositions[field] {
+				related = append(related, analysis.RelatedInformation{
+					Pos:     pos,
+					Message: "missing " + kind + " field " + field,
+				})
+			}
+			related = append(related, fieldDeclRelated(result, kind, field)...)
+		}
+	}
+	add("input", result.InputType, result.InputTypePos, missingIn)
+	add("output", result.OutputType, result.OutputTypePos, missingOut)
+	return related
+}
+
+// fieldDeclRelated returns the declaration of the missing field as related information, if known.
+func fieldDeclRelated(result ConverterValidationResult, kind, field string) []analysis.RelatedInformation {
+	pos := result.FieldDecls[field]
+	if !pos.IsValid() {
+		return nil
+	}
+	return []analysis.RelatedInformation{{
+		Pos:     pos,
+		Message: kind + " field " + field + " declared here",
+	}}
+}
+
+// limitFields limits the number of missing fields reported for a converter (0 means no limit).
+// Missing output fields take precedence over input ones. The number of omitted fields is returned as more.
+func limitFields(limit int, missingIn, missingOut []string) (in, out []string, more int) {
+	if limit <= 0 || len(missingIn)+len(missingOut) <= limit {
+		return missingIn, missingOut, 0
+	}
+	out = missingOut[:min(len(missingOut), limit)]
+	in = missingIn[:min(len(missingIn), limit-len(out))]
+	return in, out, len(missingIn) + len(missingOut) - len(in) - len(out)
+}
+
+// reportLimited reports at most limit of the diagnostics, prioritizing missing output fields
+// and leaving missing input fields for last, followed by the number of omitted ones.
+func reportLimited(pass *analysis.Pass, limit int, diagnostics *[]analysis.Diagnostic) {
+	priority := func(d analysis.Diagnostic) int {
+		switch d.Category {
+		case CodeMissingOutput:
+			return 0
+		case CodeMissingInput:
+			return 2
+		}
+		return 1
+	}
+
+	kept := slices.Clone(*diagnostics)
+	slices.SortStableFunc(kept, func(a, b analysis.Diagnostic) int {
+		return priority(a) - priority(b)
+	})
+	var more int
+	if len(kept) > limit {
+		kept, more = kept[:limit], len(kept)-limit
+	}
+	slices.SortStableFunc(kept, func(a, b analysis.Diagnostic) int {
+		return cmp.Compare(a.Pos, b.Pos)
+	})
+
+	for _, d := range kept {
+		pass.Report(d)
+	}
+	if more > 0 {
+		pass.Report(analysis.Diagnostic{
+			Pos:      pass.Files[0].Name.Pos(),
+			Category: CategoryOmitted,
+			Message:  fmt.Sprintf("and %d more findings in the package (see -max-issues)", more),
+		})
+	}
+}
+
+// reportPerField reports every missing field with a separate diagnostic positioned where the fix goes.
+func reportPerField(pass *analysis.Pass, cfg *Config, fn *Func, result ConverterValidationResult) {
+	suggestions := make(map[string]string, len(result.Suggestions))
+	for _, s := range result.Suggestions {
+		suggestions[s.Output] = s.Input
+	}
+
+	missingIn, missingOut, more := limitFields(cfg.MaxIssuesPerFunc, result.MissingInputFields, result.MissingOutputFields)
+	if cfg.checksInput() {
+		for _, field := range missingIn {
+			for _, pos := range result.FieldPositions[field] {
+				pass.Report(analysis.Diagnostic{
+					Pos:      pos,
+					Category: CodeMissingInput,
+					Message:  withCodes("missing input field "+field, CodeMissingInput),
+					Related:  fieldDeclRelated(result, "input", field),
+				})
+			}
+		}
+	}
+	if cfg.checksOutput() {
+		for _, field := range missingOut {
+			message := "missing output field " + field
+			if src, ok := suggestions[field]; ok {
+				message += ": did you mean " + src + "?"
+			}
+			for _, pos := range result.FieldPositions[field] {
+				pass.Report(analysis.Diagnostic{
+					Pos:      pos,
+					Category: CodeMissingOutput,
+					Message:  withCodes(message, CodeMissingOutput),
+					Related:  fieldDeclRelated(result, "output", field),
+				})
+			}
+		}
+	}
+	for _, oneof := range result.UnhandledOneofs {
+		pass.Report(analysis.Diagnostic{
+			Pos:      fn.NamePos,
+			Category: CodeUnhandledOneof,
+			Message:  withCodes("unhandled oneof case "+oneof, CodeUnhandledOneof),
+		})
+	}
+	for _, mapping := range result.UnmappedFields {
+		pass.Report(analysis.Diagnostic{
+			Pos:      fn.NamePos,
+			Category: CodeUnmapped,
+			Message:  withCodes("unmapped field "+mapping, CodeUnmapped),
+		})
+	}
+	if more > 0 {
+		pass.Report(analysis.Diagnostic{
+			Pos:      fn.NamePos,
+			Category: CategoryOmitted,
+			Message:  fmt.Sprintf("and %d more missing fields (see -max-issues-per-func)", more),
+		})
+	}
 }
 
 // ContainerType represents the “container” kind for a candidate type.
@@ -106,15 +584,82 @@ type candidate struct {
 	name          string
 	containerType ContainerType
 	structType    *types.Struct
+	typeName      *types.TypeName
+}
+
+// String returns the candidate's type name along with its container, e.g. []Event.
+func (c candidate) String() string {
+	switch c.containerType {
+	case ContainerPointer:
+		return "*" + c.name
+	case ContainerSlice:
+		return "[]" + c.name
+	case ContainerMap:
+		return "map[...]" + c.name
+	}
+	return c.name
+}
+
+// Candidate is the struct a converter converts from (its input) or into (its output).
+type Candidate struct {
+	// Var is the name of the parameter or result. It's empty for unnamed results.
+	Var string
+	// Type is the named struct type.
+	Type *types.TypeName
+	// Struct is the underlying struct type.
+	Struct *types.Struct
+	// Container is how the struct is passed: plainly, by pointer, in a slice or in a map.
+	Container ContainerType
+}
+
+// Candidates returns the input and output candidates of the function as ValidateConverter pairs them:
+// the first struct parameter and the first struct result (or the concrete struct returned
+// as an interface result).
+func Candidates(fn *Func, pass *analysis.Pass) (in, out Candidate, err error) {
+	inCand, inVar, outCand, outVar, err := candidates(fn, pass)
+	if err != nil {
+		return Candidate{}, Candidate{}, err
+	}
+	in = Candidate{Var: inVar, Type: inCand.typeName, Struct: inCand.structType, Container: inCand.containerType}
+	out = Candidate{Var: outVar, Type: outCand.typeName, Struct: outCand.structType, Container: outCand.containerType}
+	return in, out, nil
+}
+
+// candidates finds the candidate input parameter and output result of the function along with their names.
+func candidates(fn *Func, pass *analysis.Pass) (inCand candidate, inVar string, outCand candidate, outVar string, err error) {
+	sig := fn.Signature
+	if sig == nil {
+		return inCand, "", outCand, "", fmt.Errorf("cannot get type info for function %q", fn.Name)
+	}
+
+	// Find the candidate input parameter.
+	inCand, inVar, okIn := findCandidateParam(fn.Type.Params, sig.Params())
+	if !okIn || inVar == "" {
+		return inCand, "", outCand, "", fmt.Errorf("cannot determine candidate input parameter for function %q", fn.Name)
+	}
+
+	// Determine the candidate output parameter.
+	outCand, outVar, okOut := findCandidateParam(fn.Type.Results, sig.Results())
+	if !okOut {
+		// The result may be an interface implemented by a returned concrete struct.
+		if concrete := concreteResultCandidates(fn, pass); len(concrete) > 0 {
+			outCand, outVar, okOut = concrete[0], "", true
+		}
+	}
+	if !okOut {
+		return inCand, "", outCand, "", fmt.Errorf("cannot determine candidate output parameter for function %q", fn.Name)
+	}
+	return inCand, inVar, outCand, outVar, nil
 }
 
 // extractCandidateType checks if the given type qualifies as a candidate for conversion.
 // It recognizes a plain struct, a pointer to a struct, a slice/array of such types,
 // or a map whose value is such a type. If so, it returns the candidate (with its
 // underlying type name and container type) and ok==true. Otherwise, ok==false.
+// Type aliases are resolved, so candidates are always named after the aliased type.
 func extractCandidateType(t types.Type) (cand candidate, ok bool) {
 	// First, check for containers.
-	switch tt := t.(type) {
+	switch tt := types.Unalias(t).(type) {
 	case *types.Slice, *types.Array:
 		cand.containerType = ContainerSlice
 		var elem types.Type
@@ -133,15 +678,15 @@ func extractCandidateType(t types.Type) (cand candidate, ok bool) {
 	}
 
 	// If the type is a pointer and not already a container, mark it as pointer.
-	if ptr, okPtr := t.(*types.Pointer); okPtr {
+	if ptr, okPtr := types.Unalias(t).(*types.Pointer); okPtr {
 		if cand.containerType == ContainerNone {
 			cand.containerType = ContainerPointer
 		}
 		t = ptr.Elem()
 	}
 
-	// We expect a named type whose underlying type is a struct.
-	named, okNamed := t.(*types.Named)
+	// We expect a named type (possibly behind an alias) whose underlying type is a struct.
+	named, okNamed := types.Unalias(t).(*types.Named)
 	if !okNamed {
 		return candidate{}, false
 	}
@@ -151,60 +696,112 @@ func extractCandidateType(t types.Type) (cand candidate, ok bool) {
 	}
 	cand.name = named.Obj().Name()
 	cand.structType = st
+	cand.typeName = named.Obj()
 	return cand, true
 }
 
-// IsPossibleConverter checks whether fn (a function declaration)
+// IsPossibleConverter checks whether fn (a function declaration or literal)
 // qualifies as a potential converter function based on these rules:
 //   - At least one input and one output candidate exist.
 //   - Candidate is the argument who fits the candidate type (struct or pointer to struct).
 //   - For at least one candidate pair (input, output) with the same container type,
 //     the names of the candidate types share a common substring (ignoring case).
+//     The name check is skipped for functions placed into converter registries.
 //
 // TODO: it can't be the same type e.g. HandleRewrites(sectionRewrites) (string, SectionRewrite, erro)
-func IsPossibleConverter(fn *ast.FuncDecl, pass *analysis.Pass) bool {
+func IsPossibleConverter(fn *Func, pass *analysis.Pass, cfg *Config) bool {
+	// The decision trail is written for the explained function (see Config.Explain),
+	// pairing traces also with the tracing verbosity.
+	explained := cfg.Explain != "" && cfg.Explain == fn.Name
+	explain := func(format string, args ...any) {
+		if explained {
+			cfg.logf(VerbosityQuiet, "%s: %s: "+format, append([]any{pass.Fset.Position(fn.NamePos), fn.Name}, args...)...)
+		}
+	}
+	trace := func(inCand, outCand candidate, format string, args ...any) {
+		level := VerbosityTrace
+		if explained {
+			level = VerbosityQuiet
+		}
+		cfg.logf(level, "%s: %s: %s -> %s: "+format,
+			append([]any{pass.Fset.Position(fn.NamePos), fn.Name, inCand, outCand}, args...)...)
+	}
+
 	// If we're not including methods and this function has a receiver, skip it.
-	if !includeMethods && fn.Recv != nil {
+	if !cfg.IncludeMethods && fn.Recv != nil {
+		explain("not a converter: methods are not checked (see -include-methods)")
+		return false
+	}
+
+	// Only the exported conversion surface is checked if asked so.
+	if cfg.ExportedOnly && !ast.IsExported(fn.Name) {
+		explain("not a converter: not exported (see -exported-only)")
+		return false
+	}
+
+	// Respect naming conventions configured by the user.
+	if !cfg.FuncPattern.MatchString(fn.Name) {
+		explain("not a converter: name does not match -func-pattern %s", cfg.FuncPattern.String())
 		return false
 	}
 
-	obj := pass.TypesInfo.Defs[fn.Name]
-	if obj == nil {
+	// Functions without body (e.g. implemented in assembly) have nothing to check.
+	if fn.Body == nil {
+		explain("not a converter: no body")
 		return false
 	}
 
-	sig, ok := obj.Type().(*types.Signature)
-	if !ok {
+	sig := fn.Signature
+	if sig == nil {
+		explain("not a converter: no type information")
 		return false
 	}
 
 	// No arguments: nothing was converted
 	if sig.Params().Len() == 0 || sig.Results().Len() == 0 {
+		explain("not a converter: no parameters or no results")
 		return false
 	}
 
-	// Gather candidate types from input parameters.
-	var inCandidates []candidate
-	for i := 0; i < sig.Params().Len(); i++ {
-		param := sig.Params().At(i)
-		if cand, ok := extractCandidateType(param.Type()); ok {
-			inCandidates = append(inCandidates, cand)
+	// candidates returns candidates of the tuple, explaining rejected ones.
+	candidates := func(kind string, tuple *types.Tuple) []candidate {
+		var cands []candidate
+		for i := 0; i < tuple.Len(); i++ {
+			typ := types.TypeString(tuple.At(i).Type(), types.RelativeTo(pass.Pkg))
+			cand, ok := extractCandidateType(tuple.At(i).Type())
+			switch {
+			case !ok:
+				explain("%s %d (%s): not a struct, pointer to struct, slice or map of structs", kind, i, typ)
+			case cfg.ProtoAware && isWellKnownType(cand.typeName):
+				explain("%s %d (%s): well-known proto type", kind, i, typ)
+			case !cfg.sizeAllowed(cand.structType):
+				explain("%s %d (%s): struct size out of -min-fields/-max-fields", kind, i, typ)
+			default:
+				explain("%s %d (%s): candidate %s", kind, i, typ, cand)
+				cands = append(cands, cand)
+			}
 		}
+		return cands
 	}
+
+	// Gather candidate types from input parameters.
+	inCandidates := candidates("parameter", sig.Params())
 	if len(inCandidates) == 0 {
+		explain("not a converter: no input candidates")
 		return false
 	}
 
 	// Gather candidate types from output parameters.
-	var outCandidates []candidate
-	for i := 0; i < sig.Results().Len(); i++ {
-		res := sig.Results().At(i)
-
-		if cand, ok := extractCandidateType(res.Type()); ok {
+	outCandidates := candidates("result", sig.Results())
+	// Interface results are represented by concrete structs returned in the body.
+	for _, cand := range concreteResultCandidates(fn, pass) {
+		if cfg.sizeAllowed(cand.structType) {
+			explain("returned concrete type: candidate %s", cand)
 			outCandidates = append(outCandidates, cand)
 		}
 	}
 	if len(outCandidates) == 0 {
+		explain("not a converter: no output candidates")
 		return false
 	}
 
@@ -214,54 +811,104 @@ func IsPossibleConverter(fn *ast.FuncDecl, pass *analysis.Pass) bool {
 	//    - otherwise, if the input candidate is a plain struct or pointer to struct, the output candidate
 	//      must also be a plain struct or pointer (i.e. not a slice or map).
 	// - And the candidate names share a common substring (ignoring case).
+	//   Unless the name heuristic is disabled: then the candidate types only have to differ.
 	for _, inCand := range inCandidates {
 		lowerIn := strings.ToLower(inCand.name)
 		for _, outCand := range outCandidates {
 			// Check container type compatibility.
 			if inCand.containerType == ContainerSlice || inCand.containerType == ContainerMap {
 				if inCand.containerType != outCand.containerType {
+					trace(inCand, outCand, "incompatible containers")
 					continue // e.g. slice -> non-slice is not allowed.
 				}
 			} else {
 				// inCand is ContainerNone or ContainerPointer.
 				// Allow output to be either a plain struct or a pointer.
 				if outCand.containerType != ContainerNone && outCand.containerType != ContainerPointer {
+					trace(inCand, outCand, "incompatible containers")
 					continue
 				}
 			}
 
+			// Functions placed into registries are converters regardless of the names.
+			if fn.Registered {
+				trace(inCand, outCand, "registered converter")
+				return true
+			}
+
+			// Without the name heuristic, converting a struct into a different one is enough.
+			if !cfg.NameHeuristic {
+				if inCand.typeName != outCand.typeName {
+					trace(inCand, outCand, "distinct types")
+					return true
+				}
+				trace(inCand, outCand, "same type")
+				continue
+			}
+
 			lowerOut := strings.ToLower(outCand.name)
 			if strings.Contains(lowerOut, lowerIn) || strings.Contains(lowerIn, lowerOut) {
+				trace(inCand, outCand, "names match")
 				return true
 			}
+			trace(inCand, outCand, "names differ")
 		}
 	}
 
+	explain("not a converter: no candidate pair matched")
 	return false
 }
 
-// collectMissingFields is similar to checkAllFieldsUsed but returns a slice of missing field names.
-func collectMissingFields(st *types.Struct, usedFields UsageLookup, usedMethodsArg ...UsageLookup) []string {
+// collectMissingFields is similar to checkAllFieldsUsed but returns a slice of missing field names
+// of the indexed struct. Fields listed in skipped are never reported.
+func collectMissingFields(index *fieldIndex, skipped, usedFields *UsageLookup, usedMethodsArg ...*UsageLookup) []string {
+	skipped, usedFields = skipped.bind(index), usedFields.bind(index)
 	var missing []string
-	for i := 0; i < st.NumFields(); i++ {
-		field := st.Field(i)
-		// adjust this as needed.
-		if !field.Exported() {
+	for i, name := range index.names {
+		if skipped.has(i) {
 			continue
 		}
 
-		if !usedFields.LookUp(field.Name()) {
+		if !usedFields.has(i) {
 			// if methods were given, let's allow via getters
 			// If a getter method exists (for input candidate) then allow it.
-			if len(usedMethodsArg) > 0 && usedMethodsArg[0].LookUp("Get"+field.Name()) {
+			if len(usedMethodsArg) > 0 && usedMethodsArg[0].LookUp("Get"+name) {
 				continue
 			}
-			missing = append(missing, field.Name())
+			missing = append(missing, name)
 		}
 	}
 	return missing
 }
 
+// fieldDecl returns the position of the declaration of the struct's field, or token.NoPos if there is none.
+// Fields promoted from embedded structs are declared in those.
+func fieldDecl(st *types.Struct, name string) token.Pos {
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Name() == name {
+			return field.Pos()
+		}
+	}
+	if field, _, _ := types.LookupFieldOrMethod(st, false, nil, name); field != nil {
+		if _, ok := field.(*types.Var); ok {
+			return field.Pos()
+		}
+	}
+	return token.NoPos
+}
+
+// requiredFields returns the exported fields of the indexed struct (see structIndex) converters are required to map.
+func requiredFields(index *fieldIndex, skipped *UsageLookup) []string {
+	skipped = skipped.bind(index)
+	var names []string
+	for i, name := range index.names {
+		if !skipped.has(i) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // ConverterValidationResult holds the details of a converter function validation.
 type ConverterValidationResult struct {
 	// Valid is true if every exported field (or getter methods) in
@@ -273,6 +920,45 @@ type ConverterValidationResult struct {
 	// MissingOutputFields contains the names of exported fields in the output candidate
 	// that were not used.
 	MissingOutputFields []string
+	// UnmappedFields contains configured field mappings (input -> output)
+	// whose output field was not assigned from the mapped input field.
+	UnmappedFields []string
+	// HardcodedFields contains output fields assigned only constants while the same-named
+	// input field exists and is not mapped anywhere. They are not taken into account by Valid.
+	HardcodedFields []string
+	// SwappedFields contains output fields assigned from a different input field while
+	// the same-named input field is unused. They are not taken into account by Valid.
+	SwappedFields []string
+	// FieldPositions maps missing input and output fields to positions where they should be handled:
+	// the input parameter, output literals lacking the field or the end of the function body.
+	FieldPositions map[string][]token.Pos
+	// FieldDecls maps missing input and output fields to positions of their declarations in the models.
+	FieldDecls map[string]token.Pos
+	// Suggestions contains likely input sources of missing output fields.
+	Suggestions []FieldSuggestion
+	// DuplicateWrites contains output fields written more than once on the same path.
+	// They are not taken into account by Valid.
+	DuplicateWrites []string
+	// UnkeyedLiterals contains composite literals of the output model initializing fields by their positions.
+	UnkeyedLiterals []*ast.CompositeLit
+	// InputType and OutputType are the input and output models of the converter.
+	InputType, OutputType string
+	// InputTypePos and OutputTypePos are positions of declarations of the input and output models.
+	InputTypePos, OutputTypePos token.Pos
+	// InputModel and OutputModel are the named types of the input and output models.
+	// They're set for converters covering all fields or of unknown coverage too.
+	InputModel, OutputModel *types.TypeName
+	// InputFields and OutputFields contain the fields of the input and output models
+	// the converter is required to map (in the form of missing fields).
+	InputFields, OutputFields []string
+	// Coverage is the ratio of required input and output fields that were used.
+	Coverage float64
+	// UnhandledOneofs contains oneof variants of proto messages (field: variant)
+	// that the converter does not handle.
+	UnhandledOneofs []string
+	// UnknownCoverage explains why field coverage of the converter can't be determined
+	// (e.g. it relies on a reflective copy). Empty when coverage is known.
+	UnknownCoverage string
 }
 
 // ValidateConverter checks that the converter function fn uses every field
@@ -280,57 +966,513 @@ type ConverterValidationResult struct {
 //
 // For input, we assume the candidate comes from the first parameter and that it has a name.
 // For output, we first try to use a named result; if none, we look for a composite literal.
-func ValidateConverter(fn *ast.FuncDecl, pass *analysis.Pass) (ConverterValidationResult, error) {
-	// Retrieve the function object and signature.
-	obj := pass.TypesInfo.Defs[fn.Name]
-	if obj == nil {
-		return ConverterValidationResult{}, fmt.Errorf("cannot get type info for function %q", fn.Name.Name)
-	}
-	sig, ok := obj.Type().(*types.Signature)
-	if !ok {
-		return ConverterValidationResult{}, fmt.Errorf("function %q does not have a valid signature", fn.Name.Name)
+//
+// Configured field mappings are honored only when the output field is assigned from the mapped
+// input field: then both fields are considered used, otherwise the mapping is reported as unmapped.
+func ValidateConverter(fn *Func, pass *analysis.Pass, cfg *Config) (ConverterValidationResult, error) {
+	// Retrieve the function signature.
+	sig := fn.Signature
+	if sig == nil {
+		return ConverterValidationResult{}, fmt.Errorf("cannot get type info for function %q", fn.Name)
 	}
 	if sig.Params().Len() < 1 || sig.Results().Len() < 1 {
-		return ConverterValidationResult{}, fmt.Errorf("function %q must have at least one parameter and one result", fn.Name.Name)
+		return ConverterValidationResult{}, fmt.Errorf("function %q must have at least one parameter and one result", fn.Name)
 	}
 
-	// Find the candidate input parameter.
-	inCand, inVar, okIn := findCandidateParam(fn.Type.Params, sig.Params())
-	if !okIn || inVar == "" {
-		return ConverterValidationResult{}, fmt.Errorf("cannot determine candidate input parameter for function %q", fn.Name.Name)
+	inCand, inVar, outCand, outVar, err := candidates(fn, pass)
+	if err != nil {
+		return ConverterValidationResult{}, err
 	}
 
-	// Determine the candidate output parameter.
-	outCand, outVar, okOut := findCandidateParam(fn.Type.Results, sig.Results())
-	if !okOut {
-		return ConverterValidationResult{}, fmt.Errorf("cannot determine candidate output parameter for function %q", fn.Name.Name)
+	// Converters can opt out of mapping some fields (or of the check entirely).
+	funcIgnored, ignoreAll := fn.ignoredFields()
+	// Partial converters declare the fields they leave unmapped per side.
+	partialIn, partialOut := fn.partialFields()
+	// Fields tagged as required are checked even then.
+	tagged := len(taggedRequired(inCand.structType)) > 0 || len(taggedRequired(outCand.structType)) > 0
+	if ignoreAll && !tagged {
+		return ConverterValidationResult{Valid: true, Coverage: 1, InputModel: inCand.typeName, OutputModel: outCand.typeName}, nil
+	}
+
+	in := variable{name: inVar, obj: fn.variable(inVar)}
+
+	// Reflective copy helpers of the input cover fields invisibly for the static analysis.
+	if _, callee := findCallWithVar(pass, fn.Body, cfg.ReflectiveCopyFuncs, in); callee != nil {
+		if cfg.ReflectiveCopy != ReflectiveCopyCovered {
+			return ConverterValidationResult{
+				UnknownCoverage: "reflective copy via " + shortFuncName(callee),
+				InputModel:      inCand.typeName,
+				OutputModel:     outCand.typeName,
+			}, nil
+		}
+		if !tagged {
+			return ConverterValidationResult{Valid: true, Coverage: 1, InputModel: inCand.typeName, OutputModel: outCand.typeName}, nil
+		}
+		// The copy is trusted with all fields but the ones tagged as required: they're mapped explicitly.
+		ignoreAll = true
 	}
 
 	// Collect field usages for the input candidate variable.
-	fieldsUsedModelIn := CollectUsedFields(fn.Body, inVar)
-	methodsUsedModelIn := CollectUsedMethods(fn.Body, inVar)
-	missingIn := collectMissingFields(inCand.structType, fieldsUsedModelIn, methodsUsedModelIn)
-	for i, m := range missingIn {
-		missingIn[i] = inVar + "." + m
+	// Reads feeding only logging or metrics calls are not conversions.
+	ignored := findCalls(pass, fn.Body, cfg.IgnoreReadsIn)
+	// Neither are reads whose values are discarded, if asked so.
+	var blankReads []ast.Node
+	if cfg.IgnoreBlankReads {
+		blankReads = blankAssignments(fn.Body)
+		ignored = append(ignored, blankReads...)
+	}
+	fieldsUsedModelIn, methodsUsedModelIn := NewUsageLookup(), NewUsageLookup()
+	inObjects := make(map[types.Object]bool)
+	collectIn := func(v variable) {
+		if v.obj != nil {
+			inObjects[v.obj] = true
+		}
+		fieldsUsedModelIn.AddAll(v.collector(fn.info, RecordFields).Skip(ignored...).Walk(fn.Body))
+		methodsUsedModelIn.AddAll(v.collector(fn.info, RecordMethods).Skip(ignored...).Walk(fn.Body))
+		// Elements of slices and maps are usually accessed via range or index variables.
+		if inCand.containerType == ContainerSlice || inCand.containerType == ContainerMap {
+			for _, elem := range elementVariables(fn, v) {
+				fieldsUsedModelIn.AddAll(elem.collector(fn.info, RecordFields).Skip(ignored...).Walk(fn.Body))
+				methodsUsedModelIn.AddAll(elem.collector(fn.info, RecordMethods).Skip(ignored...).Walk(fn.Body))
+			}
+		}
+	}
+	collectIn(in)
+	// Copies of the input (s := in, tmp := *in) are read just like the input itself.
+	for _, alias := range aliasVariables(fn, in) {
+		collectIn(alias)
 	}
 
 	// Collect field usages for the output candidate.
-	fieldsUsedModelOut := CollectOutputFields(fn, outVar, outCand.name)
-	missingOut := collectMissingFields(outCand.structType, fieldsUsedModelOut)
-	if outVar != "" {
+	fieldsUsedModelOut := CollectOutputFields(fn, outVar, outCand.typeName, blankReads...)
+	// When the output is constructed differently per branch, every branch has to be complete.
+	outBranches := resolveCallBranches(pass, CollectOutputBranches(fn, outVar, outCand.typeName, blankReads...), outCand.typeName)
+	// Fields written by helpers of the package count wherever the output comes from them,
+	// and so do the input fields they read (e.g. return buildDBSample(in)).
+	for _, branch := range outBranches {
+		if branch.Call != nil {
+			fieldsUsedModelOut.AddAll(branch.Fields)
+			fields, methods := helperInputUsage(pass, branch.Call, inObjects)
+			fieldsUsedModelIn.AddAll(fields)
+			methodsUsedModelIn.AddAll(methods)
+		}
+	}
+	useOut := func(name string) {
+		fieldsUsedModelOut.Add(name)
+		for _, branch := range outBranches {
+			branch.Fields.Add(name)
+		}
+	}
+	for name := range CollectBuilderFields(fn, cfg, outCand.structType).All() {
+		useOut(name)
+	}
+
+	// JSON round-trips have no per-field code to analyze: just like reflective copies, they're trusted
+	// with all fields but the ones tagged as required.
+	if isJSONRoundTrip(pass, fn.Body, in) {
+		if cfg.ReportJSONRoundTrip {
+			return ConverterValidationResult{
+				UnknownCoverage: "relies on struct tag compatibility (JSON round-trip)",
+				InputModel:      inCand.typeName,
+				OutputModel:     outCand.typeName,
+			}, nil
+		}
+		if !tagged {
+			return ConverterValidationResult{Valid: true, Coverage: 1, InputModel: inCand.typeName, OutputModel: outCand.typeName}, nil
+		}
+		ignoreAll = true
+	}
+
+	// Deep copies of the input give baseline coverage of all fields shared by both models.
+	deepCopied := callsWithVar(pass, fn.Body, cfg.DeepCopyFuncs, in)
+	if deepCopied {
+		for _, name := range sharedFields(inCand.structType, outCand.structType) {
+			fieldsUsedModelIn.Add(name)
+			useOut(name)
+		}
+	}
+
+	// Output fields written with constants drop the same-named input field silently.
+	var hardcoded []string
+	if cfg.ReportHardcoded && cfg.reports(pass, fn, CodeHardcoded) {
+		writes := CollectOutputWrites(fn, outVar, outCand.typeName)
+		for _, name := range writes.Hardcoded(fn.info, inVar, fn.variable(inVar), inCand.structType) {
+			hardcoded = append(hardcoded, qualify(outVar, name))
+		}
+	}
+
+	// Output fields assigned from another input field while the same-named one is unused
+	// are a classic copy-paste bug.
+	var swapped []string
+	if cfg.ReportSwapped && cfg.reports(pass, fn, CodeSwapped) {
+		writes := CollectOutputWrites(fn, outVar, outCand.typeName)
+		for _, pair := range writes.Swapped(fn.info, inVar, fn.variable(inVar), inCand.structType, fieldsUsedModelIn) {
+			swapped = append(swapped, fmt.Sprintf("%s = %s (%s unused)",
+				qualify(outVar, pair[0]), qualify(inVar, pair[1]), qualify(inVar, pair[0])))
+		}
+	}
+
+	// Output fields overwritten on the same path often indicate a copy-paste bug.
+	var duplicates []string
+	if cfg.ReportDuplicateWrites && cfg.reports(pass, fn, CodeDuplicateWrites) {
+		for _, name := range CollectDuplicateWrites(fn, outVar, outCand.typeName) {
+			duplicates = append(duplicates, qualify(outVar, name))
+		}
+	}
+
+	// Apply field mappings configured for this pair of types.
+	var unmapped []string
+	if mappings := cfg.FieldMappings.For(inCand.typeName, outCand.typeName); len(mappings) > 0 {
+		writes := CollectOutputWrites(fn, outVar, outCand.typeName)
+		for _, m := range mappings {
+			if !writes.AssignedFrom(fn.info, m.OutField, inVar, fn.variable(inVar), m.InField) {
+				if cfg.reports(pass, fn, CodeUnmapped) {
+					unmapped = append(unmapped, qualify(inVar, m.InField)+" -> "+qualify(outVar, m.OutField))
+				}
+				continue
+			}
+			fieldsUsedModelIn.Add(m.InField)
+			useOut(m.OutField)
+		}
+	}
+
+	if len(outBranches) > 1 {
+		branchFields := make([]*UsageLookup, 0, len(outBranches))
+		for _, branch := range outBranches {
+			branchFields = append(branchFields, branch.Fields)
+		}
+		fieldsUsedModelOut = intersectUsage(branchFields...)
+	}
+
+	// Fields used through local variables, conditionals and helpers escape the syntactic collectors.
+	if cfg.ssa != nil {
+		if f := cfg.ssa.function(fn); f != nil {
+			fieldsUsedModelIn.AddAll(preciseInputFields(f, inVar, inCand.structType))
+			if result := resultIndex(sig, outCand.structType); result >= 0 {
+				fieldsUsedModelOut.AddAll(preciseOutputFields(f, result, outCand.structType))
+			}
+		}
+	}
+
+	var missingIn, missingOut []string
+	var requiredIn, requiredOut []string
+	// Missing fields tagged as required leak whatever the coverage.
+	var missingTagged bool
+	var suggestions []FieldSuggestion
+	positions := make(map[string][]token.Pos)
+	decls := make(map[string]token.Pos)
+	if cfg.checksInput() {
+		index := structIndex(pass, inCand.typeName, inCand.structType)
+		skipped := skippedFields(pass, cfg, inCand.typeName).bind(index)
+		skipped.AddAll(funcIgnored)
+		skipped.AddAll(partialIn)
+		// Fields the output gets from its embedded base types (e.g. ID of gorm.Model) are set by the ORM.
+		for _, name := range embeddedBaseFields(cfg, outCand.structType) {
+			skipped.Add(name)
+		}
+		baselined, _ := cfg.baseline.fields(pass, fn, CodeMissingInput)
+		skipped.AddAll(baselined)
+		skipped.AddAll(cfg.unchangedFields(pass, fn, inCand.structType))
+		// Fields tagged as required are exempted by nothing.
+		skipped = exemptTagged(skipped, inCand.structType)
+		for _, name := range append(requiredFields(index, skipped), promotedTagged(inCand.structType)...) {
+			requiredIn = append(requiredIn, qualify(inVar, name))
+		}
+		if !ignoreAll {
+			missingIn = collectMissingFields(index, skipped, fieldsUsedModelIn, methodsUsedModelIn)
+		}
+		missingIn = appendMissingTagged(missingIn, inCand.structType, fieldsUsedModelIn, &missingTagged)
+		for i, m := range missingIn {
+			missingIn[i] = qualify(inVar, m)
+			positions[missingIn[i]] = []token.Pos{paramPos(fn.Type.Params, inVar)}
+			decls[missingIn[i]] = fieldDecl(inCand.structType, m)
+		}
+	}
+
+	if cfg.checksOutput() {
+		index := structIndex(pass, outCand.typeName, outCand.structType)
+		skipped := skippedFields(pass, cfg, outCand.typeName).bind(index)
+		skipped.AddAll(funcIgnored)
+		skipped.AddAll(partialOut)
+		for _, name := range embeddedBaseFields(cfg, inCand.structType) {
+			skipped.Add(name)
+		}
+		baselined, _ := cfg.baseline.fields(pass, fn, CodeMissingOutput)
+		skipped.AddAll(baselined)
+		skipped.AddAll(cfg.unchangedFields(pass, fn, outCand.structType))
+		skipped = exemptTagged(skipped, outCand.structType)
+		for _, name := range append(requiredFields(index, skipped), promotedTagged(outCand.structType)...) {
+			requiredOut = append(requiredOut, qualify(outVar, name))
+		}
+		if !ignoreAll {
+			missingOut = collectMissingFields(index, skipped, fieldsUsedModelOut)
+		}
+		missingOut = appendMissingTagged(missingOut, outCand.structType, fieldsUsedModelOut, &missingTagged)
+		if cfg.Suggest {
+			sources := suggestSources(inCand.structType, outCand.structType, missingOut, fieldsUsedModelIn)
+			for _, m := range missingOut {
+				if src, ok := sources[m]; ok {
+					suggestions = append(suggestions, FieldSuggestion{Output: qualify(outVar, m), Input: qualify(inVar, src)})
+				}
+			}
+		}
 		for i, m := range missingOut {
-			missingOut[i] = outVar + "." + m
+			missingOut[i] = qualify(outVar, m)
+			positions[missingOut[i]] = outputFieldPositions(fn, outBranches, m)
+			decls[missingOut[i]] = fieldDecl(outCand.structType, m)
+		}
+	}
+
+	// Check that every oneof variant of proto messages is handled.
+	// Deep copies carry over whichever variant is set.
+	var unhandledOneofs []string
+	if cfg.ProtoAware && cfg.CheckOneofs && !deepCopied && cfg.reports(pass, fn, CodeUnhandledOneof) {
+		if cfg.checksInput() && isProtoMessage(pass, inCand.typeName) {
+			unhandledOneofs = append(unhandledOneofs, unhandledOneofCases(pass, fn, inCand.typeName, inVar)...)
+		}
+		if cfg.checksOutput() && isProtoMessage(pass, outCand.typeName) {
+			unhandledOneofs = append(unhandledOneofs, unhandledOneofCases(pass, fn, outCand.typeName, outVar)...)
 		}
 	}
 
-	valid := (len(missingIn) == 0 && len(missingOut) == 0)
+	// Missing fields are tolerated as long as the coverage reaches the configured threshold.
+	coverage := 1.0
+	if required := len(requiredIn) + len(requiredOut); required > 0 {
+		coverage = float64(required-len(missingIn)-len(missingOut)) / float64(required)
+	}
+	leaking := (len(missingIn) > 0 || len(missingOut) > 0) && (coverage < cfg.MinCoverage || missingTagged)
+
+	// Converters ignored entirely leak fields tagged as required only.
+	if ignoreAll {
+		return ConverterValidationResult{
+			Valid:               !leaking,
+			Coverage:            coverage,
+			InputType:           types.TypeString(inCand.typeName.Type(), types.RelativeTo(pass.Pkg)),
+			OutputType:          types.TypeString(outCand.typeName.Type(), types.RelativeTo(pass.Pkg)),
+			InputFields:         requiredIn,
+			OutputFields:        requiredOut,
+			FieldPositions:      positions,
+			FieldDecls:          decls,
+			InputTypePos:        inCand.typeName.Pos(),
+			OutputTypePos:       outCand.typeName.Pos(),
+			InputModel:          inCand.typeName,
+			OutputModel:         outCand.typeName,
+			MissingInputFields:  missingIn,
+			MissingOutputFields: missingOut,
+		}, nil
+	}
+
+	valid := (!leaking && len(unmapped) == 0 && len(unhandledOneofs) == 0)
 	return ConverterValidationResult{
 		Valid:               valid,
+		Coverage:            coverage,
+		InputType:           types.TypeString(inCand.typeName.Type(), types.RelativeTo(pass.Pkg)),
+		OutputType:          types.TypeString(outCand.typeName.Type(), types.RelativeTo(pass.Pkg)),
+		InputFields:         requiredIn,
+		OutputFields:        requiredOut,
+		UnkeyedLiterals:     unkeyedLiterals(fn, outCand.typeName),
+		HardcodedFields:     hardcoded,
+		DuplicateWrites:     duplicates,
+		SwappedFields:       swapped,
+		FieldPositions:      positions,
+		FieldDecls:          decls,
+		InputTypePos:        inCand.typeName.Pos(),
+		OutputTypePos:       outCand.typeName.Pos(),
+		InputModel:          inCand.typeName,
+		OutputModel:         outCand.typeName,
+		Suggestions:         suggestions,
 		MissingInputFields:  missingIn,
 		MissingOutputFields: missingOut,
+		UnmappedFields:      unmapped,
+		UnhandledOneofs:     unhandledOneofs,
 	}, nil
 }
 
+// codes returns the codes of findings making the converter invalid.
+func (r ConverterValidationResult) codes() []string {
+	var codes []string
+	if len(r.MissingOutputFields) > 0 {
+		codes = append(codes, CodeMissingOutput)
+	}
+	if len(r.MissingInputFields) > 0 {
+		codes = append(codes, CodeMissingInput)
+	}
+	if len(r.UnhandledOneofs) > 0 {
+		codes = append(codes, CodeUnhandledOneof)
+	}
+	if len(r.UnmappedFields) > 0 {
+		codes = append(codes, CodeUnmapped)
+	}
+	return codes
+}
+
+// paramPos returns the position of the parameter with the given name.
+func paramPos(params *ast.FieldList, name string) token.Pos {
+	for _, field := range params.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return ident.Pos()
+			}
+		}
+	}
+	return params.Pos()
+}
+
+// outputFieldPositions returns closing braces of output literals lacking the field,
+// or the closing brace of the function body if the output is not built by literals.
+func outputFieldPositions(fn *Func, branches []OutputBranch, field string) []token.Pos {
+	var positions []token.Pos
+	for _, branch := range branches {
+		if !branch.Fields.LookUp(field) {
+			positions = append(positions, branch.end())
+		}
+	}
+	if len(positions) == 0 {
+		positions = append(positions, fn.Body.Rbrace)
+	}
+	return positions
+}
+
+// resolveCallBranches adds fields written by helpers of the package to the output branches of their calls
+// (e.g. out = buildSpecial(in)). Branches of other calls are left out: what they cover can't be told.
+func resolveCallBranches(pass *analysis.Pass, branches []OutputBranch, candidate *types.TypeName) []OutputBranch {
+	var resolved []OutputBranch
+	for _, branch := range branches {
+		if branch.Call != nil {
+			written, ok := helperOutputFields(pass, branch.Call, branch.Result, candidate)
+			if !ok {
+				continue
+			}
+			branch.Fields.AddAll(written)
+		}
+		resolved = append(resolved, branch)
+	}
+	return resolved
+}
+
+// helperOutputFields returns fields written into the result at the given index by the function
+// of the package the call calls. Its own calls are not followed. It returns false if the function
+// is not declared in the package.
+func helperOutputFields(pass *analysis.Pass, call *ast.CallExpr, result int, candidate *types.TypeName) (*UsageLookup, bool) {
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil || callee.Pkg() != pass.Pkg {
+		return nil, false
+	}
+	decl := funcDecl(pass, callee)
+	if decl == nil || decl.Body == nil {
+		return nil, false
+	}
+
+	helper := NewFuncFromDecl(pass, decl)
+	outVar := resultName(decl.Type.Results, result)
+	var literals []*UsageLookup
+	for _, branch := range CollectOutputBranches(helper, outVar, candidate) {
+		if branch.Lit != nil {
+			literals = append(literals, branch.Fields)
+		}
+	}
+	if len(literals) > 1 {
+		return intersectUsage(literals...), true
+	}
+	return CollectOutputFields(helper, outVar, candidate), true
+}
+
+// helperInputUsage returns fields and methods of the input the function of the package the call calls
+// uses on its parameters (or receiver) given the input, i.e. an argument referring to one of the objects.
+func helperInputUsage(pass *analysis.Pass, call *ast.CallExpr, inputs map[types.Object]bool) (fields, methods *UsageLookup) {
+	fields, methods = NewUsageLookup(), NewUsageLookup()
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil || callee.Pkg() != pass.Pkg {
+		return fields, methods
+	}
+	decl := funcDecl(pass, callee)
+	if decl == nil || decl.Body == nil {
+		return fields, methods
+	}
+
+	isInput := func(expr ast.Expr) bool {
+		ident, ok := derefIdent(expr)
+		return ok && inputs[pass.TypesInfo.ObjectOf(ident)]
+	}
+	helper := NewFuncFromDecl(pass, decl)
+	collect := func(name *ast.Ident) {
+		if name.Name == "_" {
+			return
+		}
+		obj := helper.object(name)
+		fields.AddAll(NewUsageCollector(name.Name, RecordFields).Resolve(helper.info, obj).Walk(helper.Body))
+		methods.AddAll(NewUsageCollector(name.Name, RecordMethods).Resolve(helper.info, obj).Walk(helper.Body))
+	}
+
+	// Methods of the input, e.g. return in.ToDB().
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && decl.Recv != nil && isInput(sel.X) {
+		for _, name := range decl.Recv.List[0].Names {
+			collect(name)
+		}
+	}
+	if call.Ellipsis.IsValid() {
+		return fields, methods
+	}
+	i := 0
+	for _, field := range decl.Type.Params.List {
+		if len(field.Names) == 0 {
+			i++
+			continue
+		}
+		for _, name := range field.Names {
+			if i < len(call.Args) && isInput(call.Args[i]) {
+				collect(name)
+			}
+			i++
+		}
+	}
+	return fields, methods
+}
+
+// funcDecl returns the declaration of the function among files of the package, if any.
+func funcDecl(pass *analysis.Pass, obj *types.Func) *ast.FuncDecl {
+	for _, file := range pass.Files {
+		if obj.Pos() < file.FileStart || obj.Pos() >= file.FileEnd {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Name.Pos() == obj.Pos() {
+				return fd
+			}
+		}
+	}
+	return nil
+}
+
+// resultName returns the name of the result at the given index, or "" if results are unnamed.
+func resultName(results *ast.FieldList, index int) string {
+	if results == nil {
+		return ""
+	}
+	i := 0
+	for _, field := range results.List {
+		if len(field.Names) == 0 {
+			i++
+			continue
+		}
+		for _, name := range field.Names {
+			if i == index && name.Name != "_" {
+				return name.Name
+			}
+			i++
+		}
+	}
+	return ""
+}
+
+// qualify prefixes the field name with the variable name, if any.
+func qualify(varName, field string) string {
+	if varName == "" {
+		return field
+	}
+	return varName + "." + field
+}
+
 // findCandidateParam searches the appropriate FieldList (for input or output)
 // for the first parameter/result that qualifies as a candidate type.
 // It returns the candidate info, the variable name (if any) and true on success.
@@ -369,3 +1511,48 @@ func findCandidateParam(fieldList *ast.FieldList, sigParams *types.Tuple) (cand
 	}
 	return candidate{}, "", false
 }
+
+// concreteResultCandidates returns candidates for interface results of fn: concrete structs
+// (or pointers to them) implementing the interface and returned as composite literals,
+// e.g. `return &storage.SampleRecord{...}` from a function returning storage.Record.
+func concreteResultCandidates(fn *Func, pass *analysis.Pass) []candidate {
+	if fn.Body == nil || fn.Signature == nil {
+		return nil
+	}
+	results := fn.Signature.Results()
+
+	var cands []candidate
+	seen := make(map[*types.TypeName]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			// Returns of nested function literals belong to them.
+			return false
+		case *ast.ReturnStmt:
+			if len(x.Results) != results.Len() {
+				return true
+			}
+			for i, expr := range x.Results {
+				iface, ok := results.At(i).Type().Underlying().(*types.Interface)
+				if !ok {
+					continue
+				}
+				if _, ok := ast.Unparen(expr).(*ast.CompositeLit); !ok {
+					if u, ok := ast.Unparen(expr).(*ast.UnaryExpr); !ok || u.Op != token.AND {
+						continue
+					}
+				}
+				t := pass.TypesInfo.TypeOf(expr)
+				if t == nil || !types.Implements(t, iface) {
+					continue
+				}
+				if cand, ok := extractCandidateType(t); ok && !seen[cand.typeName] {
+					seen[cand.typeName] = true
+					cands = append(cands, cand)
+				}
+			}
+		}
+		return true
+	})
+	return cands
+}
@@ -2,21 +2,1257 @@
 package sf_test
 
 import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/amberpixels/go-stickyfields/internal/sf"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
 )
 
 func TestC1(t *testing.T) {
 	testdata := analysistest.TestData()
 
-	analyzer := &analysis.Analyzer{
-		Name: "stickyfields",
-		Doc:  "reports all inconsistent converter functions: ensures sticky fields)",
-		Run:  sf.Run,
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/c1")
+}
+
+func TestIgnoreField(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/ignorefield")
+}
+
+func TestNolint(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/nolint")
+}
+
+func TestFieldMapping(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("map", "model.Post.Body=dbmodel.Post.Content"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/fieldmap")
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/deprecatedlenient")
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("include-deprecated", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/deprecated")
+}
+
+func TestProto(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/proto")
+}
+
+func TestEmbeddedBaseTypes(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/gormmodel")
+}
+
+func TestORMProfiles(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/ormprofiles")
+}
+
+func TestNullableWrappers(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/nullable")
+}
+
+func TestTimeHelpers(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/timehelpers")
+}
+
+func TestReflectiveCopy(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/reflectivecopy")
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("reflective-copy", "covered"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/reflectivecopycovered")
+}
+
+func TestDeepCopy(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/deepcopy")
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/jsonroundtripskipped")
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("report-json-roundtrip", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/jsonroundtrip")
+}
+
+func TestBuilder(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/builder")
+}
+
+func TestFuncLiterals(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/funclit")
+}
+
+func TestRegistries(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-registries", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/registry")
+}
+
+func TestRoundTrips(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-round-trips", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("disable", "SF001,SF002"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/roundtrip")
+}
+
+func TestMissingReverse(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-reverse", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/reverse")
+}
+
+func TestNaming(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-naming", "true"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "converters/naming")
+
+	analyzer = sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-naming", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("converter-packages", "/convert$"); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, testdata, analyzer, "converters/naming/outside")
+}
+
+func TestInterfaceResult(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/ifaceresult")
+}
+
+func TestAliases(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/alias")
+}
+
+func TestGeneratedFiles(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/generated")
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-generated", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/generatedchecked")
+}
+
+func TestIncludeTests(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/testfilesskipped")
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("include-tests", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/testfiles")
+}
+
+func TestPathFilters(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("include", "^converters/pathfilter$"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("exclude", `/legacy\.go$`); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/pathfilter")
+}
+
+func TestExportedOnly(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("exported-only", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/exportedonly")
+}
+
+func TestFuncPattern(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("func-pattern", "^(To|From|Convert)"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/funcpattern")
+}
+
+func TestStructSize(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("min-fields", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("max-fields", "5"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/structsize")
+}
+
+func TestNameHeuristicDisabled(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("name-heuristic", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/nameheuristic")
+}
+
+func TestCheckOutputOnly(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check", "output"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/checkoutput")
+}
+
+func TestLenientMode(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("include-methods", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("mode", "lenient"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/lenientmode")
+}
+
+func TestModeUnitchecker(t *testing.T) {
+	cfg := sf.DefaultConfig()
+	analyzer := sf.NewAnalyzer(cfg)
+
+	// Like unitchecker, connect the flags of the analyzer to a flag set of the driver.
+	fs := flag.NewFlagSet("vet", flag.ContinueOnError)
+	analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, analyzer.Name+"."+f.Name, f.Usage)
+	})
+	if err := fs.Parse([]string{"-stickyfields.report-hardcoded=false", "-stickyfields.mode=strict"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.ReportHardcoded || !cfg.ReportSwapped || cfg.Mode != sf.ModeStrict {
+		t.Errorf("unexpected config: report-hardcoded %t, report-swapped %t, mode %q",
+			cfg.ReportHardcoded, cfg.ReportSwapped, cfg.Mode)
+	}
+}
+
+func TestMinCoverage(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("min-coverage", "0.7"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/mincoverage")
+}
+
+func TestBranches(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/branches")
+}
+
+func TestEarlyReturns(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/earlyreturn")
+}
+
+func TestDeferred(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/deferred")
+}
+
+func TestReassignments(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/reassign")
+}
+
+func TestUnkeyedLiterals(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("report-unkeyed", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/unkeyed")
+}
+
+func TestSliceConverters(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/slices")
+}
+
+func TestHardcodedFields(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("report-hardcoded", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/hardcoded")
+}
+
+func TestLoggingReads(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/logging")
+}
+
+func TestDuplicateWrites(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("report-duplicate-writes", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/duplicates")
+}
+
+func TestBlankReads(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("ignore-blank-reads", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/blankreads")
+}
+
+func TestSuggestions(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/suggest")
+}
+
+func TestSwappedFields(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("report-swapped", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/swapped")
+}
+
+func TestPerFieldDiagnostics(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("per-field", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/perfield")
+}
+
+func TestSuppressFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, sf.Analyzer, "converters/suppress")
+}
+
+func TestResultConverters(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sf.Analyzer, "converters/suggest")
+
+	result := results[0].Result.(*sf.Result)
+	if len(result.Files) != 1 {
+		t.Errorf("analyzed files: got %v, want one file", result.Files)
+	}
+	if result.Findings != 2 {
+		t.Errorf("findings: got %d, want 2", result.Findings)
+	}
+
+	var converter *sf.Converter
+	for i, c := range result.Converters {
+		if c.Name == "PersonToDTO" {
+			converter = &result.Converters[i]
+		}
+	}
+	if converter == nil {
+		t.Fatalf("PersonToDTO not found among converters: %v", result.Converters)
+	}
+
+	if converter.InputType != "Person" || converter.OutputType != "PersonDTO" {
+		t.Errorf("unexpected models: %s -> %s", converter.InputType, converter.OutputType)
+	}
+	wantOutput := []string{"ID", "Name", "Surname", "EMail", "Verified"}
+	if !slices.Equal(converter.OutputFields, wantOutput) {
+		t.Errorf("output fields: got %v, want %v", converter.OutputFields, wantOutput)
+	}
+	if converter.Valid {
+		t.Error("PersonToDTO reported as valid")
+	}
+}
+
+func TestStructFacts(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sf.FactsAnalyzer, "converters/facts")
+}
+
+func TestRelatedInformation(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sf.Analyzer, "converters/related")
+
+	var got []string
+	for _, d := range results[0].Diagnostics {
+		for _, r := range d.Related {
+			posn := results[0].Pass.Fset.Position(r.Pos)
+			got = append(got, fmt.Sprintf("%s:%d: %s", filepath.Base(posn.Filename), posn.Line, r.Message))
+		}
+	}
+	want := []string{
+		"related.go:3: input model User declared here",
+		"related.go:13: missing input field u.Name",
+		"related.go:5: input field u.Name declared here",
+		"related.go:8: output model UserDTO declared here",
+		"related.go:14: missing output field Email",
+		"related.go:10: output field Email declared here",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("related information:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestColorAlways(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("color", "always"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/color")
+}
+
+func TestVerbosityTrace(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	var out bytes.Buffer
+	cfg := sf.DefaultConfig()
+	cfg.Output = &out
+	cfg.Verbosity = sf.VerbosityTrace
+	cfg.Color = sf.ColorAlways
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/color")
+
+	if want := "ToSampleDTO: Sample -> SampleDTO: names match"; !strings.Contains(out.String(), want) {
+		t.Errorf("trace %q does not contain %q", out.String(), want)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	var out bytes.Buffer
+	cfg := sf.DefaultConfig()
+	cfg.Output = &out
+	cfg.Verbosity = sf.VerbosityQuiet
+	cfg.Explain = "SamplesToDTO"
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/explain")
+
+	for _, want := range []string{
+		"SamplesToDTO: parameter 0 (converters/model.Sample): candidate Sample",
+		"SamplesToDTO: parameter 1 (int): not a struct",
+		"SamplesToDTO: result 0 ([]SampleDTO): candidate []SampleDTO",
+		"SamplesToDTO: Sample -> []SampleDTO: incompatible containers",
+		"SamplesToDTO: not a converter: no candidate pair matched",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("explanation %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestDisableCodes(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("disable", sf.CodeMissingInput); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/codes")
+}
+
+func TestConfigFile(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	for _, name := range []string{"stickyfields.yaml", "stickyfields.toml"} {
+		t.Run(name, func(t *testing.T) {
+			settings, err := sf.ReadConfigFile(filepath.Join(testdata, "config", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := sf.DefaultConfig()
+			analyzer := sf.NewAnalyzer(cfg)
+			// Flags given explicitly take precedence.
+			if err := analyzer.Flags.Set("max-issues", "10"); err != nil {
+				t.Fatal(err)
+			}
+			if err := sf.ApplySettings(&analyzer.Flags, settings); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.MaxIssues != 10 || cfg.Severity(sf.CodeMissingOutput) != sf.SeverityError {
+				t.Errorf("unexpected config: max issues %d, SF001 severity %q", cfg.MaxIssues, cfg.Severity(sf.CodeMissingOutput))
+			}
+
+			analysistest.Run(t, testdata, analyzer, "converters/codes")
+		})
+	}
+
+	if err := sf.ApplySettings(&sf.NewAnalyzer(sf.DefaultConfig()).Flags, map[string]any{"bogus": true}); err == nil {
+		t.Error("unknown option accepted")
+	}
+}
+
+func TestRegexpListSettings(t *testing.T) {
+	cfg := sf.DefaultConfig()
+	analyzer := sf.NewAnalyzer(cfg)
+	// Lists of regexps are passed element by element, as regexps may contain commas.
+	settings := map[string]any{"include": []any{`^To[A-Z]\w{2,5}$`, `^From`}}
+	if err := sf.ApplySettings(&analyzer.Flags, settings); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Include) != 2 || !cfg.Include.MatchAny("ToUser") || cfg.Include.MatchAny("ToU") {
+		t.Errorf("unexpected include regexps %v", cfg.Include)
+	}
+
+	// So is the flag repeated on the command line.
+	cfg = sf.DefaultConfig()
+	analyzer = sf.NewAnalyzer(cfg)
+	for _, value := range []string{`/legacy\.go$`, `_gen\w{0,3}\.go$`} {
+		if err := analyzer.Flags.Set("exclude", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(cfg.Exclude) != 2 || !cfg.Exclude.MatchAny("a/users_gen.go") {
+		t.Errorf("unexpected exclude regexps %v", cfg.Exclude)
+	}
+}
+
+func TestNestedConfigFiles(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.NewAnalyzer(sf.DefaultConfig()), "converters/nested", "converters/nested/legacy")
+}
+
+func TestPackageDirectives(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.NewAnalyzer(sf.DefaultConfig()), "converters/pkgconfig")
+}
+
+func TestBaseline(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	baseline := filepath.Join(testdata, "src", "converters", "baseline", "stickyfields-baseline.json")
+	if err := analyzer.Flags.Set("baseline", baseline); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("include-methods", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := analysistest.Run(t, testdata, analyzer, "converters/baseline")
+
+	// Baselined findings are neither reported nor recorded again.
+	var b sf.Baseline
+	b.Add(results[0].Result.(*sf.Result))
+	want := []sf.BaselineEntry{{Package: "converters/baseline", Function: "ToSampleDTO", Code: sf.CodeMissingInput, Field: "Currency"}}
+	if !slices.Equal(b.Findings, want) {
+		t.Errorf("got findings %+v, want %+v", b.Findings, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	const diff = `diff --git a/diff.go b/diff.go
+--- a/diff.go
++++ b/diff.go
+@@ -5,0 +6 @@ type Order struct {
++	Discount int // added
+@@ -9,0 +11 @@ type OrderDTO struct {
++	Discount int // added
+@@ -21 +23 @@ func FromOrderDTO(dto OrderDTO) Order {
+-		ID: dto.ID,
++		ID: dto.ID, // changed
+--- a/removed.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package diff
+`
+	changed, err := sf.ParseDiff(strings.NewReader(diff), filepath.Join(testdata, "src", "converters", "diff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := sf.DefaultConfig()
+	cfg.Changed = changed
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/diff")
+}
+
+func TestParseDiff(t *testing.T) {
+	dir := filepath.FromSlash("/repo")
+	tests := []struct {
+		name string
+		diff string
+		want sf.ChangedLines
+	}{
+		{
+			name: "prefixed",
+			diff: "--- a/pkg/a.go\n+++ b/pkg/a.go\n@@ -1,0 +2,3 @@\n",
+			want: sf.ChangedLines{filepath.Join(dir, "pkg", "a.go"): {{2, 4}}},
+		},
+		{
+			name: "not prefixed",
+			diff: "--- pkg/a.go\n+++ pkg/a.go\n@@ -3 +3 @@\n",
+			want: sf.ChangedLines{filepath.Join(dir, "pkg", "a.go"): {{3, 3}}},
+		},
+		{
+			name: "deleted",
+			diff: "--- a/pkg/a.go\n+++ /dev/null\n@@ -1,3 +0,0 @@\n",
+			want: sf.ChangedLines{},
+		},
+		{
+			name: "removed lines only",
+			diff: "--- a/pkg/a.go\n+++ b/pkg/a.go\n@@ -4,2 +3,0 @@\n",
+			want: sf.ChangedLines{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sf.ParseDiff(strings.NewReader(tt.diff), dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changed lines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaselineRelativeToModule(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	// go vet runs vet tools in package directories, so paths are resolved against the module root
+	// (testdata/src/converters) as well.
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("baseline", filepath.Join("baseline", "stickyfields-baseline.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("include-methods", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/baseline")
+}
+
+func TestChecks(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	for _, check := range sf.Checks {
+		switch check.Name {
+		case "leakingfields":
+			analysistest.Run(t, testdata, sf.NewCheckAnalyzer(check, sf.DefaultConfig()), "converters/checks/leaks")
+		case "hardcodedfields":
+			analysistest.Run(t, testdata, sf.NewCheckAnalyzer(check, sf.DefaultConfig()), "converters/checks/hardcoded")
+		}
+	}
+}
+
+func TestSeverities(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("severity", "SF002=error"); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.Flags.Set("severity", "SF003=fatal"); err == nil {
+		t.Error("unknown severity accepted")
+	}
+
+	results := analysistest.Run(t, testdata, analyzer, "converters/severity")
+	// Findings of several codes are categorized by the most severe one.
+	for _, d := range results[0].Diagnostics {
+		if d.Category != sf.CodeMissingInput {
+			t.Errorf("category: got %s, want %s", d.Category, sf.CodeMissingInput)
+		}
+	}
+}
+
+func TestMaxIssuesPerFunc(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("max-issues-per-func", "4"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/limitsfunc")
+}
+
+func TestMaxIssuesPerFuncPerField(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	for name, value := range map[string]string{"max-issues-per-func": "4", "per-field": "true"} {
+		if err := analyzer.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := analysistest.Run(t, testdata, analyzer, "converters/limitsperfield")
+
+	// Omitted fields are told apart from the findings by their category.
+	var omitted int
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			if d.Category == sf.CategoryOmitted {
+				omitted++
+			}
+		}
+	}
+	if omitted != 1 {
+		t.Errorf("got %d diagnostics of omitted fields, want 1", omitted)
+	}
+}
+
+func TestMaxIssues(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("max-issues", "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := analysistest.Run(t, testdata, analyzer, "converters/limitspkg")
+
+	// Machine-readable outputs tell the number of omitted findings by its category.
+	var omitted int
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			if d.Category == sf.CategoryOmitted {
+				omitted++
+			}
+		}
+	}
+	if omitted != 1 {
+		t.Errorf("got %d diagnostics of omitted findings, want 1", omitted)
+	}
+}
+
+func TestPrecise(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("precise", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/precise")
+
+	// Enabled up front, the SSA form comes from buildssa.Analyzer.
+	cfg := sf.DefaultConfig()
+	cfg.Precise = true
+	analyzer = sf.NewAnalyzer(cfg)
+	if !slices.Contains(analyzer.Requires, buildssa.Analyzer) {
+		t.Error("analyzer of the precise mode doesn't require buildssa.Analyzer")
+	}
+	analysistest.Run(t, testdata, analyzer, "converters/precise")
+}
+
+func TestShadowedVariables(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/shadow")
+}
+
+func TestShadowedWrites(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	// Variables shadowing the input or the output are not them.
+	cfg := sf.DefaultConfig()
+	cfg.ReportHardcoded = true
+	cfg.ReportSwapped = true
+	cfg.ReportDuplicateWrites = true
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/shadowwrites")
+}
+
+func TestCopiedVariables(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/copies")
+}
+
+func TestIntermediateOutputs(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/intermediate")
+}
+
+func TestClosures(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/closures")
+}
+
+func TestDelegatingConverters(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/delegate")
+}
+
+// apiAnalyzer validates converters through the programmatic API from a pass of its own.
+var apiAnalyzer = &analysis.Analyzer{
+	Name: "api",
+	Doc:  "reports converters validated with sf.ValidateFunc",
+	Run: func(pass *analysis.Pass) (any, error) {
+		for _, file := range pass.Files {
+			for _, decl := range file.Decls {
+				decl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				fn := sf.NewFuncFromDecl(pass, decl)
+				in, out, err := sf.Candidates(fn, pass)
+				if err != nil {
+					continue
+				}
+				result, err := sf.ValidateFunc(pass, fn, nil)
+				if err != nil {
+					return nil, err
+				}
+				pass.Reportf(decl.Pos(), "%s -> %s: missing input %v, missing output %v",
+					in.Type.Name(), out.Type.Name(), result.MissingInputFields, result.MissingOutputFields)
+			}
+		}
+		return nil, nil
+	},
+}
+
+func TestProgrammaticAPI(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, apiAnalyzer, "converters/api")
+}
+
+// mappingAnalyzer reports mapping tables extracted with sf.ExtractMapping.
+var mappingAnalyzer = &analysis.Analyzer{
+	Name: "mapping",
+	Doc:  "reports mapping tables of converters",
+	Run: func(pass *analysis.Pass) (any, error) {
+		for _, file := range pass.Files {
+			for _, decl := range file.Decls {
+				decl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				mapping, err := sf.ExtractMapping(sf.NewFuncFromDecl(pass, decl), pass)
+				if err != nil {
+					continue
+				}
+				var rows []string
+				for _, out := range mapping.Outputs {
+					switch {
+					case out.Constant:
+						rows = append(rows, fmt.Sprintf("%s <- %s const", out.Field, strings.Join(out.Sources, ", ")))
+					case len(out.Sources) > 0:
+						rows = append(rows, fmt.Sprintf("%s <- %s from %v", out.Field, strings.Join(out.Sources, ", "), out.From))
+					}
+				}
+				pass.Reportf(decl.Pos(), "%s", strings.Join(rows, "; "))
+				for _, in := range mapping.Inputs {
+					if in.Read {
+						pass.Reportf(decl.Pos(), "%s read, to %v", in.Field, in.To)
+					}
+				}
+			}
+		}
+		return nil, nil
+	},
+}
+
+func TestExtractMapping(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, mappingAnalyzer, "converters/mapping")
+}
+
+func TestDeadFields(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	var results []*sf.Result
+	for _, r := range analysistest.Run(t, testdata, sf.Analyzer, "converters/deadfields/...") {
+		if result, ok := r.Result.(*sf.Result); ok {
+			results = append(results, result)
+		}
+	}
+
+	var got []string
+	for _, dead := range sf.DeadFields(results) {
+		got = append(got, dead.Model+"."+dead.Field)
+	}
+	want := []string{"converters/deadfields/conv.User.Nickname", "converters/deadfields/db.UserRow.Legacy"}
+	if !slices.Equal(got, want) {
+		t.Errorf("dead fields = %v, want %v", got, want)
+	}
+}
+
+func TestFindSimilarStructs(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	// Named structs of the packages, one layer per package. Packages are analyzed concurrently,
+	// so they're collected from the results.
+	collect := &analysis.Analyzer{
+		Name: "structs",
+		Doc:  "collects named structs of the package",
+		Run: func(pass *analysis.Pass) (any, error) {
+			var structs []*types.TypeName
+			for _, name := range pass.Pkg.Scope().Names() {
+				if obj, ok := pass.Pkg.Scope().Lookup(name).(*types.TypeName); ok {
+					if _, ok := obj.Type().Underlying().(*types.Struct); ok {
+						structs = append(structs, obj)
+					}
+				}
+			}
+			return structs, nil
+		},
+		ResultType: reflect.TypeOf([]*types.TypeName(nil)),
 	}
+	structs := make(map[string][]*types.TypeName)
+	for _, r := range analysistest.Run(t, testdata, collect, "converters/similar/model", "converters/similar/db") {
+		structs[r.Pass.Pkg.Path()], _ = r.Result.([]*types.TypeName)
+	}
+
+	layers := [][]*types.TypeName{structs["converters/similar/model"], structs["converters/similar/db"]}
+	var got []string
+	for _, pair := range sf.FindSimilarStructs(layers, 0.7, 2) {
+		got = append(got, fmt.Sprintf("%s~%s %v %.1f", pair.A.Name(), pair.B.Name(), pair.Shared, pair.Overlap))
+	}
+	// Orders share two fields of similar types only, tags a single field.
+	want := []string{"User~UserRow [ID Name Email CreatedAt] 0.8"}
+	if !slices.Equal(got, want) {
+		t.Errorf("similar structs = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateConverter(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	var got string
+	generate := &analysis.Analyzer{
+		Name: "gen",
+		Doc:  "generates a converter between structs of the package",
+		Run: func(pass *analysis.Pass) (any, error) {
+			in := pass.Pkg.Scope().Lookup("Sample").(*types.TypeName)
+			out := pass.Pkg.Scope().Lookup("SampleRow").(*types.TypeName)
+			var err error
+			got, err = sf.GenerateConverter("", in, out, types.RelativeTo(pass.Pkg))
+			return nil, err
+		},
+	}
+	analysistest.Run(t, testdata, generate, "converters/gen")
+
+	// The fixture contains the generated converter too, it must pass the analyzer as generated.
+	want := `// ToSampleRow converts Sample into SampleRow.
+//
+// TODO: map Age, Created, Comment and remove them from the //sf:ignore directive.
+//
+//sf:ignore Age Created Comment
+func ToSampleRow(in Sample) SampleRow {
+	return SampleRow{
+		ID: int64(in.ID),
+		Name: in.Name,
+		EmailStr: in.Email, // TODO: check, matched by a similar name
+		Tags: in.Tags,
+	}
+}
+`
+	if got != want {
+		t.Errorf("generated converter:\n%s\nwant:\n%s", got, want)
+	}
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/gen")
+}
+
+func TestGenerateTests(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	r := analysistest.Run(t, testdata, sf.Analyzer, "converters/gentests")[0]
+	src, skipped, err := sf.GenerateTests(r.Pass.Pkg, r.Result.(*sf.Result))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSkipped := []string{"ToUserRowVersion: converters taking more than the input model are not tested"}
+	if !slices.Equal(skipped, wantSkipped) {
+		t.Errorf("skipped = %q, want %q", skipped, wantSkipped)
+	}
+	for _, want := range []string{
+		"return ToUserRow(in), nil",
+		"return ToUserRowPtr(&in)",
+		// Ignored fields are not expected to be set.
+		`fields: []string{"ID", "Name", "Created"},`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated tests lack %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestResultMappings(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	cfg := sf.DefaultConfig()
+	cfg.Mappings = true
+	r := analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/gentests")[0]
+	var got []string
+	for _, c := range r.Result.(*sf.Result).Converters {
+		if c.Name != "ToUserRow" {
+			continue
+		}
+		if c.Mapping == nil {
+			t.Fatal("ToUserRow has no mapping table")
+		}
+		for _, out := range c.Mapping.Outputs {
+			got = append(got, fmt.Sprintf("%s <- %v", out.Field, out.From))
+		}
+	}
+	want := []string{"ID <- [ID]", "Name <- [Name]", "Created <- [Created]", "Version <- []"}
+	if !slices.Equal(got, want) {
+		t.Errorf("mapping of ToUserRow = %q, want %q", got, want)
+	}
+}
+
+func TestConversionGraph(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	var results []*sf.Result
+	for _, r := range analysistest.Run(t, testdata, sf.Analyzer, "converters/gentests", "converters/deadfields/...") {
+		results = append(results, r.Result.(*sf.Result))
+	}
+	// Results repeated, e.g. of foo and foo.test, don't repeat edges.
+	results = append(results, results...)
+
+	nodes, edges := sf.ConversionGraph(results)
+	var got []string
+	for _, obj := range nodes {
+		got = append(got, obj.Pkg().Path()+"."+obj.Name())
+	}
+	for _, e := range edges {
+		got = append(got, fmt.Sprintf("%s -> %s: %s.%s", e.From.Name(), e.To.Name(), e.Package, e.Converter))
+	}
+	want := []string{
+		"converters/deadfields/conv.User",
+		"converters/deadfields/db.UserRow",
+		"converters/gentests.User",
+		"converters/gentests.UserRow",
+		"User -> UserRow: converters/deadfields/conv.ToUserRow",
+		"UserRow -> User: converters/deadfields/conv.FromUserRow",
+		"User -> UserRow: converters/gentests.ToUserRow",
+		"User -> UserRow: converters/gentests.ToUserRowPtr",
+		"User -> UserRow: converters/gentests.ToUserRowVersion",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("conversion graph = %q, want %q", got, want)
+	}
+}
+
+func TestDuplicateConverters(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	for flag, value := range map[string]string{
+		"check-duplicates": "true",
+		"allow-duplicates": "ToUserRowRedacted",
+	} {
+		if err := analyzer.Flags.Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/duplicateconverters")
+}
+
+func TestLayers(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	for flag, value := range map[string]string{
+		"check-layers": "true",
+		"layers":       "domain=/domain$,db=/db$",
+		"layer-rules":  "domain->db@/repo$",
+	} {
+		if err := analyzer.Flags.Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/layers/...")
+
+	cfg := sf.DefaultConfig()
+	if err := cfg.Layers.Set("domain=/domain$"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.LayerRules.Set("domain->db"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("rule of an unknown layer is valid")
+	}
+}
+
+func TestPurity(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := sf.NewAnalyzer(sf.DefaultConfig())
+	if err := analyzer.Flags.Set("check-purity", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, analyzer, "converters/purity")
+}
+
+func TestNilResults(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	cfg := sf.DefaultConfig()
+	cfg.CheckNilResults = true
+	cfg.Disable = sf.StringList{sf.CodeMissingOutput, sf.CodeMissingInput}
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/nilresults")
+}
+
+func TestErrorConvention(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	cfg := sf.DefaultConfig()
+	cfg.CheckErrors = true
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/errorconvention")
+
+	if severity := cfg.Severity(sf.CodeMissingError); severity != sf.SeverityInfo {
+		t.Errorf("unexpected severity of %s: %q", sf.CodeMissingError, severity)
+	}
+	cfg.Severities = sf.Severities{sf.CodeNilInput: sf.SeverityError}
+	if severity := cfg.Severity(sf.CodeNilInput); severity != sf.SeverityError {
+		t.Errorf("unexpected severity of %s: %q", sf.CodeNilInput, severity)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.Run(t, testdata, sf.Analyzer, "converters/partial")
+}
+
+func TestRequiredTag(t *testing.T) {
+	testdata := analysistest.TestData()
 
-	analysistest.Run(t, testdata, analyzer, "converters/c1")
+	// Missing fields tagged as required leak even within the coverage threshold.
+	cfg := sf.DefaultConfig()
+	cfg.MinCoverage = 0.5
+	// So do the ones of converters relying on reflective copies or JSON round-trips.
+	cfg.ReflectiveCopy = sf.ReflectiveCopyCovered
+	analysistest.Run(t, testdata, sf.NewAnalyzer(cfg), "converters/requiredtag")
 }
@@ -0,0 +1,120 @@
+package sf
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// BaselineEntry is a finding recorded in a baseline. It's keyed by the converter and the field
+// rather than by position, so it survives unrelated edits of the file.
+type BaselineEntry struct {
+	// Package is the import path of the package declaring the converter.
+	Package string `json:"package"`
+	// Function is the name of the converter, qualified by the receiver type for methods (e.g. Mapper.ToDB).
+	Function string `json:"function"`
+	// Code is the code of the finding.
+	Code string `json:"code"`
+	// Field is the missing field for SF001 and SF002. Other findings are recorded per converter.
+	Field string `json:"field,omitempty"`
+}
+
+// Baseline is a set of known findings that are not reported again,
+// allowing to adopt the analyzer in existing code bases gradually.
+type Baseline struct {
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// ReadBaseline reads the baseline from the JSON file.
+func ReadBaseline(filename string) (*Baseline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", filename, err)
+	}
+	return &b, nil
+}
+
+// Write writes the baseline to the JSON file, ordering its findings.
+func (b *Baseline) Write(filename string) error {
+	slices.SortFunc(b.Findings, func(x, y BaselineEntry) int {
+		return cmp.Or(
+			cmp.Compare(x.Package, y.Package),
+			cmp.Compare(x.Function, y.Function),
+			cmp.Compare(x.Code, y.Code),
+			cmp.Compare(x.Field, y.Field),
+		)
+	})
+	b.Findings = slices.Compact(b.Findings)
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0o644)
+}
+
+// Add records findings of the converters of the result.
+func (b *Baseline) Add(result *Result) {
+	for _, c := range result.Converters {
+		add := func(code string, fields ...string) {
+			for _, field := range fields {
+				b.Findings = append(b.Findings, BaselineEntry{
+					Package:  result.Package,
+					Function: c.qualifiedName(),
+					Code:     code,
+					// Missing input fields are qualified by the parameter name (e.g. in.Name).
+					Field: field[strings.LastIndex(field, ".")+1:],
+				})
+			}
+		}
+		add(CodeMissingOutput, c.MissingOutputFields...)
+		add(CodeMissingInput, c.MissingInputFields...)
+		for code, found := range map[string]bool{
+			CodeHardcoded:       len(c.HardcodedFields) > 0,
+			CodeDuplicateWrites: len(c.DuplicateWrites) > 0,
+			CodeSwapped:         len(c.SwappedFields) > 0,
+			CodeUnhandledOneof:  len(c.UnhandledOneofs) > 0,
+			CodeUnmapped:        len(c.UnmappedFields) > 0,
+			CodeUnknownCoverage: c.UnknownCoverage != "",
+		} {
+			if found {
+				add(code, "")
+			}
+		}
+	}
+}
+
+// fields returns fields of the converter's findings with the code recorded in the baseline.
+// all is set if the baseline records the code for the converter as a whole.
+func (b *Baseline) fields(pass *analysis.Pass, fn *Func, code string) (fields *UsageLookup, all bool) {
+	fields = NewUsageLookup()
+	if b == nil {
+		return fields, false
+	}
+	name := fn.qualifiedName()
+	for _, e := range b.Findings {
+		if e.Package != pass.Pkg.Path() || e.Function != name || e.Code != code {
+			continue
+		}
+		if e.Field == "" {
+			all = true
+		}
+		fields.Add(e.Field)
+	}
+	return fields, all
+}
+
+// baselined reports whether the baseline records findings with the code for the converter.
+func (b *Baseline) baselined(pass *analysis.Pass, fn *Func, code string) bool {
+	_, all := b.fields(pass, fn, code)
+	return all
+}
@@ -0,0 +1,68 @@
+package sf
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// builderFieldPlaceholder is replaced with a field name in builder method templates.
+const builderFieldPlaceholder = "{Field}"
+
+// CollectBuilderFields returns fields of the output struct st written through a builder.
+// A builder is any value whose build method (see Config.BuilderBuildMethods) is called in fn:
+// every method called on it, either in a chain (NewBuilder().Label(x).Build())
+// or on the builder variable (b.Label(x); b.Build()), is mapped to a field via
+// Config.BuilderMethods templates (e.g. "With{Field}").
+func CollectBuilderFields(fn *Func, cfg *Config, st *types.Struct) *UsageLookup {
+	ul := NewUsageLookup()
+	if len(cfg.BuilderMethods) == 0 || len(cfg.BuilderBuildMethods) == 0 {
+		return ul
+	}
+
+	methods := NewUsageLookup()
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !slices.Contains(cfg.BuilderBuildMethods, sel.Sel.Name) {
+			return true
+		}
+
+		// Walk down the chain of calls: b.A(x).B(y).Build()
+		x := sel.X
+		for {
+			c, ok := x.(*ast.CallExpr)
+			if !ok {
+				break
+			}
+			s, ok := c.Fun.(*ast.SelectorExpr)
+			if !ok {
+				break
+			}
+			methods.Add(s.Sel.Name)
+			x = s.X
+		}
+
+		// The chain is rooted in a builder variable: collect everything called on it.
+		if ident, ok := x.(*ast.Ident); ok {
+			methods.AddAll(variable{name: ident.Name, obj: fn.object(ident)}.collector(fn.info, RecordMethods).Walk(fn.Body))
+		}
+		return true
+	})
+
+	for i := 0; i < st.NumFields(); i++ {
+		name := st.Field(i).Name()
+		for _, tpl := range cfg.BuilderMethods {
+			if methods.LookUp(strings.ReplaceAll(tpl, builderFieldPlaceholder, name)) {
+				ul.Add(name)
+				break
+			}
+		}
+	}
+
+	return ul
+}
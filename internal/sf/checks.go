@@ -0,0 +1,153 @@
+package sf
+
+import (
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Check is a sub-check of the analyzer reporting findings of some codes only,
+// so drivers like multichecker can pick and choose among them.
+type Check struct {
+	// Name is the name of the check's analyzer.
+	Name string
+	// Doc describes the check.
+	Doc string
+	// Codes are the codes of findings reported by the check.
+	Codes []string
+	// enable turns on options the check is about, which are off by default.
+	enable func(cfg *Config)
+}
+
+// Checks are sub-checks covering all codes of findings.
+var Checks = []Check{
+	{
+		Name:  "leakingfields",
+		Doc:   "reports converter functions leaking fields: unread input or unwritten output fields",
+		Codes: []string{CodeMissingOutput, CodeMissingInput, CodeUnhandledOneof, CodeUnmapped, CodeUnknownCoverage},
+	},
+	{
+		Name:  "hardcodedfields",
+		Doc:   "reports output fields of converter functions assigned constants instead of the same-named input fields",
+		Codes: []string{CodeHardcoded},
+		enable: func(cfg *Config) {
+			cfg.ReportHardcoded = true
+		},
+	},
+	{
+		Name:  "duplicatewrites",
+		Doc:   "reports output fields of converter functions written more than once",
+		Codes: []string{CodeDuplicateWrites},
+		enable: func(cfg *Config) {
+			cfg.ReportDuplicateWrites = true
+		},
+	},
+	{
+		Name:  "swappedfields",
+		Doc:   "reports output fields of converter functions possibly assigned from a wrong input field",
+		Codes: []string{CodeSwapped},
+		enable: func(cfg *Config) {
+			cfg.ReportSwapped = true
+		},
+	},
+	{
+		Name:  "unkeyedliterals",
+		Doc:   "reports outputs of converter functions built with unkeyed composite literals",
+		Codes: []string{CodeUnkeyedLiteral},
+		enable: func(cfg *Config) {
+			cfg.ReportUnkeyed = true
+		},
+	},
+	{
+		Name:  "unregisteredconverters",
+		Doc:   "reports converter functions missing from registries of functions with the same signature",
+		Codes: []string{CodeUnregistered},
+		enable: func(cfg *Config) {
+			cfg.CheckRegistries = true
+		},
+	},
+	{
+		Name:  "roundtrips",
+		Doc:   "reports fields mapped by converter functions that their reverse converters do not map back",
+		Codes: []string{CodeRoundTrip},
+		enable: func(cfg *Config) {
+			cfg.CheckRoundTrips = true
+		},
+	},
+	{
+		Name:  "missingreverse",
+		Doc:   "reports converter functions having no converter in the other direction",
+		Codes: []string{CodeMissingReverse},
+		enable: func(cfg *Config) {
+			cfg.CheckReverse = true
+		},
+	},
+	{
+		Name:  "naming",
+		Doc:   "reports converter functions not following the naming convention",
+		Codes: []string{CodeNaming},
+		enable: func(cfg *Config) {
+			cfg.CheckNaming = true
+		},
+	},
+	{
+		Name:  "duplicateconverters",
+		Doc:   "reports converter functions converting the same types as another one",
+		Codes: []string{CodeDuplicateConverter},
+		enable: func(cfg *Config) {
+			cfg.CheckDuplicates = true
+		},
+	},
+	{
+		Name:  "layering",
+		Doc:   "reports converter functions between layers that the layering rules don't allow",
+		Codes: []string{CodeLayering},
+		enable: func(cfg *Config) {
+			cfg.CheckLayers = true
+		},
+	},
+	{
+		Name:  "purity",
+		Doc:   "reports converter functions mutating their input",
+		Codes: []string{CodeInputMutation},
+		enable: func(cfg *Config) {
+			cfg.CheckPurity = true
+		},
+	},
+	{
+		Name:  "nilresults",
+		Doc:   "reports dereferences of pointer named results of converter functions before they're assigned",
+		Codes: []string{CodeNilResult},
+		enable: func(cfg *Config) {
+			cfg.CheckNilResults = true
+		},
+	},
+	{
+		Name:  "errors",
+		Doc:   "reports converter functions that can fail but return no error and ones not checking their pointer input for nil",
+		Codes: []string{CodeMissingError, CodeNilInput},
+		enable: func(cfg *Config) {
+			cfg.CheckErrors = true
+		},
+	},
+}
+
+// NewCheckAnalyzer creates the analyzer of the check bound to the given configuration.
+// Codes of other checks are disabled.
+func NewCheckAnalyzer(check Check, cfg *Config) *analysis.Analyzer {
+	if check.enable != nil {
+		check.enable(cfg)
+	}
+	cfg.Disable = nil
+	for code := range CodeDocs {
+		if !slices.Contains(check.Codes, code) {
+			cfg.Disable = append(cfg.Disable, code)
+		}
+	}
+	slices.Sort(cfg.Disable)
+
+	a := NewAnalyzer(cfg)
+	a.Name = check.Name
+	a.Doc = check.Doc
+	return a
+}
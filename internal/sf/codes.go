@@ -0,0 +1,130 @@
+package sf
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Codes of findings reported by the analyzer. They are stable, used as categories
+// of diagnostics (see analysis.Diagnostic.Category) and prefix their messages.
+// CodeDeadField is reported by the command aggregating converters of all packages (see DeadFields).
+const (
+	CodeMissingOutput      = "SF001"
+	CodeMissingInput       = "SF002"
+	CodeHardcoded          = "SF003"
+	CodeDuplicateWrites    = "SF004"
+	CodeSwapped            = "SF005"
+	CodeUnkeyedLiteral     = "SF006"
+	CodeUnhandledOneof     = "SF007"
+	CodeUnmapped           = "SF008"
+	CodeUnknownCoverage    = "SF009"
+	CodeUnregistered       = "SF010"
+	CodeRoundTrip          = "SF011"
+	CodeMissingReverse     = "SF012"
+	CodeDeadField          = "SF013"
+	CodeNaming             = "SF014"
+	CodeDuplicateConverter = "SF015"
+	CodeLayering           = "SF016"
+	CodeInputMutation      = "SF017"
+	CodeNilResult          = "SF018"
+	CodeMissingError       = "SF019"
+	CodeNilInput           = "SF020"
+)
+
+// CategoryOmitted is the category of the diagnostic telling how many findings of a package were omitted
+// (see Config.MaxIssues). It's no finding of its own: SARIF reports skip it and it never fails runs.
+const CategoryOmitted = "omitted"
+
+// CodeDocs describes every code of findings, e.g. for rule metadata of SARIF reports.
+var CodeDocs = map[string]string{
+	CodeMissingOutput:      "Converter does not write a field of its output model",
+	CodeMissingInput:       "Converter does not read a field of its input model",
+	CodeHardcoded:          "Converter assigns constants to output fields instead of mapping input ones",
+	CodeDuplicateWrites:    "Converter writes an output field more than once",
+	CodeSwapped:            "Converter possibly assigns an output field from the wrong input field",
+	CodeUnkeyedLiteral:     "Converter builds its output with an unkeyed composite literal",
+	CodeUnhandledOneof:     "Converter does not handle a oneof variant of a proto message",
+	CodeUnmapped:           "Converter does not assign a configured field mapping",
+	CodeUnknownCoverage:    "Converter field coverage cannot be determined statically",
+	CodeUnregistered:       "Converter is missing from the registry of converters with its signature",
+	CodeRoundTrip:          "Reverse converter does not map back a field mapped by the converter",
+	CodeMissingReverse:     "Converter has no reverse converter in the package",
+	CodeDeadField:          "Field of a model is mapped by none of its converters",
+	CodeNaming:             "Converter does not follow the naming convention",
+	CodeDuplicateConverter: "Converter converts the same types as another converter of the package",
+	CodeLayering:           "Converter converts models between layers the layering rules don't allow",
+	CodeInputMutation:      "Converter mutates its input",
+	CodeNilResult:          "Converter dereferences a pointer named result before assigning it",
+	CodeMissingError:       "Converter can fail but does not return an error",
+	CodeNilInput:           "Converter dereferences its pointer input without checking it for nil",
+}
+
+// withCodes prefixes the message with the codes of findings it reports.
+func withCodes(message string, codes ...string) string {
+	return strings.Join(codes, ", ") + ": " + message
+}
+
+// Severities of findings.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// defaultSeverities are severities of codes of findings other than SeverityWarning,
+// for conventions rather than mapping mistakes.
+var defaultSeverities = map[string]string{
+	CodeMissingError: SeverityInfo,
+	CodeNilInput:     SeverityInfo,
+}
+
+// Severities maps codes of findings to their severities (their default ones if not listed:
+// SeverityInfo of CodeMissingError and CodeNilInput, SeverityWarning of others).
+// It's usable as a flag.Value.
+type Severities map[string]string
+
+func (s *Severities) String() string {
+	if s == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*s))
+	for _, code := range slices.Sorted(maps.Keys(*s)) {
+		parts = append(parts, code+"="+(*s)[code])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses comma-separated code=severity entries and adds them to the map.
+func (s *Severities) Set(value string) error {
+	for _, entry := range splitList(value) {
+		code, severity, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid severity %q: expected CODE=%s|%s|%s", entry, SeverityError, SeverityWarning, SeverityInfo)
+		}
+		// Codes are case-insensitive, as keys of settings may be lowercased (e.g. by golangci-lint).
+		code, severity = strings.ToUpper(strings.TrimSpace(code)), strings.TrimSpace(severity)
+		if err := checkSeverity(code, severity); err != nil {
+			return err
+		}
+		if *s == nil {
+			*s = make(Severities)
+		}
+		(*s)[code] = severity
+	}
+	return nil
+}
+
+// checkSeverity checks the code of findings and its severity are known.
+func checkSeverity(code, severity string) error {
+	if _, ok := CodeDocs[code]; !ok {
+		return fmt.Errorf("unknown code %q of findings", code)
+	}
+	switch severity {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return nil
+	}
+	return fmt.Errorf("invalid severity %q of %s: expected %q, %q or %q",
+		severity, code, SeverityError, SeverityWarning, SeverityInfo)
+}
@@ -3,6 +3,9 @@ package sf
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
+	"iter"
+	"slices"
 	"strings"
 )
 
@@ -15,26 +18,134 @@ const (
 	RecordFields
 )
 
-// UsageLookup is a map storing name of fields/methods that were used
-type UsageLookup map[string]struct{}
+// *UsageLookup is a set storing names of fields/methods that were used.
+// Bound to the interned index of a struct's fields (see bind), it keeps fields of the struct as bits
+// and only other names (e.g. of methods) as strings, so converters of structs with hundreds of fields
+// are checked without hashing and allocating per field.
+type UsageLookup struct {
+	index *fieldIndex
+	bits  bitset
+	names map[string]struct{}
+}
+
+// NewUsageLookup returns an unbound set of the given names.
+func NewUsageLookup(names ...string) *UsageLookup {
+	ul := &UsageLookup{}
+	for _, name := range names {
+		ul.Add(name)
+	}
+	return ul
+}
+
+// newFieldUsage returns an empty set bound to the field index.
+func newFieldUsage(index *fieldIndex) *UsageLookup {
+	return &UsageLookup{index: index, bits: newBitset(len(index.names))}
+}
+
+func (ul *UsageLookup) Add(v string) {
+	if i, ok := ul.index.lookup(v); ok {
+		ul.bits.set(i)
+		return
+	}
+	if ul.names == nil {
+		ul.names = make(map[string]struct{})
+	}
+	ul.names[v] = struct{}{}
+}
+
+// AddAll adds all names of the other set.
+func (ul *UsageLookup) AddAll(other *UsageLookup) {
+	if other == nil {
+		return
+	}
+	if other.index == ul.index {
+		for i, w := range other.bits {
+			ul.bits[i] |= w
+		}
+	} else {
+		for i, name := range other.index.fieldNames() {
+			if other.bits.has(i) {
+				ul.Add(name)
+			}
+		}
+	}
+	for name := range other.names {
+		ul.Add(name)
+	}
+}
 
-func (ul UsageLookup) LookUp(v string) bool {
-	_, ok := ul[v]
+// LookUp reports whether the name is in the set. It's safe to call on a nil set.
+func (ul *UsageLookup) LookUp(v string) bool {
+	if ul == nil {
+		return false
+	}
+	if i, ok := ul.index.lookup(v); ok {
+		return ul.bits.has(i)
+	}
+	_, ok := ul.names[v]
 	return ok
 }
 
+// has reports whether the field at position i of the index the set is bound to is in the set.
+func (ul *UsageLookup) has(i int) bool {
+	return ul.bits.has(i)
+}
+
+// Len returns the number of names in the set.
+func (ul *UsageLookup) Len() int {
+	if ul == nil {
+		return 0
+	}
+	return ul.bits.len() + len(ul.names)
+}
+
+// All iterates over names in the set: fields of the bound struct in the order of declaration
+// and other names in no particular order.
+func (ul *UsageLookup) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if ul == nil {
+			return
+		}
+		for i, name := range ul.index.fieldNames() {
+			if ul.bits.has(i) && !yield(name) {
+				return
+			}
+		}
+		for name := range ul.names {
+			if !yield(name) {
+				return
+			}
+		}
+	}
+}
+
+// bind returns the set bound to the field index: the set itself if it's bound to it already,
+// a copy otherwise.
+func (ul *UsageLookup) bind(index *fieldIndex) *UsageLookup {
+	if ul != nil && ul.index == index {
+		return ul
+	}
+	bound := newFieldUsage(index)
+	bound.AddAll(ul)
+	return bound
+}
+
 // UsageCollector is a generic AST visitor that collects selector usage for a given variable.
 // rType(RecordingType) stands for the type of things we record: fields or methods
+// The variable is matched by its name, unless it's resolved to its object (see Resolve).
 type UsageCollector struct {
-	used        UsageLookup
+	used        *UsageLookup
 	varName     string
+	info        *types.Info
+	obj         types.Object
 	parentStack []ast.Node
 	nodesType   CollectingType
+	skipped     map[ast.Node]bool
 }
 
 func NewUsageCollector(varName string, rType CollectingType) *UsageCollector {
 	return &UsageCollector{
-		used:        make(UsageLookup),
+		used:        NewUsageLookup(),
 		varName:     varName,
 		parentStack: make([]ast.Node, 0),
 		nodesType:   rType,
@@ -51,6 +162,11 @@ func (v *UsageCollector) Visit(container ast.Node) ast.Visitor {
 		return nil
 	}
 
+	// Usages within skipped nodes are not recorded.
+	if v.skipped[container] {
+		return nil
+	}
+
 	// Push the current node onto the parent stack.
 	v.parentStack = append(v.parentStack, container)
 
@@ -60,9 +176,14 @@ func (v *UsageCollector) Visit(container ast.Node) ast.Visitor {
 		return v
 	}
 
-	// Check that the expression's X is an identifier matching varName.
-	ident, ok := sel.X.(*ast.Ident)
-	if !ok || ident.Name != v.varName {
+	// Check that the expression's X is an identifier matching varName
+	// or an element of it (e.g. items[i].ID for slices and maps).
+	x := sel.X
+	if index, ok := x.(*ast.IndexExpr); ok {
+		x = index.X
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok || !v.matches(ident) {
 		return v
 	}
 
@@ -77,40 +198,69 @@ func (v *UsageCollector) Visit(container ast.Node) ast.Visitor {
 
 	// Decide based on the mode.
 	if v.nodesType == RecordMethods && isMethodCall || !isMethodCall {
-		v.used[sel.Sel.Name] = struct{}{}
+		v.used.Add(sel.Sel.Name)
 	}
 
 	return v
 }
 
+// matches reports whether the identifier refers to the collected variable.
+func (v *UsageCollector) matches(ident *ast.Ident) bool {
+	if v.obj != nil {
+		return v.info.Uses[ident] == v.obj
+	}
+	return ident.Name == v.varName
+}
+
+// Resolve makes the collector match identifiers by the object they refer to rather than by name,
+// so variables of the same name shadowing it in nested blocks are not counted.
+// A nil info or object leaves matching by name.
+func (v *UsageCollector) Resolve(info *types.Info, obj types.Object) *UsageCollector {
+	if info != nil && obj != nil {
+		v.info, v.obj = info, obj
+	}
+	return v
+}
+
+// Skip makes the collector ignore usages within the given nodes (e.g. arguments of logging calls).
+func (v *UsageCollector) Skip(nodes ...ast.Node) *UsageCollector {
+	if v.skipped == nil {
+		v.skipped = make(map[ast.Node]bool, len(nodes))
+	}
+	for _, n := range nodes {
+		v.skipped[n] = true
+	}
+	return v
+}
+
 func (v *UsageCollector) reset() {
 	v.parentStack = make([]ast.Node, 0)
-	v.used = make(UsageLookup)
+	v.used = NewUsageLookup()
 }
 
-func (v *UsageCollector) Walk(container ast.Node) UsageLookup {
+func (v *UsageCollector) Walk(container ast.Node) *UsageLookup {
 	v.reset()
 	ast.Walk(v, container)
 	return v.used
 }
 
-// CollectUsedFields walks the AST rooted at n and returns a set (UsageLookup)
+// CollectUsedFields walks the AST rooted at n and returns a set (*UsageLookup)
 // of field names that are directly accessed on varName (ignoring any method calls).
-func CollectUsedFields(n ast.Node, varName string) UsageLookup {
+func CollectUsedFields(n ast.Node, varName string) *UsageLookup {
 	return NewUsageCollector(varName, RecordFields).Walk(n)
 }
 
-// CollectUsedMethods walks the AST rooted at n and returns a set (UsageLookup)
+// CollectUsedMethods walks the AST rooted at n and returns a set (*UsageLookup)
 // of method names that are called on varName.
-func CollectUsedMethods(n ast.Node, varName string) UsageLookup {
+func CollectUsedMethods(n ast.Node, varName string) *UsageLookup {
 	return NewUsageCollector(varName, RecordMethods).Walk(n)
 }
 
 // CollectCompositeLitKeys scans for composite literals of type matching the given candidate,
 // and returns a set of keys (field names) that appear in the literal.
 // (We assume keys are simple identifiers; more complex cases can be added as needed.)
-func CollectCompositeLitKeys(n ast.Node, candidateName string) UsageLookup {
-	ul := make(UsageLookup)
+func CollectCompositeLitKeys(n ast.Node, candidateName string) *UsageLookup {
+	ul := NewUsageLookup()
 	ast.Inspect(n, func(node ast.Node) bool {
 		cl, ok := node.(*ast.CompositeLit)
 		if !ok {
@@ -123,7 +273,7 @@ func CollectCompositeLitKeys(n ast.Node, candidateName string) UsageLookup {
 					continue
 				}
 				if keyIdent, ok := kv.Key.(*ast.Ident); ok {
-					ul[keyIdent.Name] = struct{}{}
+					ul.Add(keyIdent.Name)
 				}
 			}
 		}
@@ -138,52 +288,425 @@ func CollectCompositeLitKeys(n ast.Node, candidateName string) UsageLookup {
 //
 //	(a) If outVar is non-empty, it collects direct field accesses on that variable.
 //	(b) It also scans assignment and return statements to find composite literals
-//	    (or their address-of forms) whose type (or underlying type) matches candidateName,
+//	    (or their address-of forms) whose type (or underlying type) matches candidate,
 //	    then collects the keys (i.e. field names) provided in the literal.
 //
 // CollectOutputFields inspects fn.Body and returns a set of field names that are used in
 // constructing the output value of the converter. It does two things:
 //
 //	(a) If outVar is non-empty or can be determined from a local declaration, it collects direct
-//	    field accesses on that variable (e.g. out.ID = ...).
+//	    field accesses on that variable (e.g. out.ID = ...), including accesses within deferred function
+//	    literals finalizing a named result (e.g. defer func() { out.UpdatedAt = now() }()).
 //	(b) It scans assignment and return statements for composite literals that initialize a value
-//	    of type candidateName (e.g. out = &Category{ Type: ... }).
-func CollectOutputFields(fn *ast.FuncDecl, outVar, candidateName string) UsageLookup {
-	ul := make(UsageLookup)
+//	    of type candidate (e.g. out = &Category{ Type: ... }).
+//
+// Field accesses within the skipped nodes are ignored.
+func CollectOutputFields(fn *Func, outVar string, candidate *types.TypeName, skip ...ast.Node) *UsageLookup {
+	ul := NewUsageLookup()
 
-	// If no output variable was provided (e.g. unnamed result), try to find a local candidate.
-	if outVar == "" {
-		outVar = findLocalCandidateVariable(fn, candidateName)
+	// (a) If we have output variables, collect direct field accesses.
+	for _, v := range outputVariables(fn, outVar, candidate) {
+		ul.AddAll(v.collector(fn.info, RecordFields).Skip(skip...).Walk(fn.Body))
 	}
 
-	// (a) If we have an output variable, collect direct field accesses.
-	if outVar != "" {
-		for k := range CollectUsedFields(fn.Body, outVar) {
-			ul[k] = struct{}{}
+	// (b) Scan the function body for composite literals in assignments and return statements.
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for _, expr := range stmt.Rhs {
+				for _, elem := range appendedOrSelf(fn.info, expr) {
+					extractKeysFromExpr(fn.info, elem, candidate, ul)
+				}
+			}
+		case *ast.ReturnStmt:
+			for _, expr := range stmt.Results {
+				extractKeysFromExpr(fn.info, expr, candidate, ul)
+			}
+		}
+		return true
+	})
+
+	return ul
+}
+
+// blankAssignments returns assignments discarding all their values (e.g. _ = in.Label).
+// Reads within them do not bring the value anywhere.
+func blankAssignments(n ast.Node) []ast.Node {
+	var assigns []ast.Node
+	ast.Inspect(n, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range stmt.Lhs {
+			if ident, ok := lhs.(*ast.Ident); !ok || ident.Name != "_" {
+				return true
+			}
 		}
+		assigns = append(assigns, stmt)
+		return false
+	})
+	return assigns
+}
+
+// appendedOrSelf returns elements appended by expr if it's a call of the append builtin
+// (e.g. the literal in `out = append(out, db.Sample{...})`). Otherwise, it returns expr itself.
+func appendedOrSelf(info *types.Info, expr ast.Expr) []ast.Expr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 || call.Ellipsis.IsValid() {
+		return []ast.Expr{expr}
 	}
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || ident.Name != "append" {
+		return []ast.Expr{expr}
+	}
+	if info != nil {
+		if _, ok := info.Uses[ident].(*types.Builtin); !ok {
+			return []ast.Expr{expr}
+		}
+	}
+	return call.Args[1:]
+}
 
-	// (b) Scan the function body for composite literals in assignments and return statements.
+// aliasVariables returns local variables of fn holding copies of in, of its dereference
+// or of its address (s := in, tmp := *in, var p = &in), following copies of copies.
+// Parameters of function literals called with them (e.g. apply := func(s *Sample) {...}; apply(&in))
+// hold copies as well. Variables are resolved to their objects, so variables reusing the name
+// of a copy in other scopes are not taken for it.
+func aliasVariables(fn *Func, in variable) variables {
+	aliases := variables{in}
+	lits := make(map[any]*ast.FuncLit)
+	var vars variables
+	isAlias := func(expr ast.Expr) bool {
+		ident, ok := derefIdent(expr)
+		return ok && aliases.contain(fn.info, ident)
+	}
+	addIdent := func(ident *ast.Ident, expr ast.Expr) {
+		if ident.Name == "_" || aliases.contain(fn.info, ident) || !isAlias(expr) {
+			return
+		}
+		v := variable{name: ident.Name, obj: fn.object(ident)}
+		aliases = append(aliases, v)
+		vars = append(vars, v)
+	}
+	add := func(lhs []ast.Expr, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, expr := range rhs {
+			ident, ok := lhs[i].(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if lit, ok := ast.Unparen(expr).(*ast.FuncLit); ok {
+				lits[fn.identKey(ident)] = lit
+			}
+			addIdent(ident, expr)
+		}
+	}
+
+	// Statements are inspected in the order of the source, so copies of copies are found at once.
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			add(x.Lhs, x.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(x.Names))
+			for i, name := range x.Names {
+				lhs[i] = name
+			}
+			add(lhs, x.Values)
+		case *ast.CallExpr:
+			lit, ok := ast.Unparen(x.Fun).(*ast.FuncLit)
+			if ident, isIdent := ast.Unparen(x.Fun).(*ast.Ident); isIdent {
+				lit, ok = lits[fn.identKey(ident)]
+			}
+			if !ok || x.Ellipsis.IsValid() {
+				return true
+			}
+			i := 0
+			for _, field := range lit.Type.Params.List {
+				for _, name := range field.Names {
+					if i < len(x.Args) {
+						addIdent(name, x.Args[i])
+					}
+					i++
+				}
+				if len(field.Names) == 0 {
+					i++
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// elementVariables returns variables of fn holding elements of the slice or map v:
+// values of range loops over it (for _, e := range items) and copies of its elements (e := items[i]).
+// Each variable is resolved to its own object, so loops reusing the name of the value
+// are collected separately.
+func elementVariables(fn *Func, v variable) variables {
+	var vars variables
+	isVar := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && v.matches(fn.info, ident)
+	}
+	add := func(expr ast.Expr) {
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+			vars = append(vars, variable{name: ident.Name, obj: fn.object(ident)})
+		}
+	}
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch stmt := n.(type) {
+		case *ast.RangeStmt:
+			if isVar(stmt.X) {
+				add(stmt.Value)
+			}
 		case *ast.AssignStmt:
-			for _, expr := range stmt.Rhs {
-				extractKeysFromExpr(expr, candidateName, ul)
+			if len(stmt.Lhs) != len(stmt.Rhs) {
+				return true
+			}
+			for i, rhs := range stmt.Rhs {
+				rhs = ast.Unparen(rhs)
+				if u, ok := rhs.(*ast.UnaryExpr); ok && u.Op == token.AND {
+					rhs = ast.Unparen(u.X)
+				}
+				if index, ok := rhs.(*ast.IndexExpr); ok && isVar(index.X) {
+					add(stmt.Lhs[i])
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// OutputBranch is a composite literal constructing the output value of a converter
+// along with the fields it writes. Output values may also come from calls (e.g. out = buildSpecial(in)):
+// then Call is set instead of Lit, and fields written by the callee are not known until it's resolved.
+type OutputBranch struct {
+	Lit *ast.CompositeLit
+	// Call returns the output value as its result at the index Result.
+	Call   *ast.CallExpr
+	Result int
+	Fields *UsageLookup
+}
+
+// end returns the position of the end of the literal or call.
+func (b OutputBranch) end() token.Pos {
+	if b.Lit != nil {
+		return b.Lit.Rbrace
+	}
+	return b.Call.Rparen
+}
+
+// CollectOutputBranches inspects fn.Body and returns field sets of every value constructing the output
+// of the converter: literals and calls returned directly (e.g. return Category{...}) and assigned
+// to the output variable (e.g. out = &Category{...}, out = buildSpecial(in)). Each set also includes
+// direct field writes on the output variable, as they apply to whichever value was chosen.
+// Returns of nested function literals are ignored as they belong to those functions,
+// and so are early exits: zero-value literals and literals returned along with a non-nil error.
+// Field accesses within the skipped nodes are ignored.
+func CollectOutputBranches(fn *Func, outVar string, candidate *types.TypeName, skip ...ast.Node) []OutputBranch {
+	outVars := outputVariables(fn, outVar, candidate)
+	direct := NewUsageLookup()
+	for _, v := range outVars {
+		direct.AddAll(v.collector(fn.info, RecordFields).Skip(skip...).Walk(fn.Body))
+	}
+
+	var branches []OutputBranch
+	addLiteral := func(expr ast.Expr) {
+		cl := candidateLiteral(fn.info, expr, candidate)
+		if cl == nil {
+			return
+		}
+		fields := NewUsageLookup()
+		fields.AddAll(direct)
+		extractKeysFromExpr(fn.info, expr, candidate, fields)
+		branches = append(branches, OutputBranch{Lit: cl, Fields: fields})
+	}
+	addCall := func(expr ast.Expr, result int) {
+		call := candidateCall(fn.info, expr, result, candidate)
+		if call == nil {
+			return
+		}
+		fields := NewUsageLookup()
+		fields.AddAll(direct)
+		branches = append(branches, OutputBranch{Call: call, Result: result, Fields: fields})
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			// Calls returning several values, e.g. out, err = build(in).
+			if len(stmt.Rhs) == 1 && len(stmt.Lhs) > 1 {
+				for i, lhs := range stmt.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && outVars.contain(fn.info, ident) {
+						addCall(stmt.Rhs[0], i)
+					}
+				}
+				return true
+			}
+			if len(stmt.Lhs) != len(stmt.Rhs) {
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && outVars.contain(fn.info, ident) {
+					for _, elem := range appendedOrSelf(fn.info, stmt.Rhs[i]) {
+						addLiteral(elem)
+						addCall(elem, 0)
+					}
+				}
 			}
 		case *ast.ReturnStmt:
+			// Error paths are early exits, not conversions.
+			if isErrorReturn(fn.info, stmt) {
+				return true
+			}
 			for _, expr := range stmt.Results {
-				extractKeysFromExpr(expr, candidateName, ul)
+				// So are zero values, e.g. `return db.Sample{}`.
+				if cl := candidateLiteral(fn.info, expr, candidate); cl != nil && len(cl.Elts) == 0 {
+					continue
+				}
+				addLiteral(expr)
+				addCall(expr, 0)
 			}
 		}
 		return true
 	})
 
+	return branches
+}
+
+// candidateCall returns the expression if it's a call of a function (not a conversion or a builtin)
+// whose result at the given index is of type candidate or a pointer to it.
+func candidateCall(info *types.Info, expr ast.Expr, result int, candidate *types.TypeName) *ast.CallExpr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || info == nil {
+		return nil
+	}
+	if tv, ok := info.Types[call.Fun]; !ok || tv.IsType() || tv.IsBuiltin() {
+		return nil
+	}
+	t := info.TypeOf(call)
+	if tuple, ok := t.(*types.Tuple); ok {
+		if result >= tuple.Len() {
+			return nil
+		}
+		t = tuple.At(result).Type()
+	} else if result > 0 {
+		return nil
+	}
+	if named := namedType(t); named == nil || named.Obj() != candidate {
+		return nil
+	}
+	return call
+}
+
+// isErrorReturn reports whether the return statement returns a non-nil error as its last result.
+func isErrorReturn(info *types.Info, stmt *ast.ReturnStmt) bool {
+	if info == nil || len(stmt.Results) == 0 {
+		return false
+	}
+	last := stmt.Results[len(stmt.Results)-1]
+	if ident, ok := ast.Unparen(last).(*ast.Ident); ok && ident.Name == "nil" {
+		return false
+	}
+	t := info.TypeOf(last)
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// intersectUsage returns items present in every given lookup.
+func intersectUsage(lookups ...*UsageLookup) *UsageLookup {
+	ul := NewUsageLookup()
+	if len(lookups) == 0 {
+		return ul
+	}
+	for k := range lookups[0].All() {
+		found := true
+		for _, other := range lookups[1:] {
+			if !other.LookUp(k) {
+				found = false
+				break
+			}
+		}
+		if found {
+			ul.Add(k)
+		}
+	}
 	return ul
 }
 
 // extractKeysFromExpr examines expr and, if it is or contains a composite literal
-// that initializes a value of type candidateName, it extracts any key names and adds them to keys.
-func extractKeysFromExpr(expr ast.Expr, candidateName string, keys UsageLookup) {
+// that initializes a value of type candidate, it extracts any key names and adds them to keys.
+func extractKeysFromExpr(info *types.Info, expr ast.Expr, candidate *types.TypeName, keys *UsageLookup) {
+	cl := candidateLiteral(info, expr, candidate)
+	if cl == nil {
+		return
+	}
+
+	// Extract keys from key-value pairs (or positions of unkeyed elements).
+	forEachLiteralField(info, cl, func(name string, _ ast.Expr) {
+		keys.Add(name)
+	})
+}
+
+// forEachLiteralField calls f for every field initialized by the composite literal with the field name and value.
+// Unkeyed (positional) elements are mapped to struct fields by their index, which requires type information.
+func forEachLiteralField(info *types.Info, cl *ast.CompositeLit, f func(name string, value ast.Expr)) {
+	var st *types.Struct
+	if t := typeOf(info, cl); t != nil {
+		st, _ = t.Underlying().(*types.Struct)
+	}
+
+	for i, elt := range cl.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			if st != nil && i < st.NumFields() {
+				f(st.Field(i).Name(), elt)
+			}
+			continue
+		}
+		if keyIdent, ok := kv.Key.(*ast.Ident); ok {
+			f(keyIdent.Name, kv.Value)
+		}
+	}
+}
+
+// isUnkeyedLiteral reports whether the composite literal initializes fields by their positions.
+func isUnkeyedLiteral(cl *ast.CompositeLit) bool {
+	if len(cl.Elts) == 0 {
+		return false
+	}
+	_, keyed := cl.Elts[0].(*ast.KeyValueExpr)
+	return !keyed
+}
+
+// unkeyedLiterals returns unkeyed composite literals of type candidate in fn.Body.
+func unkeyedLiterals(fn *Func, candidate *types.TypeName) []*ast.CompositeLit {
+	var lits []*ast.CompositeLit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		expr, ok := n.(ast.Expr)
+		if !ok {
+			return true
+		}
+		if cl := candidateLiteral(fn.info, expr, candidate); cl != nil && cl == n && isUnkeyedLiteral(cl) {
+			lits = append(lits, cl)
+		}
+		return true
+	})
+	return lits
+}
+
+// candidateLiteral returns the composite literal (or the literal behind its address-of form)
+// if expr initializes a value of type candidate. Otherwise, it returns nil.
+// When type information is available, the literal's type is resolved through aliases and compared
+// with the candidate's declaration, so `DBSample{...}` matches the candidate dbmodel.Sample given
+// `type DBSample = dbmodel.Sample` while `model.Sample{...}` doesn't. Without it, types are matched by name.
+func candidateLiteral(info *types.Info, expr ast.Expr, candidate *types.TypeName) *ast.CompositeLit {
 	var cl *ast.CompositeLit
 
 	switch x := expr.(type) {
@@ -199,7 +722,16 @@ func extractKeysFromExpr(expr ast.Expr, candidateName string, keys UsageLookup)
 	}
 
 	if cl == nil {
-		return
+		return nil
+	}
+
+	if info != nil {
+		if named := namedType(info.TypeOf(cl)); named != nil {
+			if named.Obj() != candidate {
+				return nil
+			}
+			return cl
+		}
 	}
 
 	// Determine the type name of the composite literal.
@@ -213,27 +745,302 @@ func extractKeysFromExpr(expr ast.Expr, candidateName string, keys UsageLookup)
 	}
 
 	// Compare candidate names (optionally case-insensitively).
-	if !strings.EqualFold(typeName, candidateName) {
-		return
+	if !strings.EqualFold(typeName, candidate.Name()) {
+		return nil
 	}
 
-	// Extract keys from key-value pairs.
-	for _, elt := range cl.Elts {
-		kv, ok := elt.(*ast.KeyValueExpr)
-		if !ok {
+	return cl
+}
+
+// namedType resolves aliases and pointers and returns the named type behind t, if any.
+func namedType(t types.Type) *types.Named {
+	if t == nil {
+		return nil
+	}
+	t = types.Unalias(t)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(ptr.Elem())
+	}
+	named, _ := t.(*types.Named)
+	return named
+}
+
+// OutputWrites maps output field names to the expressions assigned to them.
+type OutputWrites map[string][]ast.Expr
+
+// AssignedFrom reports whether outField was assigned an expression reading the field inField of varName.
+// The variable is matched by its object obj unless it's nil (see UsageCollector.Resolve).
+func (w OutputWrites) AssignedFrom(info *types.Info, outField, varName string, obj types.Object, inField string) bool {
+	in := variable{name: varName, obj: obj}
+	for _, expr := range w[outField] {
+		if in.collector(info, RecordFields).Walk(expr).LookUp(inField) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hardcoded returns output fields having only constant values written into them (e.g. Label: "const label")
+// while the same-named field of varName exists in the input struct st and does not flow into any output field.
+// The variable is matched by its object obj unless it's nil (see UsageCollector.Resolve).
+func (w OutputWrites) Hardcoded(info *types.Info, varName string, obj types.Object, st *types.Struct) []string {
+	in := variable{name: varName, obj: obj}
+	flowing := NewUsageLookup()
+	for _, exprs := range w {
+		for _, expr := range exprs {
+			flowing.AddAll(in.collector(info, RecordFields).Walk(expr))
+		}
+	}
+
+	var hardcoded []string
+	for i := 0; i < st.NumFields(); i++ {
+		name := st.Field(i).Name()
+		exprs := w[name]
+		if len(exprs) == 0 || flowing.LookUp(name) {
 			continue
 		}
-		if keyIdent, ok := kv.Key.(*ast.Ident); ok {
-			keys[keyIdent.Name] = struct{}{}
+		constant := true
+		for _, expr := range exprs {
+			if !isConstant(info, expr) {
+				constant = false
+				break
+			}
+		}
+		if constant {
+			hardcoded = append(hardcoded, name)
+		}
+	}
+	return hardcoded
+}
+
+// Swapped returns assignments of an output field directly from a different field of varName
+// (e.g. out.FirstName = in.LastName), when the input struct st has a type-compatible field named
+// after the output field that is not used at all. It returns them as "outField = inField" pairs.
+// The variable is matched by its object obj unless it's nil (see UsageCollector.Resolve).
+func (w OutputWrites) Swapped(info *types.Info, varName string, obj types.Object, st *types.Struct, used *UsageLookup) [][2]string {
+	if info == nil {
+		return nil
+	}
+	in := variable{name: varName, obj: obj}
+
+	var swapped [][2]string
+	for i := 0; i < st.NumFields(); i++ {
+		same := st.Field(i)
+		if !same.Exported() || used.LookUp(same.Name()) {
+			continue
+		}
+		for _, expr := range w[same.Name()] {
+			sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name == same.Name() {
+				continue
+			}
+			if ident, ok := sel.X.(*ast.Ident); !ok || !in.matches(info, ident) {
+				continue
+			}
+			t := info.TypeOf(sel)
+			if t == nil || !types.ConvertibleTo(same.Type(), t) {
+				continue
+			}
+			swapped = append(swapped, [2]string{same.Name(), sel.Sel.Name})
+			break
+		}
+	}
+	return swapped
+}
+
+// isConstant reports whether expr is a constant or nil.
+func isConstant(info *types.Info, expr ast.Expr) bool {
+	if info == nil {
+		return false
+	}
+	tv, ok := info.Types[expr]
+	return ok && (tv.Value != nil || tv.IsNil())
+}
+
+// CollectOutputWrites inspects fn.Body and returns expressions written into each field of the
+// output value of the converter. It is the value-aware counterpart of CollectOutputFields:
+//
+//	(a) direct assignments on the output variable (e.g. out.ID = in.ID);
+//	(b) keyed elements of composite literals of type candidate (e.g. &Category{ID: in.ID}).
+func CollectOutputWrites(fn *Func, outVar string, candidate *types.TypeName) OutputWrites {
+	writes := make(OutputWrites)
+
+	outVars := outputVariables(fn, outVar, candidate)
+
+	addLiteral := func(expr ast.Expr) {
+		cl := candidateLiteral(fn.info, expr, candidate)
+		if cl == nil {
+			return
+		}
+		forEachLiteralField(fn.info, cl, func(name string, value ast.Expr) {
+			writes[name] = append(writes[name], value)
+		})
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) == len(stmt.Rhs) {
+				for i, lhs := range stmt.Lhs {
+					sel, ok := lhs.(*ast.SelectorExpr)
+					if !ok {
+						continue
+					}
+					x := sel.X
+					if index, ok := x.(*ast.IndexExpr); ok {
+						x = index.X
+					}
+					if ident, ok := x.(*ast.Ident); ok && outVars.contain(fn.info, ident) {
+						writes[sel.Sel.Name] = append(writes[sel.Sel.Name], stmt.Rhs[i])
+					}
+				}
+			}
+			for _, expr := range stmt.Rhs {
+				for _, elem := range appendedOrSelf(fn.info, expr) {
+					addLiteral(elem)
+				}
+			}
+		case *ast.ReturnStmt:
+			for _, expr := range stmt.Results {
+				addLiteral(expr)
+			}
+		}
+		return true
+	})
+
+	return writes
+}
+
+// variable is a variable of the converter, resolved to its object if type information is available
+// so variables of the same name shadowing it in nested blocks are told apart.
+type variable struct {
+	name string
+	obj  types.Object
+}
+
+// matches reports whether the identifier refers to the variable.
+func (v variable) matches(info *types.Info, ident *ast.Ident) bool {
+	if v.obj != nil {
+		return info.ObjectOf(ident) == v.obj
+	}
+	return ident.Name == v.name
+}
+
+// collector returns a collector of usages of the variable.
+func (v variable) collector(info *types.Info, rType CollectingType) *UsageCollector {
+	return NewUsageCollector(v.name, rType).Resolve(info, v.obj)
+}
+
+// variables is a list of variables of the converter.
+type variables []variable
+
+// contain reports whether the identifier refers to any of the variables.
+func (vs variables) contain(info *types.Info, ident *ast.Ident) bool {
+	return slices.ContainsFunc(vs, func(v variable) bool { return v.matches(info, ident) })
+}
+
+// outputVariables returns variables holding the output value of the converter:
+// outVar (or a local candidate variable if outVar is empty) and variables whose value
+// flows into it, e.g. tmp in `tmp := db.Sample{...}; result = tmp`, `return tmp` or `out = append(out, tmp)`.
+func outputVariables(fn *Func, outVar string, candidate *types.TypeName) variables {
+	var vars variables
+	if outVar != "" {
+		vars = append(vars, variable{name: outVar, obj: fn.variable(outVar)})
+	} else if ident := findLocalCandidateVariable(fn, candidate); ident != nil {
+		// No output variable was provided (e.g. unnamed result): try a local candidate.
+		vars = append(vars, variable{name: ident.Name, obj: fn.object(ident)})
+	}
+	add := func(expr ast.Expr) bool {
+		ident, ok := derefIdent(expr)
+		if !ok || ident.Name == "_" || ident.Name == "nil" || vars.contain(fn.info, ident) {
+			return false
 		}
+		vars = append(vars, variable{name: ident.Name, obj: fn.object(ident)})
+		return true
 	}
+
+	// Returned candidate variables hold the output as well.
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, expr := range x.Results {
+				if named := namedType(typeOf(fn.info, expr)); named != nil && named.Obj() == candidate {
+					add(expr)
+				}
+			}
+		}
+		return true
+	})
+
+	// Follow assignments and declarations of known output variables (e.g. result = base, var out = base)
+	// until no new intermediate variable is found.
+	for changed := true; changed; {
+		changed = false
+		follow := func(lhs []ast.Expr, rhs []ast.Expr) {
+			if len(lhs) != len(rhs) {
+				return
+			}
+			for i, lhs := range lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || !vars.contain(fn.info, ident) {
+					continue
+				}
+				// Elements appended to a slice output hold the output as well.
+				for _, elem := range appendedOrSelf(fn.info, rhs[i]) {
+					if add(elem) {
+						changed = true
+					}
+				}
+			}
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.AssignStmt:
+				follow(x.Lhs, x.Rhs)
+			case *ast.ValueSpec:
+				names := make([]ast.Expr, len(x.Names))
+				for i, name := range x.Names {
+					names[i] = name
+				}
+				follow(names, x.Values)
+			}
+			return true
+		})
+	}
+
+	return vars
+}
+
+// derefIdent returns the identifier behind expr, its address or its dereference (x, &x, *x).
+func derefIdent(expr ast.Expr) (*ast.Ident, bool) {
+	expr = ast.Unparen(expr)
+	switch x := expr.(type) {
+	case *ast.UnaryExpr:
+		if x.Op == token.AND {
+			expr = ast.Unparen(x.X)
+		}
+	case *ast.StarExpr:
+		expr = ast.Unparen(x.X)
+	}
+	ident, ok := expr.(*ast.Ident)
+	return ident, ok
+}
+
+// typeOf returns the type of expr if type information is available.
+func typeOf(info *types.Info, expr ast.Expr) types.Type {
+	if info == nil {
+		return nil
+	}
+	return info.TypeOf(expr)
 }
 
 // findLocalCandidateVariable scans the function body for a short variable declaration
-// that assigns a composite literal (or its address) of type candidateName. If found, it returns
-// the variable name (e.g. "out"). Otherwise, it returns the empty string.
-func findLocalCandidateVariable(fn *ast.FuncDecl, candidateName string) string {
-	var varName string
+// that assigns a composite literal (or its address) of type candidate. If found, it returns
+// the identifier of the variable (e.g. out). Otherwise, it returns nil.
+func findLocalCandidateVariable(fn *Func, candidate *types.TypeName) *ast.Ident {
+	var found *ast.Ident
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		decl, ok := n.(*ast.AssignStmt)
 		if !ok {
@@ -253,38 +1060,13 @@ func findLocalCandidateVariable(fn *ast.FuncDecl, candidateName string) string {
 			if i >= len(decl.Rhs) {
 				continue
 			}
-			expr := decl.Rhs[i]
-			var cl *ast.CompositeLit
-			switch x := expr.(type) {
-			case *ast.CompositeLit:
-				cl = x
-			case *ast.UnaryExpr:
-				// Handle cases like: out := &Category{ ... }
-				if x.Op == token.AND {
-					if lit, ok := x.X.(*ast.CompositeLit); ok {
-						cl = lit
-					}
-				}
-			}
-			if cl == nil {
-				continue
-			}
-			// Determine the type name of the composite literal.
-			var typeName string
-			switch t := cl.Type.(type) {
-			case *ast.Ident:
-				typeName = t.Name
-			case *ast.SelectorExpr:
-				// For types like models.Category, use the selector's identifier.
-				typeName = t.Sel.Name
-			}
-			// Compare candidate names case-insensitively.
-			if strings.EqualFold(typeName, candidateName) {
-				varName = ident.Name
+			// Compare the literal's type with the candidate.
+			if candidateLiteral(fn.info, decl.Rhs[i], candidate) != nil {
+				found = ident
 				return false // stop searching
 			}
 		}
 		return true
 	})
-	return varName
+	return found
 }
@@ -0,0 +1,758 @@
+package sf
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Directions of fields checked by the analyzer.
+const (
+	CheckBoth   = "both"   // both unread input and unwritten output fields are reported
+	CheckInput  = "input"  // only unread input fields are reported
+	CheckOutput = "output" // only unwritten output fields are reported
+)
+
+// Color modes of pretty-printed messages.
+const (
+	ColorAuto   = "auto"   // left to the driver (e.g. colored on terminals), never colored by the analyzer itself
+	ColorAlways = "always" // always colored
+	ColorNever  = "never"  // never colored
+)
+
+// Verbosity levels of messages written to Config.Output.
+const (
+	VerbosityQuiet   = 0 // nothing but diagnostics
+	VerbositySummary = 1 // a summary of the run (printed by the command)
+	VerbosityNotices = 2 // functions that could not be validated
+	VerbosityTrace   = 3 // candidate pairing of every function
+)
+
+// Config holds settings of the analyzer.
+// Every setting is exposed as an analyzer flag via RegisterFlags.
+type Config struct {
+	// Mode is the name of the preset the configuration was based on (see ModeStrict, ModeLenient).
+	Mode string
+
+	// IncludeMethods makes functions with receivers eligible to be converters.
+	IncludeMethods bool
+
+	// CheckGenerated enables analysis of generated files
+	// (those carrying the "// Code generated ... DO NOT EDIT." header).
+	CheckGenerated bool
+
+	// IncludeTests enables analysis of _test.go files.
+	IncludeTests bool
+
+	// Include restricts analysis to files whose path or package import path matches any of the regexps.
+	Include RegexpList
+
+	// Exclude skips files whose path or package import path matches any of the regexps.
+	Exclude RegexpList
+
+	// ExportedOnly restricts analysis to exported functions
+	// (and function literals assigned to exported variables).
+	ExportedOnly bool
+
+	// FuncPattern restricts analysis to functions whose name matches the regexp.
+	FuncPattern Regexp
+
+	// NameHeuristic requires names of input and output types to share a common substring.
+	// When disabled, any function converting a struct into a different struct is checked.
+	NameHeuristic bool
+
+	// MinFields excludes structs with fewer fields from the checks (0 means no limit).
+	MinFields int
+
+	// MaxFields excludes structs with more fields from the checks (0 means no limit).
+	MaxFields int
+
+	// Check defines which side of converters is checked: CheckBoth, CheckInput or CheckOutput.
+	Check string
+
+	// MinCoverage is the ratio of required fields a converter has to use to not be reported
+	// for missing fields. The default of 1 requires every field to be used.
+	MinCoverage float64
+
+	// IncludeDeprecated requires fields documented as deprecated to be mapped as well.
+	IncludeDeprecated bool
+
+	// ProtoAware enables protobuf defaults: oneof wrappers and XXX_ internals of generated
+	// messages are not required, and well-known types are not treated as models.
+	ProtoAware bool
+
+	// CheckOneofs requires converters of proto messages to handle every oneof variant.
+	// It has effect only together with ProtoAware.
+	CheckOneofs bool
+
+	// EmbeddedBaseTypes lists embedded base types (e.g. gorm.io/gorm.Model)
+	// whose promoted fields converters are not required to map.
+	EmbeddedBaseTypes StringList
+
+	// ORMProfiles lists ORM code generators (ent, sqlboiler) whose machinery fields
+	// (relations, query internals) converters are not required to map.
+	ORMProfiles StringList
+
+	// ReflectiveCopyFuncs lists reflection-based copy helpers (e.g. github.com/jinzhu/copier.Copy)
+	// that make per-field coverage of a converter impossible to determine.
+	ReflectiveCopyFuncs StringList
+
+	// ReflectiveCopy defines how converters calling ReflectiveCopyFuncs are treated:
+	// ReflectiveCopyUnknown reports them as having unknown coverage,
+	// ReflectiveCopyCovered considers all their fields covered.
+	ReflectiveCopy string
+
+	// DeepCopyFuncs lists deep-copy helpers (e.g. google.golang.org/protobuf/proto.Clone).
+	// Passing the input to one of them covers all fields shared by the input and output models.
+	DeepCopyFuncs StringList
+
+	// IgnoreReadsIn lists logging and metrics functions (e.g. log.Printf)
+	// whose arguments do not count as usages of input fields.
+	IgnoreReadsIn StringList
+
+	// IgnoreBlankReads does not count fields read only to be discarded (e.g. _ = in.Label) as used.
+	IgnoreBlankReads bool
+
+	// ReportJSONRoundTrip reports converters implemented via a JSON marshal/unmarshal round-trip,
+	// as they rely on struct tag compatibility. Otherwise, such converters are silently skipped.
+	ReportJSONRoundTrip bool
+
+	// BuilderBuildMethods lists names of methods finalizing a builder (e.g. Build).
+	BuilderBuildMethods StringList
+
+	// BuilderMethods lists templates of builder method names writing a field,
+	// where {Field} stands for the field name (e.g. "With{Field}").
+	BuilderMethods StringList
+
+	// ReportUnkeyed reports output composite literals initializing fields by their positions,
+	// as adding or reordering fields silently breaks such converters.
+	ReportUnkeyed bool
+
+	// ReportHardcoded reports output fields assigned only constants (e.g. Label: "const label")
+	// while the same-named input field exists and is not mapped into any output field.
+	ReportHardcoded bool
+
+	// ReportDuplicateWrites reports output fields written more than once on the same path,
+	// which often indicates a copy-paste bug where a different field was intended.
+	ReportDuplicateWrites bool
+
+	// ReportSwapped reports output fields assigned from a different input field (e.g. out.FirstName = in.LastName)
+	// while the same-named, type-compatible input field exists and is unused.
+	ReportSwapped bool
+
+	// Suggest adds likely input sources to reports of missing output fields
+	// (e.g. "result.Currency: did you mean sample.Currency?").
+	Suggest bool
+
+	// PerFieldDiagnostics reports every missing field separately, positioned at the input parameter
+	// or at the output literal lacking the field, instead of one summary at the function name.
+	PerFieldDiagnostics bool
+
+	// Pretty renders messages of diagnostics reported at converters along with their source line.
+	// Machine-readable outputs (e.g. SARIF) disable it.
+	Pretty bool
+
+	// Color is the color mode of pretty-printed messages: ColorAuto, ColorAlways or ColorNever.
+	// Drivers knowing where messages are printed resolve ColorAuto into one of the others.
+	Color string
+
+	// FieldMappings lists fields intentionally renamed between input and output models.
+	FieldMappings FieldMappings
+
+	// Precise determines fields used by converters from def-use chains of the SSA form of the package
+	// rather than from selectors in the source: values are followed through local variables,
+	// conditionals and helpers of the package, at the cost of building the SSA form.
+	Precise bool
+
+	// CheckRegistries reports converters that have the signature of a converter registry's values
+	// (e.g. map[string]func(model.Event) db.Event) but are not registered in it.
+	CheckRegistries bool
+
+	// CheckRoundTrips reports fields lost on round trips of converter pairs of the package
+	// (e.g. ToDB(model.User) db.User and FromDB(db.User) model.User): fields mapped by a converter
+	// that its reverse converter does not map back.
+	CheckRoundTrips bool
+
+	// CheckReverse reports converters of the package having no converter in the other direction
+	// (e.g. ToDB(model.User) db.User without FromDB(db.User) model.User).
+	CheckReverse bool
+
+	// CheckNaming reports converters whose names don't match any of the Naming templates
+	// or which are declared outside of ConverterPackages.
+	CheckNaming bool
+
+	// Naming lists templates of converter names, where {In} and {Out} stand for names of the input
+	// and output models and {InPkg} and {OutPkg} for names of their packages starting with an upper case
+	// letter (e.g. "To{Out}", "{Out}From{In}" or "{InPkg}To{OutPkg}").
+	Naming StringList
+
+	// ConverterPackages matches import paths of packages converters have to be declared in,
+	// when the naming convention is checked. Converters may be declared anywhere if it's not set.
+	ConverterPackages Regexp
+
+	// CheckDuplicates reports converters of the package converting the same types as another one
+	// (e.g. two functions converting model.User into db.User), along with the fields only one of them maps.
+	CheckDuplicates bool
+
+	// AllowDuplicates lists names of converters intentionally converting the same types as others
+	// (e.g. ToUserRowRedacted), when duplicates are checked.
+	AllowDuplicates StringList
+
+	// CheckLayers reports converters between models of different Layers that no LayerRules allow.
+	CheckLayers bool
+
+	// Layers groups packages into layers of the architecture (e.g. domain and db), for LayerRules.
+	Layers Layers
+
+	// LayerRules lists allowed conversions between models of Layers, optionally limited to converters
+	// declared in some packages (e.g. domain->db only inside internal/repo).
+	LayerRules LayerRules
+
+	// CheckNilResults reports dereferences of pointer named results of converters before any assignment
+	// of the result (e.g. result.ID = ... before result = &db.User{}), which panic.
+	CheckNilResults bool
+
+	// CheckErrors reports converters that can fail (calls returning errors, type assertions) but return
+	// no error, and converters dereferencing their pointer input without checking it for nil.
+	// Both are reported with SeverityInfo unless Severities tells otherwise.
+	CheckErrors bool
+
+	// CheckPurity reports statements of converters mutating their input (e.g. u.Name = ...
+	// or u.Normalize() of a method with a pointer receiver), for side-effect free converters.
+	CheckPurity bool
+
+	// MaxIssuesPerFunc limits the number of missing fields reported for a converter (0 means no limit).
+	// Missing output fields are reported first.
+	MaxIssuesPerFunc int
+
+	// MaxIssues limits the number of findings reported for a package (0 means no limit).
+	// Missing output fields are reported first, missing input fields last.
+	MaxIssues int
+
+	// Disable lists codes of findings not to report (e.g. SF002), see CodeMissingOutput and others.
+	Disable StringList
+
+	// Severities maps codes of findings to their severities, e.g. to fail CI builds only
+	// on missing output fields. Findings are warnings by default.
+	Severities Severities
+
+	// Baseline is the JSON file of known findings (see Baseline) not to report again.
+	Baseline string
+
+	// Changed limits findings to functions with changed lines and to missing fields declared
+	// on changed lines, e.g. to check the changes of a pull request only. Nil means no limit.
+	Changed ChangedLines
+
+	// Explain is the name of a function whose classification as a converter (or not)
+	// is explained step by step to the Output.
+	Explain string
+
+	// Mappings records mapping tables of converters (see ExtractMapping) in results of the analyzer,
+	// e.g. to document them. It's off by default since extracting them takes time.
+	Mappings bool
+
+	// Verbosity controls which messages are written to Output (see VerbosityQuiet and others).
+	Verbosity int
+
+	// Output receives messages of the analyzer other than diagnostics
+	// (e.g. functions that could not be validated). Nil discards them, so drivers
+	// like go vet, gopls or nogo get nothing but diagnostics.
+	Output io.Writer
+
+	// baseline holds findings of the Baseline file while analyzing a package.
+	baseline *Baseline
+	// ssa holds the SSA form of the package while analyzing it in the Precise mode.
+	ssa *ssaFuncs
+}
+
+// DefaultConfig returns the configuration used when no flags are given.
+func DefaultConfig() *Config {
+	return &Config{
+		Mode:          ModeDefault,
+		NameHeuristic: true,
+		Check:         CheckBoth,
+		MinCoverage:   1,
+		ProtoAware:    true,
+		CheckOneofs:   true,
+		EmbeddedBaseTypes: StringList{
+			"gorm.io/gorm.Model",
+		},
+		ORMProfiles: StringList{"ent", "sqlboiler"},
+		ReflectiveCopyFuncs: StringList{
+			"github.com/jinzhu/copier.Copy",
+			"github.com/jinzhu/copier.CopyWithOption",
+			"github.com/mitchellh/mapstructure.Decode",
+			"github.com/go-viper/mapstructure/v2.Decode",
+		},
+		ReflectiveCopy: ReflectiveCopyUnknown,
+		DeepCopyFuncs: StringList{
+			"google.golang.org/protobuf/proto.Clone",
+			"google.golang.org/protobuf/proto.Merge",
+			"github.com/golang/protobuf/proto.Clone",
+			"github.com/golang/protobuf/proto.Merge",
+		},
+		IgnoreReadsIn: StringList{
+			"log.Print",
+			"log.Printf",
+			"log.Println",
+			"log.Logger.Print",
+			"log.Logger.Printf",
+			"log.Logger.Println",
+			"log/slog.Debug",
+			"log/slog.Info",
+			"log/slog.Warn",
+			"log/slog.Error",
+			"log/slog.Logger.Debug",
+			"log/slog.Logger.Info",
+			"log/slog.Logger.Warn",
+			"log/slog.Logger.Error",
+		},
+		Suggest:             true,
+		Pretty:              true,
+		Color:               ColorAuto,
+		Verbosity:           VerbositySummary,
+		BuilderBuildMethods: StringList{"Build"},
+		BuilderMethods:      StringList{"{Field}", "Set{Field}", "With{Field}"},
+		Naming:              StringList{"To{Out}", "{Out}From{In}"},
+	}
+}
+
+// Validate checks the configuration for invalid values.
+func (c *Config) Validate() error {
+	for _, name := range c.ORMProfiles {
+		if _, ok := ormProfiles[name]; !ok {
+			return fmt.Errorf("unknown ORM profile %q", name)
+		}
+	}
+	if c.ReflectiveCopy != ReflectiveCopyUnknown && c.ReflectiveCopy != ReflectiveCopyCovered {
+		return fmt.Errorf("invalid reflective copy mode %q: expected %q or %q",
+			c.ReflectiveCopy, ReflectiveCopyUnknown, ReflectiveCopyCovered)
+	}
+	switch c.Check {
+	case CheckBoth, CheckInput, CheckOutput:
+	default:
+		return fmt.Errorf("invalid check direction %q: expected %q, %q or %q",
+			c.Check, CheckBoth, CheckInput, CheckOutput)
+	}
+	for _, code := range c.Disable {
+		if _, ok := CodeDocs[code]; !ok {
+			return fmt.Errorf("unknown code %q of findings to disable", code)
+		}
+	}
+	for code, severity := range c.Severities {
+		if err := checkSeverity(code, severity); err != nil {
+			return err
+		}
+	}
+	switch c.Color {
+	case ColorAuto, ColorAlways, ColorNever:
+	default:
+		return fmt.Errorf("invalid color mode %q: expected %q, %q or %q",
+			c.Color, ColorAuto, ColorAlways, ColorNever)
+	}
+	for _, rule := range c.LayerRules {
+		if !c.Layers.has(rule.From) || !c.Layers.has(rule.To) {
+			return fmt.Errorf("invalid layer rule %s: unknown layer", rule)
+		}
+	}
+	if c.MaxIssuesPerFunc < 0 || c.MaxIssues < 0 {
+		return fmt.Errorf("invalid issue limits: per function %d, per package %d must not be negative",
+			c.MaxIssuesPerFunc, c.MaxIssues)
+	}
+	if c.Verbosity < 0 {
+		return fmt.Errorf("invalid verbosity %d: must not be negative", c.Verbosity)
+	}
+	if c.MinCoverage < 0 || c.MinCoverage > 1 {
+		return fmt.Errorf("invalid minimal coverage %v: expected a ratio between 0 and 1", c.MinCoverage)
+	}
+	if c.MinFields < 0 || c.MaxFields < 0 {
+		return fmt.Errorf("invalid struct size thresholds: min %d, max %d must not be negative",
+			c.MinFields, c.MaxFields)
+	}
+	if c.MaxFields > 0 && c.MaxFields < c.MinFields {
+		return fmt.Errorf("invalid struct size thresholds: max %d is less than min %d",
+			c.MaxFields, c.MinFields)
+	}
+	return nil
+}
+
+// RegisterFlags binds configuration fields to the given flag set.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var(&modeValue{cfg: c, fs: fs}, "mode",
+		"preset of options: strict, default or lenient; options given explicitly take precedence")
+	fs.BoolVar(&c.IncludeMethods, "include-methods", c.IncludeMethods,
+		"analyze functions with receivers as well")
+	fs.BoolVar(&c.CheckGenerated, "check-generated", c.CheckGenerated,
+		"analyze generated files as well")
+	fs.BoolVar(&c.IncludeTests, "include-tests", c.IncludeTests,
+		"analyze _test.go files as well")
+	fs.Var(&c.Include, "include",
+		"regexp (repeatable): analyze only files whose path or package import path matches any of them")
+	fs.Var(&c.Exclude, "exclude",
+		"regexp (repeatable): skip files whose path or package import path matches any of them")
+	fs.BoolVar(&c.ExportedOnly, "exported-only", c.ExportedOnly,
+		"analyze exported functions only")
+	fs.Var(&c.FuncPattern, "func-pattern",
+		"regexp restricting analysis to matching function names, e.g. ^(To|From|Convert)")
+	fs.BoolVar(&c.NameHeuristic, "name-heuristic", c.NameHeuristic,
+		"require input and output type names to be similar; disable to check any struct-to-struct function")
+	fs.IntVar(&c.MinFields, "min-fields", c.MinFields,
+		"skip structs having fewer fields than this (0 means no limit)")
+	fs.IntVar(&c.MaxFields, "max-fields", c.MaxFields,
+		"skip structs having more fields than this (0 means no limit)")
+	fs.StringVar(&c.Check, "check", c.Check,
+		"fields to check: both, input (unread input fields) or output (unwritten output fields)")
+	fs.Float64Var(&c.MinCoverage, "min-coverage", c.MinCoverage,
+		"report converters using less than this ratio of required fields, e.g. 0.9 (1 requires all fields)")
+	fs.BoolVar(&c.IncludeDeprecated, "include-deprecated", c.IncludeDeprecated,
+		"require fields marked as Deprecated to be mapped as well")
+	fs.BoolVar(&c.ProtoAware, "proto-aware", c.ProtoAware,
+		"apply protobuf defaults to protoc-generated messages (skip oneof wrappers and XXX_ fields)")
+	fs.BoolVar(&c.CheckOneofs, "check-oneofs", c.CheckOneofs,
+		"require converters of protobuf messages to handle every oneof variant")
+	fs.Var(&c.EmbeddedBaseTypes, "skip-embedded",
+		"comma-separated embedded base types whose fields are not required, e.g. gorm.io/gorm.Model")
+	fs.Var(&c.ORMProfiles, "orm-profiles",
+		"comma-separated ORM generators (ent, sqlboiler) whose machinery fields are not required")
+	fs.Var(&c.ReflectiveCopyFuncs, "reflective-copy-funcs",
+		"comma-separated reflection-based copy functions, e.g. github.com/jinzhu/copier.Copy")
+	fs.StringVar(&c.ReflectiveCopy, "reflective-copy", c.ReflectiveCopy,
+		"treatment of converters using reflective copy functions: unknown (report) or covered")
+	fs.Var(&c.DeepCopyFuncs, "deep-copy-funcs",
+		"comma-separated deep-copy functions covering fields shared by input and output, e.g. google.golang.org/protobuf/proto.Clone")
+	fs.Var(&c.IgnoreReadsIn, "ignore-reads-in",
+		"comma-separated logging/metrics functions whose arguments are not counted as input field usages, e.g. log.Printf")
+	fs.BoolVar(&c.IgnoreBlankReads, "ignore-blank-reads", c.IgnoreBlankReads,
+		"do not count fields read only to be discarded (e.g. _ = in.Label) as used")
+	fs.BoolVar(&c.ReportJSONRoundTrip, "report-json-roundtrip", c.ReportJSONRoundTrip,
+		"report converters implemented via JSON marshal/unmarshal instead of skipping them")
+	fs.Var(&c.BuilderBuildMethods, "builder-build-methods",
+		"comma-separated names of methods finalizing a builder, e.g. Build")
+	fs.Var(&c.BuilderMethods, "builder-methods",
+		"comma-separated templates of builder methods writing a field, e.g. {Field},With{Field}")
+	fs.BoolVar(&c.ReportUnkeyed, "report-unkeyed", c.ReportUnkeyed,
+		"report unkeyed (positional) composite literals of output models")
+	fs.BoolVar(&c.ReportHardcoded, "report-hardcoded", c.ReportHardcoded,
+		"report output fields assigned constants instead of the same-named input fields")
+	fs.BoolVar(&c.ReportDuplicateWrites, "report-duplicate-writes", c.ReportDuplicateWrites,
+		"report output fields written more than once on the same path")
+	fs.BoolVar(&c.ReportSwapped, "report-swapped", c.ReportSwapped,
+		"report output fields assigned from a different input field while the same-named one is unused")
+	fs.BoolVar(&c.Suggest, "suggest", c.Suggest,
+		"suggest likely input sources of missing output fields")
+	fs.BoolVar(&c.PerFieldDiagnostics, "per-field", c.PerFieldDiagnostics,
+		"report every missing field separately where it should be handled")
+	fs.BoolVar(&c.Pretty, "pretty", c.Pretty,
+		"render messages along with the source line of the converter")
+	fs.IntVar(&c.MaxIssuesPerFunc, "max-issues-per-func", c.MaxIssuesPerFunc,
+		"report at most this many missing fields of a converter, output fields first (0 means no limit)")
+	fs.IntVar(&c.MaxIssues, "max-issues", c.MaxIssues,
+		"report at most this many findings of a package, missing output fields first (0 means no limit)")
+	fs.Var(&c.Disable, "disable",
+		"comma-separated codes of findings not to report, e.g. SF002 for missing input fields")
+	fs.Var(&c.Severities, "severity",
+		"comma-separated severities (error, warning or info) of codes of findings, e.g. SF001=error,SF002=info")
+	fs.StringVar(&c.Baseline, "baseline", c.Baseline,
+		"JSON file of known findings not to report again (see -update-baseline of the command)")
+	fs.StringVar(&c.Explain, "explain", c.Explain,
+		"explain why the function with the given name is (not) considered a converter")
+	fs.IntVar(&c.Verbosity, "verbosity", c.Verbosity,
+		"verbosity: 0 for diagnostics only, 1 adds the summary, 2 functions that could not be validated, 3 candidate pairing traces")
+	fs.StringVar(&c.Color, "color", c.Color,
+		"color pretty-printed messages: auto (on terminals unless NO_COLOR is set), always or never")
+	fs.Var(&c.FieldMappings, "map",
+		"comma-separated field mappings between renamed fields, e.g. model.Post.Body=db.Post.Content")
+	fs.BoolVar(&c.Precise, "precise", c.Precise,
+		"determine used fields from SSA def-use chains, through local variables, conditionals and helpers (slower)")
+	fs.BoolVar(&c.CheckRegistries, "check-registries", c.CheckRegistries,
+		"report converters missing from registries of functions with the same signature")
+	fs.BoolVar(&c.CheckRoundTrips, "check-round-trips", c.CheckRoundTrips,
+		"report fields mapped by converters that their reverse converters of the package do not map back")
+	fs.BoolVar(&c.CheckReverse, "check-reverse", c.CheckReverse,
+		"report converters having no converter in the other direction in the package")
+	fs.BoolVar(&c.CheckNaming, "check-naming", c.CheckNaming,
+		"report converters not following the naming convention of -naming and -converter-packages")
+	fs.Var(&c.Naming, "naming",
+		"comma-separated templates of converter names, e.g. To{Out},{Out}From{In},{InPkg}To{OutPkg}")
+	fs.Var(&c.ConverterPackages, "converter-packages",
+		"regular expression of import paths of packages converters have to be declared in (with -check-naming)")
+	fs.BoolVar(&c.CheckDuplicates, "check-duplicates", c.CheckDuplicates,
+		"report converters converting the same types as another converter of the package")
+	fs.Var(&c.AllowDuplicates, "allow-duplicates",
+		"comma-separated names of converters intentionally converting the same types as others (with -check-duplicates)")
+	fs.BoolVar(&c.CheckNilResults, "check-nil-results", c.CheckNilResults,
+		"report dereferences of pointer named results of converters before they're assigned")
+	fs.BoolVar(&c.CheckErrors, "check-errors", c.CheckErrors,
+		"report converters that can fail but return no error and ones not checking their pointer input for nil")
+	fs.BoolVar(&c.CheckPurity, "check-purity", c.CheckPurity,
+		"report converters mutating their input, e.g. writing its fields or calling its methods with pointer receivers")
+	fs.BoolVar(&c.CheckLayers, "check-layers", c.CheckLayers,
+		"report converters between models of -layers that no -layer-rules allow")
+	fs.Var(&c.Layers, "layers",
+		"comma-separated layers of packages as NAME=REGEXP of import paths, e.g. domain=/domain$,db=/internal/db/")
+	fs.Var(&c.LayerRules, "layer-rules",
+		"comma-separated allowed conversions between -layers as FROM->TO, optionally @REGEXP of import paths of "+
+			"packages declaring the converters, e.g. domain->db@/internal/repo/")
+	wrapPresetFlags(fs)
+}
+
+// StringList is a comma-separated list of strings usable as a flag.Value.
+// Setting it replaces the previous (e.g. default) value.
+type StringList []string
+
+func (l *StringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *StringList) Set(value string) error {
+	*l = splitList(value)
+	return nil
+}
+
+// logf writes a message to the Output, if any and the verbosity is at least the given level.
+func (c *Config) logf(level int, format string, args ...any) {
+	if c.Output != nil && c.Verbosity >= level {
+		fmt.Fprintf(c.Output, format+"\n", args...)
+	}
+}
+
+// colored reports whether pretty-printed messages are colored.
+// The analyzer doesn't know where they're printed: ColorAuto unresolved by the driver means plain.
+func (c *Config) colored() bool {
+	return c.Color == ColorAlways
+}
+
+// Severity returns the severity of findings with the code.
+func (c *Config) Severity(code string) string {
+	if severity, ok := c.Severities[code]; ok {
+		return severity
+	}
+	if severity, ok := defaultSeverities[code]; ok {
+		return severity
+	}
+	return SeverityWarning
+}
+
+// mostSevere returns the first of the codes with the highest severity.
+func (c *Config) mostSevere(codes []string) string {
+	rank := map[string]int{SeverityInfo: 0, SeverityWarning: 1, SeverityError: 2}
+	most := codes[0]
+	for _, code := range codes[1:] {
+		if rank[c.Severity(code)] > rank[c.Severity(most)] {
+			most = code
+		}
+	}
+	return most
+}
+
+// enabled reports whether findings with the code are reported.
+func (c *Config) enabled(code string) bool {
+	return !slices.Contains(c.Disable, code)
+}
+
+// checksInput reports whether unread input fields are reported.
+func (c *Config) checksInput() bool {
+	return c.Check != CheckOutput && c.enabled(CodeMissingInput)
+}
+
+// checksOutput reports whether unwritten output fields are reported.
+func (c *Config) checksOutput() bool {
+	return c.Check != CheckInput && c.enabled(CodeMissingOutput)
+}
+
+// sizeAllowed applies MinFields and MaxFields thresholds to the struct.
+func (c *Config) sizeAllowed(st *types.Struct) bool {
+	n := st.NumFields()
+	if n < c.MinFields {
+		return false
+	}
+	return c.MaxFields == 0 || n <= c.MaxFields
+}
+
+// pathAllowed applies Include and Exclude filters to the package import path and the file path.
+func (c *Config) pathAllowed(pkgPath, filename string) bool {
+	if c.Exclude.MatchAny(pkgPath, filename) {
+		return false
+	}
+	return len(c.Include) == 0 || c.Include.MatchAny(pkgPath, filename)
+}
+
+// Regexp is a regular expression usable as a flag.Value.
+// The zero value matches everything.
+type Regexp struct {
+	*regexp.Regexp
+}
+
+func (r *Regexp) String() string {
+	if r == nil || r.Regexp == nil {
+		return ""
+	}
+	return r.Regexp.String()
+}
+
+func (r *Regexp) Set(value string) error {
+	if value == "" {
+		r.Regexp = nil
+		return nil
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return fmt.Errorf("invalid regexp %q: %w", value, err)
+	}
+	r.Regexp = re
+	return nil
+}
+
+// MatchString reports whether s matches the regexp. An unset regexp matches everything.
+func (r Regexp) MatchString(s string) bool {
+	return r.Regexp == nil || r.Regexp.MatchString(s)
+}
+
+// RegexpList is a list of regular expressions usable as a repeatable flag.Value:
+// setting it adds a regular expression, as commas may be part of them (e.g. \w{2,5}).
+// Setting it to an empty string clears the list.
+type RegexpList []*regexp.Regexp
+
+func (l *RegexpList) String() string {
+	if l == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*l))
+	for _, re := range *l {
+		parts = append(parts, re.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l *RegexpList) Set(value string) error {
+	if value = strings.TrimSpace(value); value == "" {
+		*l = nil
+		return nil
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return fmt.Errorf("invalid regexp %q: %w", value, err)
+	}
+	*l = append(*l, re)
+	return nil
+}
+
+// repeatable marks RegexpList as a flag set once per element of lists of settings (see ApplySettings).
+func (*RegexpList) repeatable() {}
+
+// MatchAny reports whether any of the regexps matches any of the values.
+func (l RegexpList) MatchAny(values ...string) bool {
+	for _, re := range l {
+		for _, v := range values {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FieldMapping declares that the input field InField of InType is stored
+// in the output field OutField of OutType.
+// Types are referenced by name, optionally qualified by the package name or path.
+type FieldMapping struct {
+	InType   string
+	InField  string
+	OutType  string
+	OutField string
+}
+
+func (m FieldMapping) String() string {
+	return m.InType + "." + m.InField + "=" + m.OutType + "." + m.OutField
+}
+
+// Matches reports whether the mapping applies to the given pair of types.
+func (m FieldMapping) Matches(in, out *types.TypeName) bool {
+	return typeMatches(m.InType, in) && typeMatches(m.OutType, out)
+}
+
+// FieldMappings is a list of field mappings usable as a flag.Value.
+type FieldMappings []FieldMapping
+
+func (fm *FieldMappings) String() string {
+	if fm == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*fm))
+	for _, m := range *fm {
+		parts = append(parts, m.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses comma-separated mappings and appends them to the list.
+func (fm *FieldMappings) Set(value string) error {
+	for _, entry := range splitList(value) {
+		in, out, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid field mapping %q: expected In.Field=Out.Field", entry)
+		}
+		inType, inField, okIn := splitTypeField(in)
+		outType, outField, okOut := splitTypeField(out)
+		if !okIn || !okOut {
+			return fmt.Errorf("invalid field mapping %q: expected In.Field=Out.Field", entry)
+		}
+		*fm = append(*fm, FieldMapping{InType: inType, InField: inField, OutType: outType, OutField: outField})
+	}
+	return nil
+}
+
+// For returns mappings applicable to the given pair of types.
+func (fm FieldMappings) For(in, out *types.TypeName) []FieldMapping {
+	var res []FieldMapping
+	for _, m := range fm {
+		if m.Matches(in, out) {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// splitTypeField splits "pkg.Type.Field" into "pkg.Type" and "Field".
+func splitTypeField(s string) (typeName, field string, ok bool) {
+	s = strings.TrimSpace(s)
+	idx := strings.LastIndex(s, ".")
+	if idx <= 0 || idx == len(s)-1 {
+		return "", "", false
+	}
+	return s[:idx], s[idx+1:], true
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(value string) []string {
+	var res []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// typeMatches checks whether the type reference matches the named type.
+// The reference can be a bare type name (Sample), qualified by the package name
+// (dbmodel.Sample) or by the full package path (example.com/app/dbmodel.Sample).
+func typeMatches(ref string, obj *types.TypeName) bool {
+	if obj == nil {
+		return false
+	}
+	if ref == obj.Name() {
+		return true
+	}
+	if obj.Pkg() == nil {
+		return false
+	}
+	return ref == obj.Pkg().Name()+"."+obj.Name() || ref == obj.Pkg().Path()+"."+obj.Name()
+}
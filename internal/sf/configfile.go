@@ -0,0 +1,271 @@
+package sf
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/tools/go/analysis"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the names of configuration files looked up in the module root and in package directories.
+var ConfigFileNames = []string{".stickyfields.yaml", ".stickyfields.yml", ".stickyfields.toml"}
+
+// FindConfigFile returns the configuration file in the root of the module containing dir,
+// or an empty string if there is none.
+func FindConfigFile(dir string) (string, error) {
+	root := moduleRoot(dir)
+	if root == "" {
+		return "", nil
+	}
+	return findConfigFileIn(root)
+}
+
+// moduleRoot returns the directory of the go.mod file of the module containing dir,
+// or an empty string if dir is not within a module.
+func moduleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// resolvePath resolves a relative path of an option against the working directory or,
+// if there is no such file, against the root of the package's module: go vet runs vet tools
+// in the directory of each package.
+func resolvePath(pass *analysis.Pass, name string) string {
+	if filepath.IsAbs(name) || len(pass.Files) == 0 {
+		return name
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	dir := filepath.Dir(pass.Fset.Position(pass.Files[0].Pos()).Filename)
+	if root := moduleRoot(dir); root != "" {
+		return filepath.Join(root, name)
+	}
+	return name
+}
+
+// findConfigFileIn returns the configuration file of the directory, or an empty string if there is none.
+func findConfigFileIn(dir string) (string, error) {
+	for _, name := range ConfigFileNames {
+		filename := filepath.Join(dir, name)
+		if _, err := os.Stat(filename); err == nil {
+			return filename, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// nestedConfigFiles returns configuration files of dir and its parents below the root of the module
+// containing dir, the outermost first. The configuration file of the module root is left to drivers.
+func nestedConfigFiles(dir string) ([]string, error) {
+	var files []string
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return files, nil
+		}
+		filename, err := findConfigFileIn(dir)
+		if err != nil {
+			return nil, err
+		}
+		if filename != "" {
+			files = slices.Insert(files, 0, filename)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// Not within a module.
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// forPackage returns the configuration of the package: options of configuration files
+// of the package directory and its parents below the module root override the given ones,
+// nearer files taking precedence. This way a legacy subtree can relax rules of the module.
+// Options of //sf:config directives in the package doc comment come last.
+func (c *Config) forPackage(pass *analysis.Pass) (*Config, error) {
+	if len(pass.Files) == 0 {
+		return c, nil
+	}
+	dir := filepath.Dir(pass.Fset.Position(pass.Files[0].Pos()).Filename)
+	files, err := nestedConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := c
+	for _, filename := range files {
+		settings, err := ReadConfigFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if cfg, err = cfg.override(settings); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+
+	if settings := packageDirectives(pass); len(settings) > 0 {
+		if cfg, err = cfg.override(settings); err != nil {
+			return nil, fmt.Errorf("%s: %w", configDirective, err)
+		}
+	}
+	return cfg, nil
+}
+
+// packageDirectives returns settings of //sf:config directives in package doc comments
+// of the package's files, e.g. //sf:config include-methods min-coverage=0.9.
+// Options without a value are set to true.
+func packageDirectives(pass *analysis.Pass) map[string]any {
+	settings := make(map[string]any)
+	for _, file := range pass.Files {
+		args, _ := directiveArgs(file.Doc, configDirective)
+		for _, arg := range args {
+			name, value, found := strings.Cut(arg, "=")
+			if !found {
+				settings[name] = true
+				continue
+			}
+			settings[name] = value
+		}
+	}
+	return settings
+}
+
+// override returns a copy of the configuration with the settings (see ApplySettings) applied.
+func (c *Config) override(settings map[string]any) (*Config, error) {
+	cfg := *c
+	// Flags of mappings and severities add to the current values rather than replacing them.
+	cfg.FieldMappings = slices.Clip(c.FieldMappings)
+	cfg.Severities = maps.Clone(c.Severities)
+
+	flags := flag.NewFlagSet(analyzerName, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	cfg.RegisterFlags(flags)
+	if err := ApplySettings(flags, settings); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ReadConfigFile reads settings from the YAML or TOML file (depending on its extension).
+// Settings are keyed by flag names, e.g.
+//
+//	mode: strict
+//	disable: [SF004]
+//	severity:
+//	  SF001: error
+//	map:
+//	  model.Post.Body: dbmodel.Post.Content
+func ReadConfigFile(filename string) (map[string]any, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	settings := make(map[string]any)
+	switch ext := filepath.Ext(filename); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &settings)
+	case ".toml":
+		err = toml.Unmarshal(data, &settings)
+	default:
+		return nil, fmt.Errorf("unknown config file format %q: expected .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", filename, err)
+	}
+	return settings, nil
+}
+
+// ApplySettings sets flags of the set from settings keyed by flag names. Flags set already
+// (e.g. given on the command line) take precedence. Lists are joined by commas
+// and maps are turned into comma-separated key=value entries, as flags of lists and maps expect.
+// Repeatable flags (e.g. of regexps, which may contain commas) are set once per element of lists instead.
+func ApplySettings(fs *flag.FlagSet, settings map[string]any) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	// The mode is a preset of other options, so it's applied first for the rest to override it.
+	names := slices.SortedFunc(maps.Keys(settings), func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "mode":
+			return -1
+		case b == "mode":
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown option %q", name)
+		}
+		if explicit[name] {
+			continue
+		}
+		values := []string{settingValue(settings[name])}
+		if list, ok := settings[name].([]any); ok && isRepeatable(fs.Lookup(name).Value) {
+			// The first value replaces the default.
+			values = []string{""}
+			for _, elem := range list {
+				values = append(values, settingValue(elem))
+			}
+		}
+		for _, value := range values {
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("option %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isRepeatable reports whether every setting of the flag value adds an element to it.
+func isRepeatable(v flag.Value) bool {
+	_, ok := v.(interface{ repeatable() })
+	return ok
+}
+
+// settingValue formats the setting as a flag value.
+func settingValue(v any) string {
+	switch v := v.(type) {
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			values = append(values, settingValue(elem))
+		}
+		return strings.Join(values, ",")
+	case map[string]any:
+		entries := make([]string, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			entries = append(entries, key+"="+settingValue(v[key]))
+		}
+		return strings.Join(entries, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,88 @@
+package sf
+
+import (
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DeadField is a field of a model that none of the converters of the model maps.
+type DeadField struct {
+	// Model is the fully qualified name of the model, e.g. example.com/db.User.
+	Model string
+	// Field is the name of the field.
+	Field string
+	// Pos is the position of the field's declaration.
+	Pos token.Pos
+}
+
+// deadFieldsModel collects fields of a model required and mapped by its converters.
+type deadFieldsModel struct {
+	obj      *types.TypeName
+	required map[string]bool
+	mapped   map[string]bool
+	// opaque is set when a converter of the model covers its fields invisibly (e.g. via a reflective copy).
+	opaque bool
+}
+
+// DeadFields returns fields of models that converters of the results are required to map (as their input
+// or output) but none of them maps: a strong hint of dead schema columns or forgotten plumbing.
+// Since converters of a model live in packages importing it rather than the other way around,
+// results of all packages of the module are aggregated, e.g. by the command analyzing them.
+//
+// Fields no converter is required to map (e.g. ignored ones) are left out, and so are models
+// of converters covering all fields or of unknown coverage (e.g. reflective copies).
+// Results of packages analyzed more than once (e.g. foo and foo.test) may be passed all the same.
+func DeadFields(results []*Result) []DeadField {
+	models := make(map[string]*deadFieldsModel)
+	add := func(obj *types.TypeName, required, missing []string, opaque bool) {
+		if obj == nil || obj.Pkg() == nil {
+			return
+		}
+		key := obj.Pkg().Path() + "." + obj.Name()
+		m, ok := models[key]
+		if !ok {
+			m = &deadFieldsModel{obj: obj, required: make(map[string]bool), mapped: make(map[string]bool)}
+			models[key] = m
+		}
+		m.opaque = m.opaque || opaque
+		for _, field := range required {
+			name := unqualify(field)
+			m.required[name] = true
+			if !slices.Contains(missing, field) {
+				m.mapped[name] = true
+			}
+		}
+	}
+	for _, result := range results {
+		for _, c := range result.Converters {
+			r := c.ConverterValidationResult
+			opaque := r.UnknownCoverage != "" || len(r.InputFields) == 0 && len(r.OutputFields) == 0
+			add(r.InputModel, r.InputFields, r.MissingInputFields, opaque)
+			add(r.OutputModel, r.OutputFields, r.MissingOutputFields, opaque)
+		}
+	}
+
+	var dead []DeadField
+	for _, key := range slices.Sorted(maps.Keys(models)) {
+		m := models[key]
+		st, ok := m.obj.Type().Underlying().(*types.Struct)
+		if m.opaque || !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			if m.required[field.Name()] && !m.mapped[field.Name()] {
+				dead = append(dead, DeadField{Model: key, Field: field.Name(), Pos: field.Pos()})
+			}
+		}
+	}
+	return dead
+}
+
+// unqualify strips the variable name from the field name qualified by qualify.
+func unqualify(field string) string {
+	return field[strings.LastIndex(field, ".")+1:]
+}
@@ -0,0 +1,121 @@
+package sf
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// LineRange is an inclusive range of lines.
+type LineRange struct {
+	Start, End int
+}
+
+// ChangedLines holds the ranges of added or modified lines keyed by the absolute file name.
+type ChangedLines map[string][]LineRange
+
+// ParseDiff reads the changed lines from a unified diff (e.g. the output of git diff -U0).
+// File names of the diff are resolved relative to dir, without the b/ prefix of git if they have it.
+func ParseDiff(r io.Reader, dir string) (ChangedLines, error) {
+	changed := make(ChangedLines)
+	var filename string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			name := strings.TrimPrefix(line, "+++ ")
+			if name == "/dev/null" {
+				// The file is deleted.
+				filename = ""
+				continue
+			}
+			name = strings.TrimPrefix(name, "b/")
+			filename = filepath.Join(dir, filepath.FromSlash(name))
+		case strings.HasPrefix(line, "@@ ") && filename != "":
+			// @@ -start[,count] +start[,count] @@
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				return nil, fmt.Errorf("malformed hunk header %q", line)
+			}
+			start, count, found := strings.Cut(fields[2][1:], ",")
+			n := 1
+			if found {
+				var err error
+				if n, err = strconv.Atoi(count); err != nil {
+					return nil, fmt.Errorf("malformed hunk header %q: %w", line, err)
+				}
+			}
+			first, err := strconv.Atoi(start)
+			if err != nil {
+				return nil, fmt.Errorf("malformed hunk header %q: %w", line, err)
+			}
+			// Hunks of removed lines only add nothing.
+			if n > 0 {
+				changed[filename] = append(changed[filename], LineRange{first, first + n - 1})
+			}
+		}
+	}
+	return changed, scanner.Err()
+}
+
+// overlaps reports whether any line between start and end (of the same file) is changed.
+func (c ChangedLines) overlaps(start, end token.Position) bool {
+	for _, r := range c[start.Filename] {
+		if r.Start <= end.Line && start.Line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// touched reports whether the function (including its doc comment) is changed.
+// Every function is considered touched unless changed lines are configured.
+func (c *Config) touched(pass *analysis.Pass, fn *Func) bool {
+	if c.Changed == nil {
+		return true
+	}
+	start, end := fn.Type.Pos(), fn.Type.End()
+	if fn.Body != nil {
+		end = fn.Body.End()
+	}
+	if fn.Decl != nil {
+		start = fn.Decl.Pos()
+		if fn.Decl.Doc != nil {
+			start = fn.Decl.Doc.Pos()
+		}
+	}
+	return c.Changed.overlaps(pass.Fset.Position(start), pass.Fset.Position(end))
+}
+
+// unchangedFields returns the fields of the struct not to report as missing from the function:
+// when only some lines are changed and the function isn't among them,
+// just newly added (or modified) fields are reported.
+func (c *Config) unchangedFields(pass *analysis.Pass, fn *Func, st *types.Struct) *UsageLookup {
+	unchanged := NewUsageLookup()
+	if st == nil || c.touched(pass, fn) {
+		return unchanged
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		pos := pass.Fset.Position(field.Pos())
+		if !c.Changed.overlaps(pos, pos) {
+			unchanged.Add(field.Name())
+		}
+	}
+	return unchanged
+}
+
+// reports reports whether findings of the function with the code are reported:
+// the code is enabled, not recorded in the baseline and the function is changed (if asked so).
+func (c *Config) reports(pass *analysis.Pass, fn *Func, code string) bool {
+	return c.enabled(code) && !c.baseline.baselined(pass, fn, code) && c.touched(pass, fn)
+}
@@ -0,0 +1,95 @@
+package sf
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// converterShape identifies converters of the same types: models along with their containers
+// (e.g. []model.User into []db.User).
+type converterShape struct {
+	in, out                   *types.TypeName
+	inContainer, outContainer ContainerType
+}
+
+// reportDuplicateConverters reports converters of the package converting the same types as a converter
+// declared before them, along with the fields only one of them maps: duplicated converters are where
+// leaks sneak in after refactors. Converters listed in Config.AllowDuplicates are intentional variants
+// (e.g. ToUserRowRedacted) and neither reported nor reported against. It returns the number of findings reported.
+func reportDuplicateConverters(pass *analysis.Pass, cfg *Config, checks []*funcCheck) int {
+	first := make(map[converterShape]*funcCheck)
+	findings := 0
+	for _, check := range checks {
+		if !check.converter || check.err != nil || slices.Contains(cfg.AllowDuplicates, check.fn.Name) {
+			continue
+		}
+		if _, all := check.fn.ignoredFields(); all {
+			continue
+		}
+		in, out, err := Candidates(check.fn, pass)
+		if err != nil {
+			continue
+		}
+		shape := converterShape{in: in.Type, out: out.Type, inContainer: in.Container, outContainer: out.Container}
+		original, ok := first[shape]
+		if !ok {
+			first[shape] = check
+			continue
+		}
+		if !cfg.reports(pass, check.fn, CodeDuplicateConverter) {
+			continue
+		}
+
+		qualifier := types.RelativeTo(pass.Pkg)
+		message := fmt.Sprintf("converter function duplicates %s converting %s into %s (coverage %.0f%% vs %.0f%%)",
+			original.fn.Name, types.TypeString(in.Type.Type(), qualifier), types.TypeString(out.Type.Type(), qualifier),
+			check.result.Coverage*100, original.result.Coverage*100)
+		mine, theirs := mappedFields(check.result), mappedFields(original.result)
+		if only := fieldsDiff(theirs, mine); len(only) > 0 {
+			message += fmt.Sprintf("\n fields mapped by %s only: %v", original.fn.Name, only)
+		}
+		if only := fieldsDiff(mine, theirs); len(only) > 0 {
+			message += fmt.Sprintf("\n fields mapped by %s only: %v", check.fn.Name, only)
+		}
+		reportFunc(pass, cfg, check.filename, check.fn, analysis.Diagnostic{
+			Category: CodeDuplicateConverter,
+			Message:  withCodes(message, CodeDuplicateConverter),
+			Related: []analysis.RelatedInformation{{
+				Pos:     original.fn.NamePos,
+				Message: "converter " + original.fn.Name + " declared here",
+			}},
+		})
+		findings++
+	}
+	return findings
+}
+
+// mappedFields returns required input and output fields the converter maps, without variable names
+// (e.g. in.Name and out.Name rather than u.Name and row.Name), so converters can be compared.
+func mappedFields(result ConverterValidationResult) []string {
+	var mapped []string
+	add := func(side string, required, missing []string) {
+		for _, field := range required {
+			if !slices.Contains(missing, field) {
+				mapped = append(mapped, side+"."+unqualify(field))
+			}
+		}
+	}
+	add("in", result.InputFields, result.MissingInputFields)
+	add("out", result.OutputFields, result.MissingOutputFields)
+	return mapped
+}
+
+// fieldsDiff returns the fields of a missing from b.
+func fieldsDiff(a, b []string) []string {
+	var diff []string
+	for _, field := range a {
+		if !slices.Contains(b, field) {
+			diff = append(diff, field)
+		}
+	}
+	return diff
+}
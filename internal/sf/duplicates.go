@@ -0,0 +1,149 @@
+package sf
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// writeSite is a single write of an output field.
+type writeSite struct {
+	pos token.Pos
+	// block is the innermost block (or case clause) containing the write.
+	block ast.Node
+	// final is set for writes of returned literals: nothing can overwrite them afterwards.
+	final bool
+}
+
+// CollectDuplicateWrites inspects fn.Body and returns output fields that are written more than once
+// on the same path: twice within a block (e.g. a literal key and a later out.ID = ...), or within
+// a nested block and then again after it (e.g. in both if/else branches and then unconditionally).
+// Conditional overrides (if cond { out.ID = ... }) and self-updates (out.Price *= 2) are not reported.
+func CollectDuplicateWrites(fn *Func, outVar string, candidate *types.TypeName) []string {
+	outVars := outputVariables(fn, outVar, candidate)
+
+	sites := make(map[string][]writeSite)
+	var fields []string
+	add := func(name string, site writeSite) {
+		if _, ok := sites[name]; !ok {
+			fields = append(fields, name)
+		}
+		sites[name] = append(sites[name], site)
+	}
+
+	var blocks []ast.Node
+	var stack []ast.Node
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if n == nil {
+			if top := stack[len(stack)-1]; len(blocks) > 0 && blocks[len(blocks)-1] == top {
+				blocks = blocks[:len(blocks)-1]
+			}
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			// Nested functions (e.g. deferred ones) run at another time.
+			stack = stack[:len(stack)-1]
+			return false
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			blocks = append(blocks, x)
+		case *ast.AssignStmt:
+			block := blocks[len(blocks)-1]
+			if x.Tok == token.ASSIGN && len(x.Lhs) == len(x.Rhs) {
+				for i, lhs := range x.Lhs {
+					sel, ok := lhs.(*ast.SelectorExpr)
+					if !ok {
+						continue
+					}
+					ident, ok := sel.X.(*ast.Ident)
+					if !ok || !outVars.contain(fn.info, ident) {
+						continue
+					}
+					// Self-updates (out.Price = out.Price * 2) build upon the previous value.
+					self := variable{name: ident.Name, obj: fn.object(ident)}
+					if self.collector(fn.info, RecordFields).Walk(x.Rhs[i]).LookUp(sel.Sel.Name) {
+						continue
+					}
+					add(sel.Sel.Name, writeSite{pos: sel.Pos(), block: block})
+				}
+			}
+			for i, expr := range x.Rhs {
+				// Literals of other variables (e.g. ones shadowing the output) are not written into the output.
+				if len(x.Lhs) == len(x.Rhs) {
+					if ident, ok := x.Lhs[i].(*ast.Ident); ok && !outVars.contain(fn.info, ident) {
+						continue
+					}
+				}
+				if cl := candidateLiteral(fn.info, expr, candidate); cl != nil {
+					forEachLiteralField(fn.info, cl, func(name string, value ast.Expr) {
+						add(name, writeSite{pos: value.Pos(), block: block})
+					})
+				}
+			}
+		case *ast.ReturnStmt:
+			block := blocks[len(blocks)-1]
+			for _, expr := range x.Results {
+				if cl := candidateLiteral(fn.info, expr, candidate); cl != nil {
+					forEachLiteralField(fn.info, cl, func(name string, value ast.Expr) {
+						add(name, writeSite{pos: value.Pos(), block: block, final: true})
+					})
+				}
+			}
+		}
+		return true
+	})
+
+	var duplicates []string
+	for _, name := range fields {
+		if hasOverwrite(sites[name]) {
+			duplicates = append(duplicates, name)
+		}
+	}
+	return duplicates
+}
+
+// hasOverwrite reports whether any write is followed by another one in the same or an enclosing block.
+// Writes in nested blocks ending with a return are never followed by writes of enclosing blocks.
+func hasOverwrite(sites []writeSite) bool {
+	for i, earlier := range sites {
+		if earlier.final {
+			continue
+		}
+		for _, later := range sites[i+1:] {
+			if later.pos <= earlier.pos || !encloses(later.block, earlier.block) {
+				continue
+			}
+			if later.block != earlier.block && endsWithReturn(earlier.block) {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// encloses reports whether the outer node contains the inner one (or is the same node).
+func encloses(outer, inner ast.Node) bool {
+	return outer.Pos() <= inner.Pos() && inner.End() <= outer.End()
+}
+
+// endsWithReturn reports whether the block (or case clause) ends with a return statement.
+func endsWithReturn(block ast.Node) bool {
+	var list []ast.Stmt
+	switch b := block.(type) {
+	case *ast.BlockStmt:
+		list = b.List
+	case *ast.CaseClause:
+		list = b.Body
+	case *ast.CommClause:
+		list = b.Body
+	}
+	if len(list) == 0 {
+		return false
+	}
+	_, ok := list[len(list)-1].(*ast.ReturnStmt)
+	return ok
+}
@@ -0,0 +1,154 @@
+package sf
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxFailures limits failure points listed by messages of CodeMissingError.
+const maxFailures = 3
+
+// returnsError reports whether the last result of the function is an error.
+func returnsError(fn *Func) bool {
+	results := fn.Signature.Results()
+	return results.Len() > 0 && isError(results.At(results.Len()-1).Type())
+}
+
+// isError reports whether the type is the error interface.
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// unreturnedFailures returns the expressions of the converter that can fail, while it returns no error
+// for them: calls returning an error (e.g. strconv.Atoi) and type assertions panicking on mismatches
+// (u.Meta.(Meta), unlike the comma-ok form). Function literals fail on their own and are left out,
+// as are converters ignored entirely (see Func.ignoredFields).
+func unreturnedFailures(fn *Func) []ast.Expr {
+	if _, all := fn.ignoredFields(); all {
+		return nil
+	}
+	if fn.Body == nil || fn.Signature == nil || returnsError(fn) {
+		return nil
+	}
+	// Type assertions of comma-ok assignments and type switches don't panic.
+	checked := make(map[ast.Expr]bool)
+	var failures []ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.TypeSwitchStmt:
+			ast.Inspect(x.Assign, func(n ast.Node) bool {
+				if assert, ok := n.(*ast.TypeAssertExpr); ok {
+					checked[assert] = true
+				}
+				return true
+			})
+		case *ast.AssignStmt:
+			if len(x.Lhs) == 2 && len(x.Rhs) == 1 {
+				if assert, ok := ast.Unparen(x.Rhs[0]).(*ast.TypeAssertExpr); ok {
+					checked[assert] = true
+				}
+			}
+		case *ast.ValueSpec:
+			if len(x.Names) == 2 && len(x.Values) == 1 {
+				if assert, ok := ast.Unparen(x.Values[0]).(*ast.TypeAssertExpr); ok {
+					checked[assert] = true
+				}
+			}
+		case *ast.TypeAssertExpr:
+			if x.Type != nil && !checked[x] {
+				failures = append(failures, x)
+			}
+		case *ast.CallExpr:
+			switch t := fn.info.TypeOf(x).(type) {
+			case *types.Tuple:
+				if t.Len() > 0 && isError(t.At(t.Len()-1).Type()) {
+					failures = append(failures, x)
+				}
+			default:
+				if t != nil && isError(t) {
+					failures = append(failures, x)
+				}
+			}
+		}
+		return true
+	})
+	return failures
+}
+
+// missingErrorMessage returns the message of CodeMissingError listing the failure points.
+func missingErrorMessage(failures []ast.Expr) string {
+	var points []string
+	for i, expr := range failures {
+		if i == maxFailures {
+			points = append(points, "...")
+			break
+		}
+		if call, ok := expr.(*ast.CallExpr); ok {
+			// Arguments make the list hard to read.
+			points = append(points, types.ExprString(call.Fun))
+			continue
+		}
+		points = append(points, types.ExprString(expr))
+	}
+	return "converter function can fail but returns no error: " + strings.Join(points, ", ")
+}
+
+// uncheckedNilInput returns the name of the pointer input of the converter if it's dereferenced
+// (e.g. u.Name or *u) without ever being compared to nil, or an empty string.
+// Converters ignored entirely (see Func.ignoredFields) may trust their callers.
+func uncheckedNilInput(pass *analysis.Pass, fn *Func) string {
+	if _, all := fn.ignoredFields(); all {
+		return ""
+	}
+	in, _, err := Candidates(fn, pass)
+	if err != nil || in.Container != ContainerPointer || fn.Body == nil {
+		return ""
+	}
+	input := fn.variable(in.Var)
+	if input == nil {
+		return ""
+	}
+	isInput := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && fn.object(id) == input
+	}
+	isNil := func(expr ast.Expr) bool {
+		id, ok := ast.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		_, ok = fn.object(id).(*types.Nil)
+		return ok
+	}
+
+	var dereferenced, checked bool
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.BinaryExpr:
+			if (x.Op == token.EQL || x.Op == token.NEQ) &&
+				(isInput(x.X) && isNil(x.Y) || isNil(x.X) && isInput(x.Y)) {
+				checked = true
+			}
+		case *ast.SelectorExpr:
+			if selection, ok := fn.info.Selections[x]; ok && isInput(x.X) &&
+				(selection.Kind() == types.FieldVal || !pointerReceiver(selection.Obj())) {
+				dereferenced = true
+			}
+		case *ast.StarExpr:
+			if isInput(x.X) {
+				dereferenced = true
+			}
+		}
+		return true
+	})
+	if !dereferenced || checked {
+		return ""
+	}
+	return in.Var
+}
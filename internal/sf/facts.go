@@ -0,0 +1,479 @@
+package sf
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ignoreDirective marks a struct field that converters are not required to map.
+// It can be placed either as a line comment or as a doc comment of the field.
+// Placed in the doc comment of a converter, it lists fields that converter is not required to map
+// (e.g. //sf:ignore Currency Price); without arguments the whole converter is ignored.
+const ignoreDirective = "//sf:ignore"
+
+// partialDirective in the doc comment of a converter declares the fields it intentionally leaves unmapped
+// per side (e.g. //sf:partial MissingInput=Secret,Token MissingOutput=Checksum); other missing fields
+// are still reported.
+const partialDirective = "//sf:partial"
+
+// configDirective in the package doc comment sets options for the package only
+// (e.g. //sf:config include-methods min-coverage=0.9).
+const configDirective = "//sf:config"
+
+// nolintDirective is the golangci-lint directive suppressing findings of the listed linters
+// (e.g. //nolint:stickyfields // reason); without a list, findings of all linters are suppressed.
+const nolintDirective = "//nolint"
+
+// StructFact holds per-field metadata of a named struct type, some of which can only be read
+// from the AST of the package declaring it. It's exported as an object fact, so converters
+// living in other packages can still honor it, and the struct is walked once for all of them.
+type StructFact struct {
+	// Fields contains names of exported fields in the order of declaration.
+	Fields []string
+	// Ignored contains names of fields marked with the //sf:ignore directive.
+	Ignored []string
+	// Deprecated contains names of fields documented with a "Deprecated:" paragraph.
+	Deprecated []string
+}
+
+func (*StructFact) AFact() {}
+
+func (f *StructFact) String() string {
+	return fmt.Sprintf("fields:%v ignored:%v deprecated:%v", f.Fields, f.Ignored, f.Deprecated)
+}
+
+// empty reports whether the fact carries no information worth exporting.
+func (f *StructFact) empty() bool {
+	return len(f.Fields) == 0 && len(f.Ignored) == 0 && len(f.Deprecated) == 0
+}
+
+// FactsAnalyzer exports field metadata of structs as facts for converters of other packages.
+// It's required by the analyzer and its sub-checks, as a fact type belongs to a single analyzer.
+var FactsAnalyzer = &analysis.Analyzer{
+	Name: "stickyfieldsfacts",
+	Doc:  "exports ignored and deprecated fields of structs for converters of other packages",
+	Run: func(pass *analysis.Pass) (any, error) {
+		exportStructFacts(pass)
+		return &structFacts{pass: pass}, nil
+	},
+	FactTypes:  []analysis.Fact{(*StructFact)(nil)},
+	ResultType: reflect.TypeOf((*structFacts)(nil)),
+}
+
+// structFacts is the result of FactsAnalyzer looking up StructFacts of named structs
+// of the package or of its dependencies, and interning field indexes of structs of the package's converters.
+type structFacts struct {
+	pass    *analysis.Pass
+	indexes fieldIndexes
+	// local holds facts of structs of the package when there is no FactsAnalyzer (see factsOf).
+	local map[*types.TypeName]*StructFact
+}
+
+// localFacts memoizes facts of structs of packages collected for passes without FactsAnalyzer.
+var localFacts packageMemo[*structFacts]
+
+// factsOf returns the result of FactsAnalyzer for the pass. Passes of analyzers not requiring it
+// (e.g. of tools validating functions via the public API) get facts of structs of their package only,
+// collected once per package.
+func factsOf(pass *analysis.Pass) *structFacts {
+	if facts, ok := pass.ResultOf[FactsAnalyzer].(*structFacts); ok {
+		return facts
+	}
+	return localFacts.get(pass.Pkg, func() *structFacts {
+		local := make(map[*types.TypeName]*StructFact)
+		collectStructFacts(pass, func(obj *types.TypeName, fact *StructFact) {
+			local[obj] = fact
+		})
+		return &structFacts{local: local}
+	})
+}
+
+// lookup returns the StructFact of the named struct, if there is one.
+func (f *structFacts) lookup(obj *types.TypeName) (*StructFact, bool) {
+	if f.pass == nil {
+		fact, ok := f.local[obj]
+		return fact, ok
+	}
+	var fact StructFact
+	return &fact, f.pass.ImportObjectFact(obj, &fact)
+}
+
+// exportStructFacts exports a StructFact for every named struct of the package
+// with exported fields or field metadata.
+func exportStructFacts(pass *analysis.Pass) {
+	collectStructFacts(pass, func(obj *types.TypeName, fact *StructFact) {
+		pass.ExportObjectFact(obj, fact)
+	})
+}
+
+// collectStructFacts walks all type declarations of the package and calls found with the StructFact
+// of every named struct with exported fields or field metadata.
+func collectStructFacts(pass *analysis.Pass, found func(obj *types.TypeName, fact *StructFact)) {
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				obj, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+
+				fact := collectStructFact(st)
+				if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+					fact.Fields = exportedFields(st)
+				}
+				if fact.empty() {
+					continue
+				}
+				found(obj, fact)
+			}
+		}
+	}
+}
+
+// collectStructFact gathers field metadata from the struct's AST.
+func collectStructFact(st *ast.StructType) *StructFact {
+	fact := &StructFact{}
+	for _, field := range st.Fields.List {
+		if hasDirective(field.Doc, ignoreDirective) || hasDirective(field.Comment, ignoreDirective) {
+			fact.Ignored = append(fact.Ignored, fieldNames(field)...)
+		}
+		if isDeprecated(field.Doc) || isDeprecated(field.Comment) {
+			fact.Deprecated = append(fact.Deprecated, fieldNames(field)...)
+		}
+	}
+	return fact
+}
+
+// isDeprecated checks if the comment group has a paragraph starting with "Deprecated: ".
+func isDeprecated(cg *ast.CommentGroup) bool {
+	if cg == nil {
+		return false
+	}
+	for _, line := range strings.Split(cg.Text(), "\n") {
+		if strings.HasPrefix(line, "Deprecated: ") {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames returns names declared by the field. Embedded fields are named after their type.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, 0, len(field.Names))
+		for _, n := range field.Names {
+			names = append(names, n.Name)
+		}
+		return names
+	}
+
+	t := field.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch x := t.(type) {
+	case *ast.Ident:
+		return []string{x.Name}
+	case *ast.SelectorExpr:
+		return []string{x.Sel.Name}
+	}
+	return nil
+}
+
+// hasDirective checks if the comment group contains the given directive.
+func hasDirective(cg *ast.CommentGroup, directive string) bool {
+	if cg == nil {
+		return false
+	}
+	for _, c := range cg.List {
+		text := strings.TrimSpace(c.Text)
+		if text == directive || strings.HasPrefix(text, directive+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// nolintLines returns the lines of the file holding //nolint directives that apply to the analyzer.
+func nolintLines(fset *token.FileSet, file *ast.File) map[int]bool {
+	lines := make(map[int]bool)
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if isNolint(c.Text) {
+				lines[fset.Position(c.Slash).Line] = true
+			}
+		}
+	}
+	return lines
+}
+
+// isNolint reports whether the comment is a //nolint directive applying to the analyzer:
+// bare, for all linters or listing stickyfields among others, optionally followed by a reason.
+func isNolint(text string) bool {
+	rest, ok := strings.CutPrefix(text, nolintDirective)
+	if !ok {
+		return false
+	}
+	if rest == "" || strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, "//") {
+		return true
+	}
+	list, ok := strings.CutPrefix(rest, ":")
+	if !ok {
+		return false
+	}
+	if i := strings.IndexAny(list, " /"); i >= 0 {
+		list = list[:i]
+	}
+	for _, linter := range strings.Split(list, ",") {
+		if linter == "all" || linter == analyzerName {
+			return true
+		}
+	}
+	return false
+}
+
+// directiveArgs returns space-separated arguments of the directive found in the comment group.
+// The boolean result reports whether the directive was found at all.
+func directiveArgs(cg *ast.CommentGroup, directive string) ([]string, bool) {
+	if cg == nil {
+		return nil, false
+	}
+	var (
+		args  []string
+		found bool
+	)
+	for _, c := range cg.List {
+		text := strings.TrimSpace(c.Text)
+		if text != directive && !strings.HasPrefix(text, directive+" ") {
+			continue
+		}
+		found = true
+		args = append(args, strings.Fields(strings.TrimPrefix(text, directive))...)
+	}
+	return args, found
+}
+
+// exportedFields returns names of exported fields of the struct.
+func exportedFields(st *types.Struct) []string {
+	var names []string
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Exported() {
+			names = append(names, field.Name())
+		}
+	}
+	return names
+}
+
+// structIndex returns the interned index of exported fields of the candidate struct. Its names come
+// from the fact of the named struct if there is one, walking the struct otherwise (e.g. for structs
+// of packages not analyzed).
+func structIndex(pass *analysis.Pass, obj *types.TypeName, st *types.Struct) *fieldIndex {
+	facts := factsOf(pass)
+	return facts.indexes.get(st, func() []string {
+		if obj != nil {
+			if fact, ok := facts.lookup(obj); ok && obj.Type().Underlying() == st {
+				return fact.Fields
+			}
+		}
+		return exportedFields(st)
+	})
+}
+
+// skippedFields returns the set of fields of the given named type that converters are not required to map:
+// fields marked as ignored, deprecated ones (unless cfg.IncludeDeprecated is set)
+// protobuf internals (when cfg.ProtoAware is set), configured embedded base types with the fields they promote
+// and machinery fields of configured ORM profiles.
+// The set is bound to the index of the struct's fields (see structIndex).
+func skippedFields(pass *analysis.Pass, cfg *Config, obj *types.TypeName) *UsageLookup {
+	if obj == nil {
+		return NewUsageLookup()
+	}
+
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return NewUsageLookup()
+	}
+
+	ul := newFieldUsage(structIndex(pass, obj, st))
+	if cfg.ProtoAware && isProtoMessage(pass, obj) {
+		ul.AddAll(protoSkippedFields(st))
+	}
+
+	for _, name := range embeddedBaseFields(cfg, st) {
+		ul.Add(name)
+	}
+
+	for _, profile := range cfg.ORMProfiles {
+		for _, name := range ormProfiles[profile](obj, st) {
+			ul.Add(name)
+		}
+	}
+
+	fact, ok := factsOf(pass).lookup(obj)
+	if !ok {
+		return ul
+	}
+	for _, name := range fact.Ignored {
+		ul.Add(name)
+	}
+	if !cfg.IncludeDeprecated {
+		for _, name := range fact.Deprecated {
+			ul.Add(name)
+		}
+	}
+	return ul
+}
+
+// requiredTag is the value of the sf struct tag of fields every converter has to map
+// (e.g. TenantID string `sf:"required"`), whatever exempts other fields.
+const requiredTag = "required"
+
+// requiredField is a field tagged as required (see requiredTag).
+type requiredField struct {
+	// name is the name of the field. Fields promoted from embedded structs are named as they're accessed.
+	name string
+	// via is the field of the struct carrying it: the field itself, or the embedded struct it's promoted from.
+	via string
+}
+
+// taggedRequired returns exported fields of the struct tagged as required (see requiredTag),
+// including the ones promoted from embedded structs.
+func taggedRequired(st *types.Struct) []requiredField {
+	return taggedRequiredOf(st, map[*types.Struct]bool{})
+}
+
+// taggedRequiredOf returns fields of the struct tagged as required, skipping embedded structs seen already
+// (e.g. in cycles of embedded pointers).
+func taggedRequiredOf(st *types.Struct, seen map[*types.Struct]bool) []requiredField {
+	seen[st] = true
+	declared := make(map[string]bool, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		declared[st.Field(i).Name()] = true
+	}
+
+	var fields []requiredField
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+		if slices.Contains(strings.Split(reflect.StructTag(st.Tag(i)).Get("sf"), ","), requiredTag) {
+			fields = append(fields, requiredField{name: field.Name(), via: field.Name()})
+		}
+		if !field.Embedded() {
+			continue
+		}
+		t := field.Type()
+		if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		embedded, ok := t.Underlying().(*types.Struct)
+		if !ok || seen[embedded] {
+			continue
+		}
+		for _, promoted := range taggedRequiredOf(embedded, seen) {
+			// Fields declared by the struct itself shadow the promoted ones.
+			if !declared[promoted.name] {
+				fields = append(fields, requiredField{name: promoted.name, via: field.Name()})
+			}
+		}
+	}
+	return fields
+}
+
+// exemptTagged returns the skipped fields without the ones tagged as required.
+func exemptTagged(skipped *UsageLookup, st *types.Struct) *UsageLookup {
+	tagged := taggedRequired(st)
+	if len(tagged) == 0 {
+		return skipped
+	}
+	res := newFieldUsage(skipped.index)
+	for name := range skipped.All() {
+		if !slices.ContainsFunc(tagged, func(f requiredField) bool { return f.name == name }) {
+			res.Add(name)
+		}
+	}
+	return res
+}
+
+// promotedTagged returns names of fields tagged as required promoted from embedded structs.
+// Unlike the struct's own fields, they are not indexed (see structIndex), so they're required on top of them.
+func promotedTagged(st *types.Struct) []string {
+	var names []string
+	for _, f := range taggedRequired(st) {
+		if f.name != f.via {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// appendMissingTagged appends fields tagged as required which aren't used directly to the missing ones:
+// getters don't count for them. Promoted fields are used along with the embedded struct carrying them.
+// It sets *found if any of the tagged fields is missing.
+func appendMissingTagged(missing []string, st *types.Struct, used *UsageLookup, found *bool) []string {
+	for _, f := range taggedRequired(st) {
+		if used.LookUp(f.name) || used.LookUp(f.via) {
+			continue
+		}
+		*found = true
+		if !slices.Contains(missing, f.name) {
+			missing = append(missing, f.name)
+		}
+	}
+	return missing
+}
+
+// embeddedBaseFields returns names of the struct's fields of configured embedded base types
+// along with the fields they promote (e.g. gorm.Model, ID, CreatedAt, UpdatedAt, DeletedAt).
+func embeddedBaseFields(cfg *Config, st *types.Struct) []string {
+	var names []string
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Embedded() || !isEmbeddedBaseType(cfg, field.Type()) {
+			continue
+		}
+		names = append(names, field.Name())
+		if base := derefStruct(field.Type()); base != nil {
+			names = append(names, exportedFields(base)...)
+		}
+	}
+	return names
+}
+
+// isEmbeddedBaseType checks if the (possibly pointer) type is one of configured embedded base types.
+func isEmbeddedBaseType(cfg *Config, t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	for _, ref := range cfg.EmbeddedBaseTypes {
+		if typeMatches(ref, named.Obj()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package sf
+
+import (
+	"go/types"
+	"math/bits"
+	"sync"
+)
+
+// fieldIndex interns exported fields of a struct type: fields are referred to by their position
+// among the exported fields, so sets of them are bitsets (see UsageLookup) rather than maps of names.
+type fieldIndex struct {
+	names     []string
+	positions map[string]int
+}
+
+func newFieldIndex(names []string) *fieldIndex {
+	positions := make(map[string]int, len(names))
+	for i, name := range names {
+		positions[name] = i
+	}
+	return &fieldIndex{names: names, positions: positions}
+}
+
+// lookup returns the position of the named field. It's safe to call on a nil index.
+func (x *fieldIndex) lookup(name string) (int, bool) {
+	if x == nil {
+		return 0, false
+	}
+	i, ok := x.positions[name]
+	return i, ok
+}
+
+// fieldNames returns names of the indexed fields. It's safe to call on a nil index.
+func (x *fieldIndex) fieldNames() []string {
+	if x == nil {
+		return nil
+	}
+	return x.names
+}
+
+// fieldIndexes interns field indexes of struct types during the analysis of a package.
+// Converters are checked concurrently, so it's guarded by a mutex.
+type fieldIndexes struct {
+	mu      sync.Mutex
+	indexes map[*types.Struct]*fieldIndex
+}
+
+// get returns the index of the struct, building it with names the first time.
+func (c *fieldIndexes) get(st *types.Struct, names func() []string) *fieldIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if x, ok := c.indexes[st]; ok {
+		return x
+	}
+	if c.indexes == nil {
+		c.indexes = make(map[*types.Struct]*fieldIndex)
+	}
+	x := newFieldIndex(names())
+	c.indexes[st] = x
+	return x
+}
+
+// bitset is a set of small non-negative integers.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) has(i int) bool {
+	return i/64 < len(b) && b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitset) len() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
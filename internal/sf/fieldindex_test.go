@@ -0,0 +1,94 @@
+package sf
+
+import (
+	"fmt"
+	"testing"
+)
+
+// wideFields returns names of fields of a struct with n fields.
+func wideFields(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("Field%d", i)
+	}
+	return names
+}
+
+// BenchmarkMissingFields compares looking up required and missing fields of a struct with hundreds
+// of fields, every third one skipped and every other one used, in sets of names and in sets bound
+// to the interned index of the struct's fields.
+func BenchmarkMissingFields(b *testing.B) {
+	for _, n := range []int{50, 500} {
+		fields := wideFields(n)
+		index := newFieldIndex(fields)
+
+		b.Run(fmt.Sprintf("names/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				skipped, used := NewUsageLookup(), NewUsageLookup()
+				for i, name := range fields {
+					if i%3 == 0 {
+						skipped.Add(name)
+					}
+					if i%2 == 0 {
+						used.Add(name)
+					}
+				}
+				var required, missing []string
+				for _, name := range fields {
+					if skipped.LookUp(name) {
+						continue
+					}
+					required = append(required, name)
+					if !used.LookUp(name) {
+						missing = append(missing, name)
+					}
+				}
+				_, _ = required, missing
+			}
+		})
+
+		b.Run(fmt.Sprintf("bitset/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				skipped, used := newFieldUsage(index), newFieldUsage(index)
+				for i, name := range fields {
+					if i%3 == 0 {
+						skipped.Add(name)
+					}
+					if i%2 == 0 {
+						used.Add(name)
+					}
+				}
+				_ = requiredFields(index, skipped)
+				_ = collectMissingFields(index, skipped, used)
+			}
+		})
+	}
+}
+
+func TestUsageLookupBind(t *testing.T) {
+	index := newFieldIndex([]string{"ID", "Name", "Email"})
+	ul := NewUsageLookup("Name", "GetID")
+
+	bound := ul.bind(index)
+	if bound.bind(index) != bound {
+		t.Errorf("binding a bound set again copied it")
+	}
+	for name, want := range map[string]bool{"ID": false, "Name": true, "Email": false, "GetID": true, "Other": false} {
+		if got := bound.LookUp(name); got != want {
+			t.Errorf("LookUp(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if bound.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", bound.Len())
+	}
+
+	bound.Add("Email")
+	if got := collectMissingFields(index, NewUsageLookup(), bound); len(got) != 1 || got[0] != "ID" {
+		t.Errorf("missing fields = %v, want [ID]", got)
+	}
+	if ul.LookUp("Email") {
+		t.Errorf("adding to the bound copy changed the original set")
+	}
+}
@@ -3,7 +3,6 @@ package sf
 import (
 	"bufio"
 	"fmt"
-	"go/ast"
 	"io"
 	"os"
 	"strings"
@@ -15,10 +14,10 @@ import (
 // PrettyPrint writes a linter message in a Rust-like style to the given writer.
 // It extracts the source line from the file (using filename and pos.Line), shortens it to a maximum
 // width (80 characters) while preserving the significant ranges, adjusts the caret position, and prints
-// the formatted diagnostic.
+// the formatted diagnostic. ANSI colors are used only if colored is set.
 // TODO: make a struct-base method (so we do not send `pass` via arg, etc)
-func PrettyPrint(w io.Writer, filename string, fn *ast.FuncDecl, pass *analysis.Pass, message string) {
-	pos := pass.Fset.Position(fn.Name.Pos())
+func PrettyPrint(w io.Writer, filename string, fn *Func, pass *analysis.Pass, message string, colored bool) {
+	pos := pass.Fset.Position(fn.NamePos)
 
 	// Open the file.
 	file, err := os.Open(filename)
@@ -74,9 +73,9 @@ func PrettyPrint(w io.Writer, filename string, fn *ast.FuncDecl, pass *analysis.
 	}
 
 	// Prepare colored output.
-	blue := color.New(color.FgBlue).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	bold := color.New(color.Bold).SprintFunc()
+	blue := colorFunc(colored, color.FgBlue)
+	red := colorFunc(colored, color.FgRed)
+	bold := colorFunc(colored, color.Bold)
 
 	_ = blue
 	_ = bold
@@ -90,8 +89,7 @@ func PrettyPrint(w io.Writer, filename string, fn *ast.FuncDecl, pass *analysis.
 	// )
 	fmt.Fprintf(w, "\n")
 
-	fnName := fn.Name.Name
-	fnNameLen := len(fnName)
+	fnNameLen := fn.nameLen()
 
 	fmt.Fprintf(w, "%*s |\n", gutterWidth, "")
 	fmt.Fprintf(w, "%*d | %s\n", gutterWidth, pos.Line, shortLine)
@@ -101,6 +99,18 @@ func PrettyPrint(w io.Writer, filename string, fn *ast.FuncDecl, pass *analysis.
 	// fmt.Fprintf(w, "\n%s: aborting due to previous error\n", bold("error"))
 }
 
+// colorFunc returns a function formatting its arguments with the attributes if colored is set.
+// Unlike color.New, it does not depend on the global color.NoColor.
+func colorFunc(colored bool, attrs ...color.Attribute) func(a ...any) string {
+	c := color.New(attrs...)
+	if colored {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+	return c.SprintFunc()
+}
+
 // shortenLine shortens a given line to at most maxWidth characters while preserving
 // If any portion is omitted, ellipses ("...") are inserted accordingly.
 func shortenLine(line string, maxWidth int) string {
@@ -0,0 +1,246 @@
+package sf
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// anonymousFuncName is the name given to function literals not assigned to any variable.
+const anonymousFuncName = "func literal"
+
+// Func is a function that may be a converter: either a function declaration
+// or a function literal (e.g. `var toDB = func(...) ... {}` or a callback passed inline).
+type Func struct {
+	// Name is the function name. Function literals are named after the variable they are assigned to.
+	Name string
+	// NamePos is the position of the function name (or of the func keyword for anonymous literals).
+	NamePos token.Pos
+	// Recv is the receiver of a method. It's nil for plain functions and literals.
+	Recv *ast.FieldList
+	// Type holds the function's parameters and results.
+	Type *ast.FuncType
+	// Body is the function body. It's nil for functions implemented externally.
+	Body *ast.BlockStmt
+	// Signature is the type of the function.
+	Signature *types.Signature
+	// Decl is the declaration of the function. It's nil for function literals.
+	Decl *ast.FuncDecl
+	// Lit is the function literal. It's nil for declared functions.
+	Lit *ast.FuncLit
+	// Registered is set when the function is placed into a converter registry
+	// (e.g. map[string]func(model.Event) db.Event): it's a converter regardless of type names.
+	Registered bool
+	// Nolint is set when the function is suppressed with a //nolint directive of golangci-lint
+	// on its declaration line or in its doc comment.
+	Nolint bool
+
+	// info is the type information of the package declaring the function.
+	info *types.Info
+}
+
+// NewFuncFromDecl creates a Func from a function declaration.
+func NewFuncFromDecl(pass *analysis.Pass, decl *ast.FuncDecl) *Func {
+	fn := &Func{
+		Name:    decl.Name.Name,
+		NamePos: decl.Name.Pos(),
+		Recv:    decl.Recv,
+		Type:    decl.Type,
+		Body:    decl.Body,
+		Decl:    decl,
+		info:    pass.TypesInfo,
+	}
+	if obj := pass.TypesInfo.Defs[decl.Name]; obj != nil {
+		fn.Signature, _ = obj.Type().(*types.Signature)
+	}
+	return fn
+}
+
+// NewFuncFromLit creates a Func from a function literal.
+// The literal is named after the identifier it's assigned to, if any.
+func NewFuncFromLit(pass *analysis.Pass, lit *ast.FuncLit, name *ast.Ident) *Func {
+	fn := &Func{
+		Name:    anonymousFuncName,
+		NamePos: lit.Type.Func,
+		Type:    lit.Type,
+		Body:    lit.Body,
+		Lit:     lit,
+		info:    pass.TypesInfo,
+	}
+	if name != nil {
+		fn.Name = name.Name
+		fn.NamePos = name.Pos()
+	}
+	fn.Signature, _ = pass.TypesInfo.TypeOf(lit).(*types.Signature)
+	return fn
+}
+
+// suppressed reports whether the function is suppressed by any of the //nolint lines (see nolintLines).
+func (fn *Func) suppressed(fset *token.FileSet, nolint map[int]bool) bool {
+	if nolint[fset.Position(fn.NamePos).Line] {
+		return true
+	}
+	if fn.Decl == nil || fn.Decl.Doc == nil {
+		return false
+	}
+	for _, c := range fn.Decl.Doc.List {
+		if nolint[fset.Position(c.Slash).Line] {
+			return true
+		}
+	}
+	return false
+}
+
+// receiverName returns the name of the receiver type of a method, or an empty string.
+func (fn *Func) receiverName() string {
+	if fn.Signature == nil || fn.Signature.Recv() == nil {
+		return ""
+	}
+	t := fn.Signature.Recv().Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// qualifiedName returns the function name qualified by the receiver type for methods (e.g. Mapper.ToDB).
+func (fn *Func) qualifiedName() string {
+	if recv := fn.receiverName(); recv != "" {
+		return recv + "." + fn.Name
+	}
+	return fn.Name
+}
+
+// ignoredFields returns fields listed in the //sf:ignore directive of the function's doc comment.
+// all is set when the directive has no arguments, i.e. the whole function is ignored.
+func (fn *Func) ignoredFields() (fields *UsageLookup, all bool) {
+	fields = NewUsageLookup()
+	if fn.Nolint {
+		return fields, true
+	}
+	if fn.Decl == nil {
+		return fields, false
+	}
+	args, found := directiveArgs(fn.Decl.Doc, ignoreDirective)
+	for _, name := range args {
+		fields.Add(name)
+	}
+	return fields, found && len(args) == 0
+}
+
+// partialFields returns fields declared intentionally unmapped by the //sf:partial directive
+// of the function's doc comment: input fields listed by MissingInput and output ones by MissingOutput.
+func (fn *Func) partialFields() (in, out *UsageLookup) {
+	in, out = NewUsageLookup(), NewUsageLookup()
+	if fn.Decl == nil {
+		return in, out
+	}
+	args, _ := directiveArgs(fn.Decl.Doc, partialDirective)
+	for _, arg := range args {
+		side, names, _ := strings.Cut(arg, "=")
+		var fields *UsageLookup
+		switch side {
+		case "MissingInput":
+			fields = in
+		case "MissingOutput":
+			fields = out
+		default:
+			continue
+		}
+		for _, name := range splitList(names) {
+			fields.Add(name)
+		}
+	}
+	return in, out
+}
+
+// variable returns the object of the function's parameter, result or receiver with the given name.
+// It returns nil if there is none. Variables declared in the body are resolved by their
+// identifiers instead (see object), as several of them may share a name.
+func (fn *Func) variable(name string) types.Object {
+	if fn.info == nil || name == "" {
+		return nil
+	}
+	for _, list := range []*ast.FieldList{fn.Recv, fn.Type.Params, fn.Type.Results} {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			for _, ident := range field.Names {
+				if ident.Name == name {
+					return fn.info.Defs[ident]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// object returns the object the identifier declares or refers to, or nil without type information.
+func (fn *Func) object(ident *ast.Ident) types.Object {
+	if fn.info == nil {
+		return nil
+	}
+	return fn.info.ObjectOf(ident)
+}
+
+// identKey returns a map key of the variable the identifier refers to: its object,
+// or its name without type information.
+func (fn *Func) identKey(ident *ast.Ident) any {
+	if obj := fn.object(ident); obj != nil {
+		return obj
+	}
+	return ident.Name
+}
+
+// usageCollector returns a collector of usages of the function's parameter, result or receiver
+// with the given name, resolved to its object (see variable) so variables shadowing it
+// in nested blocks are not counted.
+func (fn *Func) usageCollector(varName string, rType CollectingType) *UsageCollector {
+	return NewUsageCollector(varName, rType).Resolve(fn.info, fn.variable(varName))
+}
+
+// nameLen returns the length of the function name as it's written in the source.
+func (fn *Func) nameLen() int {
+	if fn.Lit != nil && fn.Name == anonymousFuncName {
+		return len(token.FUNC.String())
+	}
+	return len(fn.Name)
+}
+
+// literalNames maps function literals of the package to identifiers they are assigned to
+// via variable declarations (var toDB = func...) or assignments (toDB := func...).
+func literalNames(in *inspector.Inspector) map[*ast.FuncLit]*ast.Ident {
+	names := make(map[*ast.FuncLit]*ast.Ident)
+	in.Preorder([]ast.Node{(*ast.ValueSpec)(nil), (*ast.AssignStmt)(nil)}, func(n ast.Node) {
+		switch x := n.(type) {
+		case *ast.ValueSpec:
+			for i, v := range x.Values {
+				if lit, ok := v.(*ast.FuncLit); ok && i < len(x.Names) {
+					names[lit] = x.Names[i]
+				}
+			}
+		case *ast.AssignStmt:
+			if len(x.Lhs) != len(x.Rhs) {
+				return
+			}
+			for i, v := range x.Rhs {
+				lit, ok := v.(*ast.FuncLit)
+				if !ok {
+					continue
+				}
+				if ident, ok := x.Lhs[i].(*ast.Ident); ok && ident.Name != "_" {
+					names[lit] = ident
+				}
+			}
+		}
+	})
+	return names
+}
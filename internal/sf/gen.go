@@ -0,0 +1,81 @@
+package sf
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// generatedInputVar is the name of the input parameter of generated converters.
+const generatedInputVar = "in"
+
+// GenerateConverter returns the source of a converter function from the named struct in into out.
+// Output fields are assigned from input fields matched the way the analyzer suggests sources of missing
+// fields (see suggestSources): fields matched by similar names rather than equal ones are marked for review,
+// and fields left unmatched on either side are listed in the //sf:ignore directive of the converter
+// along with a TODO marker, so the converter passes the analyzer as generated.
+// The converter is named after the first template of the default naming convention (see Config.Naming)
+// unless a name is given; types are qualified by the qualifier.
+func GenerateConverter(name string, in, out *types.TypeName, qualifier types.Qualifier) (string, error) {
+	inStruct, ok := in.Type().Underlying().(*types.Struct)
+	if !ok {
+		return "", fmt.Errorf("%s is not a struct", in.Name())
+	}
+	outStruct, ok := out.Type().Underlying().(*types.Struct)
+	if !ok {
+		return "", fmt.Errorf("%s is not a struct", out.Name())
+	}
+	if name == "" {
+		name = expandNaming(DefaultConfig().Naming[:1], Candidate{Type: in}, Candidate{Type: out})[0]
+	}
+
+	outFields := exportedFields(outStruct)
+	sources := suggestSources(inStruct, outStruct, outFields, NewUsageLookup())
+
+	var (
+		body      bytes.Buffer
+		unmatched []string
+	)
+	read := NewUsageLookup()
+	for _, field := range outFields {
+		src, ok := sources[field]
+		if !ok {
+			unmatched = append(unmatched, field)
+			continue
+		}
+		inType, outType := structField(inStruct, src).Type(), structField(outStruct, field).Type()
+		value := generatedInputVar + "." + src
+		switch {
+		case types.AssignableTo(inType, outType):
+		case similarTypes(inType, outType):
+			value = types.TypeString(outType, qualifier) + "(" + value + ")"
+		default:
+			unmatched = append(unmatched, field)
+			continue
+		}
+		read.Add(src)
+		fmt.Fprintf(&body, "\t\t%s: %s,", field, value)
+		if nameSimilarity(src, field) < 1 {
+			body.WriteString(" // TODO: check, matched by a similar name")
+		}
+		body.WriteString("\n")
+	}
+	for _, field := range exportedFields(inStruct) {
+		if !read.LookUp(field) && !slices.Contains(unmatched, field) {
+			unmatched = append(unmatched, field)
+		}
+	}
+
+	inType, outType := types.TypeString(in.Type(), qualifier), types.TypeString(out.Type(), qualifier)
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// %s converts %s into %s.\n", name, inType, outType)
+	if len(unmatched) > 0 {
+		fmt.Fprintf(&src, "//\n// TODO: map %s and remove them from the %s directive.\n//\n%s %s\n",
+			strings.Join(unmatched, ", "), ignoreDirective, ignoreDirective, strings.Join(unmatched, " "))
+	}
+	fmt.Fprintf(&src, "func %s(%s %s) %s {\n\treturn %s{\n%s\t}\n}\n",
+		name, generatedInputVar, inType, outType, outType, body.String())
+	return src.String(), nil
+}
@@ -0,0 +1,225 @@
+package sf
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// GeneratedTestsHeader starts test files written by GenerateTests, so they can be told apart
+// from hand-written ones (and regenerated).
+const GeneratedTestsHeader = "// Code generated by stickyfields gen-tests. DO NOT EDIT."
+
+// GenerateTests returns the source of a test file of the package checking at run time what the analyzer
+// checks statically: every output field a converter of the result is required to map (and maps,
+// as far as the analyzer can tell) is set when the converter is given an input with all fields populated.
+// It catches fields mapped from the wrong input field or overwritten later, for instance.
+//
+// Converters taking their input model (or a pointer to it) only and returning their output model
+// (or a pointer to it), optionally along with an error, are tested. The others are returned as skipped
+// along with the reason. The source is empty when no converter is tested.
+func GenerateTests(pkg *types.Package, result *Result) (src []byte, skipped []string, err error) {
+	imports := map[string]bool{"reflect": true, "testing": true, "time": true}
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		imports[p.Path()] = true
+		return p.Name()
+	}
+
+	var cases bytes.Buffer
+	for _, c := range result.Converters {
+		call, reason := testedCall(pkg, c, qualifier)
+		if reason != "" {
+			skipped = append(skipped, c.qualifiedName()+": "+reason)
+			continue
+		}
+		var fields []string
+		for _, field := range c.OutputFields {
+			if !slices.Contains(c.MissingOutputFields, field) {
+				fields = append(fields, fmt.Sprintf("%q", unqualify(field)))
+			}
+		}
+		fmt.Fprintf(&cases, "\t\t{\n\t\t\tname: %q,\n\t\t\tconvert: func() (any, error) {\n%s\t\t\t},\n", c.Name, call)
+		fmt.Fprintf(&cases, "\t\t\tfields: []string{%s},\n\t\t},\n", strings.Join(fields, ", "))
+	}
+	if cases.Len() == 0 {
+		return nil, skipped, nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n\npackage %s\n\nimport (\n", GeneratedTestsHeader, pkg.Name())
+	// Standard library imports go first, separated from the others.
+	var std, others []string
+	for _, path := range slices.Sorted(maps.Keys(imports)) {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	for _, path := range std {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	if len(others) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, path := range others {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	fmt.Fprintf(&buf, ")\n\n%s%s\n", fmt.Sprintf(generatedTestFunc, cases.String()), generatedPopulateFunc)
+	src, err = format.Source(buf.Bytes())
+	if err != nil {
+		return nil, skipped, fmt.Errorf("formatting tests of %s: %w", pkg.Path(), err)
+	}
+	return src, skipped, nil
+}
+
+// testedCall returns the body of the function converting a populated input with the converter,
+// or why the converter can't be tested.
+func testedCall(pkg *types.Package, c Converter, qualifier types.Qualifier) (call, reason string) {
+	if c.Receiver != "" {
+		return "", "methods are not tested"
+	}
+	if c.UnknownCoverage != "" {
+		return "", "unknown coverage: " + c.UnknownCoverage
+	}
+	fn, ok := pkg.Scope().Lookup(c.Name).(*types.Func)
+	if !ok {
+		return "", "function literals are not tested"
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.TypeParams() != nil {
+		return "", "generic converters are not tested"
+	}
+	if sig.Params().Len() != 1 {
+		return "", "converters taking more than the input model are not tested"
+	}
+	in, inPointer := structModel(sig.Params().At(0).Type())
+	if in == nil {
+		return "", "the input is not a struct or a pointer to one"
+	}
+	results := sig.Results()
+	withError := results.Len() == 2 && types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type())
+	if results.Len() != 1 && !withError {
+		return "", "converters returning more than the output model and an error are not tested"
+	}
+	if out, _ := structModel(results.At(0).Type()); out == nil {
+		return "", "the output is not a struct or a pointer to one"
+	}
+
+	arg := "in"
+	if inPointer {
+		arg = "&in"
+	}
+	ret := fmt.Sprintf("%s(%s), nil", c.Name, arg)
+	if withError {
+		ret = fmt.Sprintf("%s(%s)", c.Name, arg)
+	}
+	return fmt.Sprintf("\t\t\t\tvar in %s\n\t\t\t\tstickyfieldsPopulate(reflect.ValueOf(&in).Elem(), 0)\n\t\t\t\treturn %s\n",
+		types.TypeString(in, qualifier), ret), ""
+}
+
+// structModel returns the named struct type of t or of the type it points to, and whether it's a pointer.
+func structModel(t types.Type) (*types.Named, bool) {
+	ptr, pointer := t.(*types.Pointer)
+	if pointer {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil, false
+	}
+	return named, pointer
+}
+
+// generatedTestFunc is the test of converters generated by GenerateTests, formatted with the test cases.
+const generatedTestFunc = `// TestStickyfieldsConverters checks that converters set their output fields from populated inputs.
+func TestStickyfieldsConverters(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func() (any, error)
+		// fields are output fields expected to be set.
+		fields []string
+	}{
+%s	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.convert()
+			if err != nil {
+				t.Fatalf("converting a populated input: %%v", err)
+			}
+			v := reflect.Indirect(reflect.ValueOf(out))
+			if !v.IsValid() {
+				t.Fatal("converting a populated input returned nil")
+			}
+			for _, field := range tt.fields {
+				if f := v.FieldByName(field); !f.IsValid() || f.IsZero() {
+					t.Errorf("%%s is not set from a populated input", field)
+				}
+			}
+		})
+	}
+}
+`
+
+// generatedPopulateFunc fills inputs of converters in tests generated by GenerateTests.
+const generatedPopulateFunc = `// stickyfieldsPopulate sets v and every exported field within it to non-zero values.
+// Pointers, slices and maps are followed up to a few levels, for recursive types.
+func stickyfieldsPopulate(v reflect.Value, depth int) {
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(1 + 1i)
+	case reflect.String:
+		v.SetString("stickyfields")
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				stickyfieldsPopulate(f, depth)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			stickyfieldsPopulate(v.Index(i), depth)
+		}
+	case reflect.Pointer:
+		if depth < 3 {
+			v.Set(reflect.New(v.Type().Elem()))
+			stickyfieldsPopulate(v.Elem(), depth+1)
+		}
+	case reflect.Slice:
+		if depth < 3 {
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			stickyfieldsPopulate(v.Index(0), depth+1)
+		}
+	case reflect.Map:
+		if depth < 3 {
+			key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			stickyfieldsPopulate(key, depth+1)
+			stickyfieldsPopulate(elem, depth+1)
+			v.Set(reflect.MakeMap(v.Type()))
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
+`
@@ -0,0 +1,64 @@
+package sf
+
+import (
+	"cmp"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+)
+
+// ConversionEdge is a converter from one named type into another within a conversion graph.
+type ConversionEdge struct {
+	From, To *types.TypeName
+	// Converter is the name of the converter, qualified by the receiver type for methods.
+	Converter string
+	// Package is the import path of the package declaring the converter.
+	Package string
+	// Pos is the position of the converter's name.
+	Pos token.Pos
+}
+
+// ConversionGraph returns the graph of which named types convert into which according to converters
+// of the results: its nodes are the models of the converters ordered by their qualified names,
+// and its edges the converters ordered by the models they convert. Results of packages analyzed
+// more than once (e.g. foo and foo.test) may be passed all the same.
+func ConversionGraph(results []*Result) (nodes []*types.TypeName, edges []ConversionEdge) {
+	byKey := make(map[string]*types.TypeName)
+	seen := make(map[token.Pos]bool)
+	for _, result := range results {
+		for _, c := range result.Converters {
+			if c.InputModel == nil || c.OutputModel == nil || seen[c.Pos] {
+				continue
+			}
+			seen[c.Pos] = true
+			byKey[typeKey(c.InputModel)] = c.InputModel
+			byKey[typeKey(c.OutputModel)] = c.OutputModel
+			edges = append(edges, ConversionEdge{
+				From:      c.InputModel,
+				To:        c.OutputModel,
+				Converter: c.qualifiedName(),
+				Package:   result.Package,
+				Pos:       c.Pos,
+			})
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(byKey)) {
+		nodes = append(nodes, byKey[key])
+	}
+	slices.SortFunc(edges, func(a, b ConversionEdge) int {
+		return cmp.Or(
+			cmp.Compare(typeKey(a.From), typeKey(b.From)),
+			cmp.Compare(typeKey(a.To), typeKey(b.To)),
+			cmp.Compare(a.Package, b.Package),
+			cmp.Compare(a.Converter, b.Converter),
+		)
+	})
+	return nodes, edges
+}
+
+// typeKey returns the fully qualified name of the named type, e.g. example.com/db.User.
+func typeKey(obj *types.TypeName) string {
+	return types.TypeString(obj.Type(), nil)
+}
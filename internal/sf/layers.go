@@ -0,0 +1,156 @@
+package sf
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Layer is a named group of packages of the architecture, e.g. db for packages matching /internal/db/.
+type Layer struct {
+	Name string
+	// Packages matches import paths of the packages of the layer.
+	Packages *regexp.Regexp
+}
+
+// Layers is a comma-separated list of layers (NAME=REGEXP) usable as a flag.Value.
+// Setting it replaces the previous value.
+type Layers []Layer
+
+func (l *Layers) String() string {
+	if l == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*l))
+	for _, layer := range *l {
+		parts = append(parts, layer.Name+"="+layer.Packages.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l *Layers) Set(value string) error {
+	var res Layers
+	for _, entry := range splitList(value) {
+		name, expr, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid layer %q: expected NAME=REGEXP", entry)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid regexp of layer %s: %w", name, err)
+		}
+		res = append(res, Layer{Name: name, Packages: re})
+	}
+	*l = res
+	return nil
+}
+
+// of returns the name of the first layer the package belongs to, or an empty string.
+func (l Layers) of(pkg *types.Package) string {
+	if pkg == nil {
+		return ""
+	}
+	for _, layer := range l {
+		if layer.Packages.MatchString(pkg.Path()) {
+			return layer.Name
+		}
+	}
+	return ""
+}
+
+// has reports whether a layer of the name is defined.
+func (l Layers) has(name string) bool {
+	for _, layer := range l {
+		if layer.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LayerRule allows converters from models of the From layer into models of the To layer,
+// declared in packages matching Packages if it's set.
+type LayerRule struct {
+	From, To string
+	Packages Regexp
+}
+
+func (r LayerRule) String() string {
+	s := r.From + "->" + r.To
+	if r.Packages.Regexp != nil {
+		s += "@" + r.Packages.String()
+	}
+	return s
+}
+
+// LayerRules is a comma-separated list of layer rules (FROM->TO or FROM->TO@REGEXP) usable as a flag.Value.
+// Setting it replaces the previous value.
+type LayerRules []LayerRule
+
+func (rs *LayerRules) String() string {
+	if rs == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*rs))
+	for _, r := range *rs {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (rs *LayerRules) Set(value string) error {
+	var res LayerRules
+	for _, entry := range splitList(value) {
+		conversion, packages, _ := strings.Cut(entry, "@")
+		from, to, ok := strings.Cut(conversion, "->")
+		if !ok || from == "" || to == "" {
+			return fmt.Errorf("invalid layer rule %q: expected FROM->TO or FROM->TO@REGEXP", entry)
+		}
+		r := LayerRule{From: from, To: to}
+		if err := r.Packages.Set(packages); err != nil {
+			return fmt.Errorf("invalid layer rule %q: %w", entry, err)
+		}
+		res = append(res, r)
+	}
+	*rs = res
+	return nil
+}
+
+// layerViolation returns why the converter breaks the layering rules (see Config.LayerRules),
+// or an empty string if it follows them. Converters between models of the same layer or of packages
+// outside of layers are unrestricted. Converters ignored entirely (see Func.ignoredFields) follow any rule.
+func layerViolation(pass *analysis.Pass, cfg *Config, fn *Func) string {
+	if _, all := fn.ignoredFields(); all {
+		return ""
+	}
+	in, out, err := Candidates(fn, pass)
+	if err != nil {
+		return ""
+	}
+	inLayer, outLayer := cfg.Layers.of(in.Type.Pkg()), cfg.Layers.of(out.Type.Pkg())
+	if inLayer == "" || outLayer == "" || inLayer == outLayer {
+		return ""
+	}
+
+	var packages []string
+	for _, rule := range cfg.LayerRules {
+		if rule.From != inLayer || rule.To != outLayer {
+			continue
+		}
+		if rule.Packages.MatchString(pass.Pkg.Path()) {
+			return ""
+		}
+		packages = append(packages, rule.Packages.String())
+	}
+	qualifier := types.RelativeTo(pass.Pkg)
+	conversion := fmt.Sprintf("%s (%s) into %s (%s)",
+		types.TypeString(in.Type.Type(), qualifier), inLayer, types.TypeString(out.Type.Type(), qualifier), outLayer)
+	if len(packages) == 0 {
+		return "converter function converts " + conversion + ": the layering rules don't allow it"
+	}
+	return fmt.Sprintf("converter function converting %s is declared outside of %s",
+		conversion, strings.Join(packages, ", "))
+}
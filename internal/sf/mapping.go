@@ -0,0 +1,105 @@
+package sf
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ConverterMapping is the mapping table of a converter: where every output field comes from
+// and where every input field goes to. Tools build documentation and review artifacts from it.
+type ConverterMapping struct {
+	// In and Out are the input and output candidates of the converter.
+	In, Out Candidate
+	// Outputs lists the exported fields of the output struct in declaration order.
+	Outputs []OutputFieldMapping
+	// Inputs lists the exported fields of the input struct in declaration order.
+	Inputs []InputFieldMapping
+}
+
+// OutputFieldMapping tells where an output field comes from.
+type OutputFieldMapping struct {
+	// Field is the name of the output field.
+	Field string
+	// Sources are the expressions written into the field as they appear in the code, e.g. "strings.TrimSpace(in.Name)".
+	// It's empty for fields not written (or written only by helpers the output comes from).
+	Sources []string
+	// From are the input fields read by Sources.
+	From []string
+	// Constant is set when every source is a constant or nil.
+	Constant bool
+}
+
+// InputFieldMapping tells where an input field goes to.
+type InputFieldMapping struct {
+	// Field is the name of the input field.
+	Field string
+	// To are the output fields whose sources read the field.
+	To []string
+	// Read is set when the field is read anywhere in the converter, including conditions
+	// and arguments of calls not flowing into any output field.
+	Read bool
+}
+
+// ExtractMapping returns the mapping table of the converter. Unlike ValidateConverter it reports
+// what the converter does rather than what it misses, so no configuration applies to it.
+func ExtractMapping(fn *Func, pass *analysis.Pass) (ConverterMapping, error) {
+	if fn.Body == nil {
+		return ConverterMapping{}, fmt.Errorf("function %q has no body", fn.Name)
+	}
+	inCand, inVar, outCand, outVar, err := candidates(fn, pass)
+	if err != nil {
+		return ConverterMapping{}, err
+	}
+
+	// The input is read through its variable, its copies and the elements of slices and maps.
+	in := variable{name: inVar, obj: fn.variable(inVar)}
+	inputs := []*UsageCollector{in.collector(fn.info, RecordFields)}
+	for _, alias := range aliasVariables(fn, in) {
+		inputs = append(inputs, alias.collector(fn.info, RecordFields))
+	}
+	if inCand.containerType == ContainerSlice || inCand.containerType == ContainerMap {
+		for _, elem := range elementVariables(fn, in) {
+			inputs = append(inputs, elem.collector(fn.info, RecordFields))
+		}
+	}
+	reads := func(n ast.Node) *UsageLookup {
+		used := NewUsageLookup()
+		for _, collector := range inputs {
+			used.AddAll(collector.Walk(n))
+		}
+		return used
+	}
+
+	mapping := ConverterMapping{
+		In:  Candidate{Var: inVar, Type: inCand.typeName, Struct: inCand.structType, Container: inCand.containerType},
+		Out: Candidate{Var: outVar, Type: outCand.typeName, Struct: outCand.structType, Container: outCand.containerType},
+	}
+
+	writes := CollectOutputWrites(fn, outVar, outCand.typeName)
+	sinks := make(map[string][]string)
+	for _, name := range exportedFields(outCand.structType) {
+		out := OutputFieldMapping{Field: name, Constant: len(writes[name]) > 0}
+		from := NewUsageLookup()
+		for _, expr := range writes[name] {
+			out.Sources = append(out.Sources, types.ExprString(expr))
+			out.Constant = out.Constant && isConstant(fn.info, expr)
+			from.AddAll(reads(expr))
+		}
+		for _, in := range exportedFields(inCand.structType) {
+			if from.LookUp(in) {
+				out.From = append(out.From, in)
+				sinks[in] = append(sinks[in], name)
+			}
+		}
+		mapping.Outputs = append(mapping.Outputs, out)
+	}
+
+	read := reads(fn.Body)
+	for _, name := range exportedFields(inCand.structType) {
+		mapping.Inputs = append(mapping.Inputs, InputFieldMapping{Field: name, To: sinks[name], Read: read.LookUp(name)})
+	}
+	return mapping, nil
+}
@@ -0,0 +1,48 @@
+package sf
+
+import (
+	"go/types"
+	"sync"
+)
+
+// memoSize is the number of packages a packageMemo keeps values of. It's meant to cover packages
+// analyzed concurrently, while long-running drivers (e.g. in watch mode) don't hold on to old ones.
+const memoSize = 16
+
+// packageMemo memoizes values computed per package for passes lacking a result of the analyzer
+// computing them (e.g. passes of other analyzers validating functions via the public API),
+// so the values are computed once per package rather than once per call.
+// Only the values of the most recently added packages are kept.
+type packageMemo[T any] struct {
+	mu      sync.Mutex
+	order   []*types.Package
+	entries map[*types.Package]*memoEntry[T]
+}
+
+type memoEntry[T any] struct {
+	once  sync.Once
+	value T
+}
+
+// get returns the value of the package, computing it the first time.
+// Concurrent calls for the same package wait for the value computed once.
+func (m *packageMemo[T]) get(pkg *types.Package, compute func() T) T {
+	m.mu.Lock()
+	e, ok := m.entries[pkg]
+	if !ok {
+		if m.entries == nil {
+			m.entries = make(map[*types.Package]*memoEntry[T])
+		}
+		e = &memoEntry[T]{}
+		m.entries[pkg] = e
+		m.order = append(m.order, pkg)
+		if len(m.order) > memoSize {
+			delete(m.entries, m.order[0])
+			m.order = m.order[1:]
+		}
+	}
+	m.mu.Unlock()
+
+	e.once.Do(func() { e.value = compute() })
+	return e.value
+}
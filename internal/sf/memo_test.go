@@ -0,0 +1,69 @@
+package sf
+
+import (
+	"fmt"
+	"go/types"
+	"sync"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func TestPackageMemo(t *testing.T) {
+	var m packageMemo[string]
+	computed := make(map[*types.Package]int)
+	var mu sync.Mutex
+	get := func(pkg *types.Package) string {
+		return m.get(pkg, func() string {
+			mu.Lock()
+			defer mu.Unlock()
+			computed[pkg]++
+			return pkg.Path()
+		})
+	}
+
+	pkgs := make([]*types.Package, memoSize+1)
+	for i := range pkgs {
+		pkgs[i] = types.NewPackage(fmt.Sprintf("example.com/p%d", i), "p")
+	}
+
+	// Concurrent calls for the same package compute the value once.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := get(pkgs[0]); got != pkgs[0].Path() {
+				t.Errorf("get() = %q, want %q", got, pkgs[0].Path())
+			}
+		}()
+	}
+	wg.Wait()
+	if computed[pkgs[0]] != 1 {
+		t.Errorf("value computed %d times, want once", computed[pkgs[0]])
+	}
+
+	// Values of the oldest packages are dropped beyond the size of the memo.
+	for _, pkg := range pkgs {
+		get(pkg)
+	}
+	get(pkgs[0])
+	get(pkgs[len(pkgs)-1])
+	if computed[pkgs[0]] != 2 {
+		t.Errorf("value of the oldest package computed %d times, want twice", computed[pkgs[0]])
+	}
+	if computed[pkgs[len(pkgs)-1]] != 1 {
+		t.Errorf("value of the latest package computed %d times, want once", computed[pkgs[len(pkgs)-1]])
+	}
+}
+
+func TestFactsOfWithoutFactsAnalyzer(t *testing.T) {
+	pass := &analysis.Pass{
+		Pkg:      types.NewPackage("example.com/facts", "facts"),
+		ResultOf: map[*analysis.Analyzer]any{},
+	}
+	// Facts and interned field indexes of structs are shared by all validations of the package.
+	if factsOf(pass) != factsOf(pass) {
+		t.Error("facts of the package collected again")
+	}
+}
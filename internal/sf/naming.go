@@ -0,0 +1,93 @@
+package sf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Placeholders of converter naming templates (see Config.Naming).
+const (
+	namingIn     = "{In}"
+	namingOut    = "{Out}"
+	namingInPkg  = "{InPkg}"
+	namingOutPkg = "{OutPkg}"
+)
+
+// namingViolation returns why the name or the package of the converter breaks the naming convention
+// (see Config.Naming and Config.ConverterPackages), or an empty string if it follows it.
+// Function literals are named after variables if at all, so only their package is checked.
+// Converters ignored entirely (see Func.ignoredFields) follow any convention.
+func namingViolation(pass *analysis.Pass, cfg *Config, fn *Func) string {
+	if _, all := fn.ignoredFields(); all {
+		return ""
+	}
+	if cfg.ConverterPackages.Regexp != nil && !cfg.ConverterPackages.MatchString(pass.Pkg.Path()) {
+		return fmt.Sprintf("converter function %s is declared outside of converter packages (%s)",
+			fn.Name, cfg.ConverterPackages.Regexp)
+	}
+	if fn.Decl == nil || len(cfg.Naming) == 0 {
+		return ""
+	}
+
+	in, out, err := Candidates(fn, pass)
+	if err != nil {
+		return ""
+	}
+	for _, template := range cfg.Naming {
+		if namingPattern(template, in, out).MatchString(fn.Name) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("converter function %s does not follow the naming convention: %s",
+		fn.Name, strings.Join(expandNaming(cfg.Naming, in, out), ", "))
+}
+
+// namingPattern returns the regular expression of converter names the template stands for.
+// Names of models of collections may be pluralized (e.g. ToUserRows for []model.User).
+func namingPattern(template string, in, out Candidate) *regexp.Regexp {
+	replacer := strings.NewReplacer(
+		regexp.QuoteMeta(namingIn), modelPattern(in),
+		regexp.QuoteMeta(namingOut), modelPattern(out),
+		regexp.QuoteMeta(namingInPkg), regexp.QuoteMeta(packageTitle(in)),
+		regexp.QuoteMeta(namingOutPkg), regexp.QuoteMeta(packageTitle(out)),
+	)
+	return regexp.MustCompile("^" + replacer.Replace(regexp.QuoteMeta(template)) + "$")
+}
+
+// modelPattern returns the regular expression of the model's name within converter names.
+func modelPattern(c Candidate) string {
+	name := regexp.QuoteMeta(c.Type.Name())
+	if c.Container == ContainerSlice || c.Container == ContainerMap {
+		name += "(?:s|es)?"
+	}
+	return name
+}
+
+// packageTitle returns the name of the model's package starting with an upper case letter (e.g. Db).
+func packageTitle(c Candidate) string {
+	if c.Type.Pkg() == nil {
+		return ""
+	}
+	name := []rune(c.Type.Pkg().Name())
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
+
+// expandNaming returns the templates with placeholders replaced by names of the models, for messages.
+func expandNaming(templates []string, in, out Candidate) []string {
+	replacer := strings.NewReplacer(
+		namingIn, in.Type.Name(),
+		namingOut, out.Type.Name(),
+		namingInPkg, packageTitle(in),
+		namingOutPkg, packageTitle(out),
+	)
+	expanded := make([]string, len(templates))
+	for i, template := range templates {
+		expanded[i] = replacer.Replace(template)
+	}
+	return expanded
+}
@@ -0,0 +1,74 @@
+package sf
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// nilResultDerefs returns dereferences of pointer named results of the converter preceding every assignment
+// of the result in the source, e.g. result.ID = ... or _ = result.ID before result = &db.User{...}:
+// the result is still nil there, so they panic. Bodies of function literals run at other times
+// (e.g. deferred ones after the assignment) and are left out, as are converters ignored entirely
+// (see Func.ignoredFields).
+func nilResultDerefs(fn *Func) []ast.Expr {
+	if _, all := fn.ignoredFields(); all {
+		return nil
+	}
+	if fn.Body == nil || fn.Type.Results == nil {
+		return nil
+	}
+
+	var derefs []ast.Expr
+	for _, field := range fn.Type.Results.List {
+		for _, name := range field.Names {
+			result := fn.object(name)
+			if result == nil {
+				continue
+			}
+			if _, ok := result.Type().(*types.Pointer); !ok {
+				continue
+			}
+			isResult := func(expr ast.Expr) bool {
+				id, ok := ast.Unparen(expr).(*ast.Ident)
+				return ok && fn.object(id) == result
+			}
+
+			// The first assignment of the result, including the ones through its address (e.g. json.Unmarshal).
+			assigned := token.Pos(-1)
+			var candidates []ast.Expr
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				switch x := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.AssignStmt:
+					for _, lhs := range x.Lhs {
+						// Right-hand sides are evaluated before the assignment.
+						if isResult(lhs) && (assigned < 0 || x.End() < assigned) {
+							assigned = x.End()
+						}
+					}
+				case *ast.UnaryExpr:
+					if x.Op == token.AND && isResult(x.X) && (assigned < 0 || x.Pos() < assigned) {
+						assigned = x.Pos()
+					}
+				case *ast.SelectorExpr:
+					if selection, ok := fn.info.Selections[x]; ok && selection.Kind() == types.FieldVal && isResult(x.X) {
+						candidates = append(candidates, x)
+					}
+				case *ast.StarExpr:
+					if isResult(x.X) {
+						candidates = append(candidates, x)
+					}
+				}
+				return true
+			})
+			for _, expr := range candidates {
+				if assigned < 0 || expr.Pos() < assigned {
+					derefs = append(derefs, expr)
+				}
+			}
+		}
+	}
+	return derefs
+}
@@ -0,0 +1,41 @@
+package sf
+
+import (
+	"go/types"
+	"reflect"
+)
+
+// ormProfiles maps names of supported ORM code generators to functions
+// returning machinery fields (relations, query internals) of models they generate.
+var ormProfiles = map[string]func(obj *types.TypeName, st *types.Struct) []string{
+	"ent":       entMachineryFields,
+	"sqlboiler": sqlboilerMachineryFields,
+}
+
+// entMachineryFields returns the Edges field of ent-generated models:
+// it holds eagerly loaded relations and is of the generated <Model>Edges type.
+func entMachineryFields(obj *types.TypeName, st *types.Struct) []string {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Name() != "Edges" {
+			continue
+		}
+		if named, ok := field.Type().(*types.Named); ok && named.Obj().Name() == obj.Name()+"Edges" {
+			return []string{field.Name()}
+		}
+	}
+	return nil
+}
+
+// sqlboilerMachineryFields returns relationship fields (R, L) of sqlboiler-generated models.
+// Those are always tagged with boil:"-".
+func sqlboilerMachineryFields(_ *types.TypeName, st *types.Struct) []string {
+	var res []string
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if field.Exported() && reflect.StructTag(st.Tag(i)).Get("boil") == "-" {
+			res = append(res, field.Name())
+		}
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+package sf
+
+import (
+	"bytes"
+	"go/ast"
+	"runtime"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// funcCheck is the check of a function of the package, run concurrently with checks of other functions.
+type funcCheck struct {
+	fn       *Func
+	filename string
+
+	// log buffers messages of the check, written in the order of functions once all checks are done.
+	log bytes.Buffer
+	// converter is set if the function is possibly a converter (see IsPossibleConverter).
+	converter bool
+	// result is the validation result of the converter, unless validating it failed with err.
+	result ConverterValidationResult
+	err    error
+}
+
+// inspectFuncs returns checks of function declarations and literals of the files in the order of the source.
+func inspectFuncs(pass *analysis.Pass, in *inspector.Inspector, registries []*registry, files map[*ast.File]bool) []*funcCheck {
+	litNames := literalNames(in)
+
+	var (
+		checks   []*funcCheck
+		filename string
+		nolint   map[int]bool
+	)
+	filter := []ast.Node{(*ast.File)(nil), (*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}
+	in.Nodes(filter, func(n ast.Node, push bool) bool {
+		if !push {
+			return true
+		}
+		var fn *Func
+		switch x := n.(type) {
+		case *ast.File:
+			// Functions of the file follow it, unless the file is skipped.
+			if !files[x] {
+				return false
+			}
+			filename = pass.Fset.Position(x.Pos()).Filename
+			nolint = nolintLines(pass.Fset, x)
+			return true
+		case *ast.FuncDecl:
+			fn = NewFuncFromDecl(pass, x)
+		case *ast.FuncLit:
+			fn = NewFuncFromLit(pass, x, litNames[x])
+		}
+		fn.Registered = isRegistered(pass, registries, fn)
+		fn.Nolint = fn.suppressed(pass.Fset, nolint)
+		checks = append(checks, &funcCheck{fn: fn, filename: filename})
+		return true
+	})
+	return checks
+}
+
+// run validates the function if it's possibly a converter.
+func (c *funcCheck) run(pass *analysis.Pass, cfg *Config) {
+	if cfg.Output != nil {
+		buffered := *cfg
+		buffered.Output = &c.log
+		cfg = &buffered
+	}
+
+	if !IsPossibleConverter(c.fn, pass, cfg) {
+		return
+	}
+	c.converter = true
+	c.result, c.err = ValidateConverter(c.fn, pass, cfg)
+	if c.err != nil {
+		cfg.logf(VerbosityNotices, "%s: ignoring %s: %v", pass.Fset.Position(c.fn.NamePos), c.fn.Name, c.err)
+	}
+}
+
+// parallel calls f with indexes from 0 to n-1 on as many goroutines as there are CPUs usable at once
+// and waits for all calls to return.
+func parallel(n int, f func(i int)) {
+	workers := min(n, runtime.GOMAXPROCS(0))
+	if workers <= 1 {
+		for i := range n {
+			f(i)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
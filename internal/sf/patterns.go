@@ -0,0 +1,185 @@
+package sf
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ReflectiveCopy modes define how converters relying on reflective copy helpers are treated.
+const (
+	// ReflectiveCopyUnknown reports such converters with a distinct "coverage unknown" diagnostic.
+	ReflectiveCopyUnknown = "unknown"
+	// ReflectiveCopyCovered considers all fields of such converters covered.
+	ReflectiveCopyCovered = "covered"
+)
+
+// JSON functions used to implement converters via a marshal/unmarshal round-trip.
+var (
+	jsonMarshalFuncs   = []string{"encoding/json.Marshal", "encoding/json.MarshalIndent"}
+	jsonUnmarshalFuncs = []string{"encoding/json.Unmarshal"}
+)
+
+// isJSONRoundTrip reports whether the body marshals the input variable to JSON and unmarshals the result back.
+func isJSONRoundTrip(pass *analysis.Pass, body ast.Node, in variable) bool {
+	if !callsWithVar(pass, body, jsonMarshalFuncs, in) {
+		return false
+	}
+	call, _ := findCall(pass, body, jsonUnmarshalFuncs)
+	return call != nil
+}
+
+// funcRef returns the reference of a function in "import/path.Func" form
+// or "import/path.Type.Method" form for methods.
+func funcRef(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+
+	sig, _ := fn.Type().(*types.Signature)
+	if sig != nil && sig.Recv() != nil {
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if named, ok := recv.(*types.Named); ok {
+			return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+
+	return fn.Pkg().Path() + "." + fn.Name()
+}
+
+// shortFuncName returns a function name qualified by its package name (e.g. copier.Copy).
+func shortFuncName(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
+
+// findCall returns the first static call within n to any of the referenced functions.
+func findCall(pass *analysis.Pass, n ast.Node, refs []string) (*ast.CallExpr, *types.Func) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	var (
+		found  *ast.CallExpr
+		callee *types.Func
+	)
+	ast.Inspect(n, func(node ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if fn := typeutil.StaticCallee(pass.TypesInfo, call); fn != nil && slices.Contains(refs, funcRef(fn)) {
+			found, callee = call, fn
+			return false
+		}
+		return true
+	})
+	return found, callee
+}
+
+// findCalls returns all static calls within n to any of the referenced functions.
+func findCalls(pass *analysis.Pass, n ast.Node, refs []string) []ast.Node {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var calls []ast.Node
+	ast.Inspect(n, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if fn := typeutil.StaticCallee(pass.TypesInfo, call); fn != nil && slices.Contains(refs, funcRef(fn)) {
+			calls = append(calls, call)
+			return false
+		}
+		return true
+	})
+	return calls
+}
+
+// callsWithVar reports whether n contains a static call to any of the referenced functions
+// that takes the variable (or its address or dereference) as an argument.
+func callsWithVar(pass *analysis.Pass, n ast.Node, refs []string, v variable) bool {
+	_, callee := findCallWithVar(pass, n, refs, v)
+	return callee != nil
+}
+
+// findCallWithVar returns the first static call within n to any of the referenced functions
+// that takes the variable (or its address or dereference) as an argument.
+// The variable is matched by its object, so variables shadowing it are not taken for it.
+func findCallWithVar(pass *analysis.Pass, n ast.Node, refs []string, v variable) (*ast.CallExpr, *types.Func) {
+	if len(refs) == 0 || v.name == "" {
+		return nil, nil
+	}
+
+	var (
+		found  *ast.CallExpr
+		callee *types.Func
+	)
+	ast.Inspect(n, func(node ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := typeutil.StaticCallee(pass.TypesInfo, call)
+		if fn == nil || !slices.Contains(refs, funcRef(fn)) {
+			return true
+		}
+		for _, arg := range call.Args {
+			if isVarRef(pass.TypesInfo, arg, v) {
+				found, callee = call, fn
+				return false
+			}
+		}
+		return true
+	})
+	return found, callee
+}
+
+// isVarRef checks if expr is the variable itself, its address or its dereference.
+func isVarRef(info *types.Info, expr ast.Expr, v variable) bool {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return v.matches(info, x)
+	case *ast.UnaryExpr:
+		return isVarRef(info, x.X, v)
+	case *ast.StarExpr:
+		return isVarRef(info, x.X, v)
+	case *ast.ParenExpr:
+		return isVarRef(info, x.X, v)
+	}
+	return false
+}
+
+// sharedFields returns names of exported fields present in both structs.
+func sharedFields(a, b *types.Struct) []string {
+	var res []string
+	for i := 0; i < a.NumFields(); i++ {
+		fa := a.Field(i)
+		if !fa.Exported() {
+			continue
+		}
+		for j := 0; j < b.NumFields(); j++ {
+			if b.Field(j).Name() == fa.Name() {
+				res = append(res, fa.Name())
+				break
+			}
+		}
+	}
+	return res
+}
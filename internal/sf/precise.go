@@ -0,0 +1,270 @@
+package sf
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+// maxPreciseDepth limits how deep calls of the package's functions are followed in the precise mode.
+const maxPreciseDepth = 3
+
+// ssaFuncs looks up SSA functions of the package for the precise mode (see Config.Precise). The SSA form
+// is the result of buildssa.Analyzer when the analyzer requires it (see NewAnalyzer); otherwise, it's built
+// the first time a converter asks for it, so packages without converters don't pay for it.
+type ssaFuncs struct {
+	pass  *analysis.Pass
+	once  sync.Once
+	funcs map[ast.Node]*ssa.Function
+}
+
+func newSSAFuncs(pass *analysis.Pass) *ssaFuncs {
+	return &ssaFuncs{pass: pass}
+}
+
+// builtSSAFuncs memoizes SSA functions built for passes without a result of buildssa.Analyzer,
+// e.g. for ValidateFunc called for every function of a package.
+var builtSSAFuncs packageMemo[map[ast.Node]*ssa.Function]
+
+// function returns the SSA function of the declaration or literal, or nil if there is none.
+func (s *ssaFuncs) function(fn *Func) *ssa.Function {
+	s.once.Do(func() {
+		if result, ok := s.pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA); ok {
+			s.funcs = srcFuncs(result)
+			return
+		}
+		s.funcs = builtSSAFuncs.get(s.pass.Pkg, func() map[ast.Node]*ssa.Function {
+			result, err := buildssa.Analyzer.Run(s.pass)
+			if err != nil {
+				return nil
+			}
+			return srcFuncs(result.(*buildssa.SSA))
+		})
+	})
+	if fn.Decl != nil {
+		return s.funcs[fn.Decl]
+	}
+	return s.funcs[fn.Lit]
+}
+
+// srcFuncs maps declarations and literals of the package to their SSA functions.
+func srcFuncs(result *buildssa.SSA) map[ast.Node]*ssa.Function {
+	funcs := make(map[ast.Node]*ssa.Function, len(result.SrcFuncs))
+	for _, f := range result.SrcFuncs {
+		funcs[f.Syntax()] = f
+	}
+	return funcs
+}
+
+// preciseInputFields returns fields of the input struct st read from the named parameter of f,
+// following its value through local variables, conditionals, containers and calls of the package's functions.
+func preciseInputFields(f *ssa.Function, param string, st *types.Struct) *UsageLookup {
+	used := NewUsageLookup()
+	for _, p := range f.Params {
+		if p.Name() == param {
+			flow := &inputFlow{st: st, used: used, visited: make(map[ssa.Value]bool)}
+			flow.value(p, 0)
+		}
+	}
+	return used
+}
+
+// inputFlow follows the input value forward along def-use chains.
+type inputFlow struct {
+	st      *types.Struct
+	used    *UsageLookup
+	visited map[ssa.Value]bool
+}
+
+func (fl *inputFlow) value(v ssa.Value, depth int) {
+	if fl.visited[v] {
+		return
+	}
+	fl.visited[v] = true
+
+	refs := v.Referrers()
+	if refs == nil {
+		return
+	}
+	for _, instr := range *refs {
+		switch x := instr.(type) {
+		case *ssa.FieldAddr:
+			fl.field(x.X.Type(), x.Field)
+		case *ssa.Field:
+			fl.field(x.X.Type(), x.Field)
+		case *ssa.UnOp:
+			if x.Op == token.MUL {
+				fl.value(x, depth)
+			}
+		case *ssa.Phi, *ssa.ChangeType, *ssa.IndexAddr, *ssa.Index, *ssa.Lookup,
+			*ssa.Range, *ssa.Next, *ssa.Extract:
+			fl.value(x.(ssa.Value), depth)
+		case *ssa.Store:
+			// Copies into local variables, e.g. src := in.
+			if alloc, ok := x.Addr.(*ssa.Alloc); ok && x.Val == v {
+				fl.value(alloc, depth)
+			}
+		case ssa.CallInstruction:
+			callee := x.Common().StaticCallee()
+			if callee == nil || callee.Blocks == nil || depth >= maxPreciseDepth {
+				continue
+			}
+			for i, arg := range x.Common().Args {
+				if arg == v && i < len(callee.Params) {
+					fl.value(callee.Params[i], depth+1)
+				}
+			}
+		}
+	}
+}
+
+// field records the field of the (pointer to) struct type t if it's the input struct.
+func (fl *inputFlow) field(t types.Type, i int) {
+	if st := derefStruct(t); st != nil && types.Identical(st, fl.st) {
+		fl.used.Add(st.Field(i).Name())
+	}
+}
+
+// preciseOutputFields returns fields of the output struct st written into the value returned by f
+// as its result at the given index, on every return but error paths and zero values.
+// Values are followed backward through local variables, conditionals and calls of the package's functions.
+func preciseOutputFields(f *ssa.Function, result int, st *types.Struct) *UsageLookup {
+	flow := &outputFlow{st: st, visited: make(map[ssa.Value]bool)}
+	return flow.returned(f, result, 0)
+}
+
+// outputFlow follows returned values backward along def-use chains.
+type outputFlow struct {
+	st      *types.Struct
+	visited map[ssa.Value]bool
+}
+
+// returned returns fields written into the result on every return of f, or nil if nothing is known.
+func (fl *outputFlow) returned(f *ssa.Function, result int, depth int) *UsageLookup {
+	var sets []*UsageLookup
+	for _, b := range f.Blocks {
+		ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+		if !ok || result >= len(ret.Results) || isSSAErrorReturn(ret) {
+			continue
+		}
+		if written := fl.written(ret.Results[result], depth); written != nil {
+			sets = append(sets, written)
+		}
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	return intersectUsage(sets...)
+}
+
+// written returns fields written into the value v, or nil if nothing is known (e.g. for zero values).
+func (fl *outputFlow) written(v ssa.Value, depth int) *UsageLookup {
+	if fl.visited[v] {
+		return nil
+	}
+	fl.visited[v] = true
+	defer delete(fl.visited, v)
+
+	switch x := v.(type) {
+	case *ssa.Alloc:
+		ul := NewUsageLookup()
+		for _, instr := range *x.Referrers() {
+			switch ref := instr.(type) {
+			case *ssa.FieldAddr:
+				if st := derefStruct(ref.X.Type()); st != nil && types.Identical(st, fl.st) {
+					ul.Add(st.Field(ref.Field).Name())
+				}
+			case *ssa.Store:
+				// Assignments of whole values, e.g. out = base(in).
+				if ref.Addr == x {
+					ul.AddAll(fl.written(ref.Val, depth))
+				}
+			}
+		}
+		return ul
+	case *ssa.UnOp:
+		if x.Op == token.MUL {
+			return fl.written(x.X, depth)
+		}
+	case *ssa.ChangeType:
+		return fl.written(x.X, depth)
+	case *ssa.MakeInterface:
+		return fl.written(x.X, depth)
+	case *ssa.Phi:
+		var sets []*UsageLookup
+		for _, edge := range x.Edges {
+			if written := fl.written(edge, depth); written != nil {
+				sets = append(sets, written)
+			}
+		}
+		if len(sets) == 0 {
+			return nil
+		}
+		return intersectUsage(sets...)
+	case *ssa.Call:
+		return fl.call(x, 0, depth)
+	case *ssa.Extract:
+		if call, ok := x.Tuple.(*ssa.Call); ok {
+			return fl.call(call, x.Index, depth)
+		}
+	case *ssa.Const:
+		return nil
+	}
+	return NewUsageLookup()
+}
+
+// call returns fields written into the result of a helper of the package returning the output.
+func (fl *outputFlow) call(call *ssa.Call, result int, depth int) *UsageLookup {
+	callee := call.Common().StaticCallee()
+	if callee == nil || callee.Blocks == nil || depth >= maxPreciseDepth {
+		return NewUsageLookup()
+	}
+	if written := fl.returned(callee, result, depth+1); written != nil {
+		return written
+	}
+	return NewUsageLookup()
+}
+
+// isSSAErrorReturn reports whether the return returns a non-nil error as its last result.
+func isSSAErrorReturn(ret *ssa.Return) bool {
+	if len(ret.Results) == 0 {
+		return false
+	}
+	last := ret.Results[len(ret.Results)-1]
+	if !types.Identical(last.Type(), types.Universe.Lookup("error").Type()) {
+		return false
+	}
+	c, ok := last.(*ssa.Const)
+	return !ok || !c.IsNil()
+}
+
+// resultIndex returns the index of the result of the signature holding the output struct st:
+// the struct itself, a pointer to it, or an interface implemented by it. It returns -1 if there is none.
+func resultIndex(sig *types.Signature, st *types.Struct) int {
+	for i := 0; i < sig.Results().Len(); i++ {
+		if s := derefStruct(sig.Results().At(i).Type()); s != nil && types.Identical(s, st) {
+			return i
+		}
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		t := sig.Results().At(i).Type()
+		if types.IsInterface(t) && !types.Identical(t, types.Universe.Lookup("error").Type()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// derefStruct returns the struct type behind t or a pointer to it, or nil if there is none.
+func derefStruct(t types.Type) *types.Struct {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, _ := t.Underlying().(*types.Struct)
+	return st
+}
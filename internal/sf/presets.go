@@ -0,0 +1,205 @@
+package sf
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Modes are named presets of the analyzer configuration.
+const (
+	// ModeStrict checks every function with a receiver or not, deprecated fields,
+	// converter registries, does not count discarded reads and reports hardcoded, swapped and overwritten
+	// fields as well as converters whose coverage cannot be determined.
+	ModeStrict = "strict"
+	// ModeDefault is the configuration returned by DefaultConfig.
+	ModeDefault = "default"
+	// ModeLenient checks only unwritten output fields of exported converters of non-trivial structs,
+	// trusting reflective copy helpers and ignoring oneof variants.
+	ModeLenient = "lenient"
+)
+
+// presets maps modes to values of the flags they set.
+// Every preset lists the same flags, so switching modes never leaves settings of another one behind.
+var presets = map[string]map[string]string{
+	ModeStrict: {
+		"include-methods":         "true",
+		"include-deprecated":      "true",
+		"exported-only":           "false",
+		"check":                   CheckBoth,
+		"check-oneofs":            "true",
+		"min-fields":              "0",
+		"reflective-copy":         ReflectiveCopyUnknown,
+		"report-json-roundtrip":   "true",
+		"check-registries":        "true",
+		"check-round-trips":       "true",
+		"check-reverse":           "false",
+		"check-naming":            "false",
+		"check-duplicates":        "true",
+		"check-layers":            "false",
+		"check-purity":            "false",
+		"check-nil-results":       "true",
+		"check-errors":            "false",
+		"ignore-blank-reads":      "true",
+		"report-hardcoded":        "true",
+		"report-swapped":          "true",
+		"report-duplicate-writes": "true",
+	},
+	ModeDefault: {
+		"include-methods":         "false",
+		"include-deprecated":      "false",
+		"exported-only":           "false",
+		"check":                   CheckBoth,
+		"check-oneofs":            "true",
+		"min-fields":              "0",
+		"reflective-copy":         ReflectiveCopyUnknown,
+		"report-json-roundtrip":   "false",
+		"check-registries":        "false",
+		"check-round-trips":       "false",
+		"check-reverse":           "false",
+		"check-naming":            "false",
+		"check-duplicates":        "false",
+		"check-layers":            "false",
+		"check-purity":            "false",
+		"check-nil-results":       "false",
+		"check-errors":            "false",
+		"ignore-blank-reads":      "false",
+		"report-hardcoded":        "false",
+		"report-swapped":          "false",
+		"report-duplicate-writes": "false",
+	},
+	ModeLenient: {
+		"include-methods":         "false",
+		"include-deprecated":      "false",
+		"exported-only":           "true",
+		"check":                   CheckOutput,
+		"check-oneofs":            "false",
+		"min-fields":              "3",
+		"reflective-copy":         ReflectiveCopyCovered,
+		"report-json-roundtrip":   "false",
+		"check-registries":        "false",
+		"check-round-trips":       "false",
+		"check-reverse":           "false",
+		"check-naming":            "false",
+		"check-duplicates":        "false",
+		"check-layers":            "false",
+		"check-purity":            "false",
+		"check-nil-results":       "false",
+		"check-errors":            "false",
+		"ignore-blank-reads":      "false",
+		"report-hardcoded":        "false",
+		"report-swapped":          "false",
+		"report-duplicate-writes": "false",
+	},
+}
+
+// modeValue is the flag.Value of the -mode flag. Setting it applies the preset
+// to all flags of the set except for those given explicitly before it.
+// Flags given after -mode override the preset naturally.
+type modeValue struct {
+	cfg *Config
+	fs  *flag.FlagSet
+}
+
+func (m *modeValue) String() string {
+	if m == nil || m.cfg == nil {
+		return ""
+	}
+	return m.cfg.Mode
+}
+
+func (m *modeValue) Set(value string) error {
+	preset, ok := presets[value]
+	if !ok {
+		return fmt.Errorf("unknown mode %q: expected %q, %q or %q", value, ModeStrict, ModeDefault, ModeLenient)
+	}
+
+	for name, v := range preset {
+		f := m.fs.Lookup(name).Value.(presetValue).preset()
+		if f.explicit {
+			continue
+		}
+		if err := f.Value.Set(v); err != nil {
+			return fmt.Errorf("applying mode %q: %w", value, err)
+		}
+	}
+	m.cfg.Mode = value
+	return nil
+}
+
+// presetFlag is the flag.Value of a flag set by presets. It records whether the flag was given
+// explicitly: drivers like unitchecker parse flags of analyzers on flag sets of their own
+// (e.g. -stickyfields.report-hardcoded of go vet), so flag.FlagSet.Visit of the analyzer's one can't tell.
+type presetFlag struct {
+	flag.Value
+	explicit bool
+}
+
+// presetValue is implemented by presetFlag and its variants for boolean and integer flags,
+// whose zero values print like the ones of the wrapped flags (see flag.PrintDefaults).
+type presetValue interface {
+	flag.Value
+	preset() *presetFlag
+}
+
+type (
+	presetBoolFlag struct{ presetFlag }
+	presetIntFlag  struct{ presetFlag }
+)
+
+// wrapPresetFlags wraps the values of flags set by presets, see presetFlag.
+func wrapPresetFlags(fs *flag.FlagSet) {
+	for name := range presets[ModeDefault] {
+		f := fs.Lookup(name)
+		wrapped := presetFlag{Value: f.Value}
+		switch f.Value.(flag.Getter).Get().(type) {
+		case bool:
+			f.Value = &presetBoolFlag{wrapped}
+		case int:
+			f.Value = &presetIntFlag{wrapped}
+		default:
+			f.Value = &wrapped
+		}
+	}
+}
+
+func (f *presetFlag) preset() *presetFlag {
+	return f
+}
+
+func (f *presetFlag) String() string {
+	if f == nil || f.Value == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+func (f *presetBoolFlag) String() string {
+	if f == nil || f.Value == nil {
+		return "false"
+	}
+	return f.Value.String()
+}
+
+func (f *presetIntFlag) String() string {
+	if f == nil || f.Value == nil {
+		return "0"
+	}
+	return f.Value.String()
+}
+
+func (f *presetFlag) Set(value string) error {
+	if err := f.Value.Set(value); err != nil {
+		return err
+	}
+	f.explicit = true
+	return nil
+}
+
+// IsBoolFlag keeps boolean flags usable without a value (e.g. -report-hardcoded).
+func (f *presetBoolFlag) IsBoolFlag() bool {
+	return true
+}
+
+func (f *presetFlag) Get() any {
+	return f.Value.(flag.Getter).Get()
+}
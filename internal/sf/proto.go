@@ -0,0 +1,166 @@
+package sf
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// wellKnownTypesPath is the import path prefix of protobuf well-known types (timestamppb, durationpb, etc).
+const wellKnownTypesPath = "google.golang.org/protobuf/types/known/"
+
+// isProtoMessage reports whether the named type is a protoc-generated message.
+// Messages are detected by the generated ProtoReflect (or legacy ProtoMessage) method
+// or by being declared in a *.pb.go file.
+func isProtoMessage(pass *analysis.Pass, obj *types.TypeName) bool {
+	if obj == nil {
+		return false
+	}
+
+	ptr := types.NewPointer(obj.Type())
+	for _, method := range []string{"ProtoReflect", "ProtoMessage"} {
+		if m, _, _ := types.LookupFieldOrMethod(ptr, false, obj.Pkg(), method); m != nil {
+			if _, ok := m.(*types.Func); ok {
+				return true
+			}
+		}
+	}
+
+	return strings.HasSuffix(pass.Fset.Position(obj.Pos()).Filename, ".pb.go")
+}
+
+// isWellKnownType reports whether the named type is one of protobuf well-known types.
+// Those are helpers (Timestamp, Duration, Any, ...) rather than models worth converting.
+func isWellKnownType(obj *types.TypeName) bool {
+	return obj != nil && obj.Pkg() != nil && strings.HasPrefix(obj.Pkg().Path(), wellKnownTypesPath)
+}
+
+// protoSkippedFields returns exported fields of a proto message that converters are not expected to set:
+// legacy XXX_ internals and oneof wrapper fields (which can hold only one variant at once).
+func protoSkippedFields(st *types.Struct) *UsageLookup {
+	ul := NewUsageLookup()
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if strings.HasPrefix(field.Name(), "XXX_") || reflect.StructTag(st.Tag(i)).Get("protobuf_oneof") != "" {
+			ul.Add(field.Name())
+		}
+	}
+	return ul
+}
+
+// oneofField describes a oneof field of a proto message along with the variant types it can hold.
+type oneofField struct {
+	name     string
+	variants []*types.TypeName
+}
+
+// oneofFields returns oneof fields of the proto message declared by obj.
+// Variants are looked up in the message's package as types implementing the oneof interface.
+func oneofFields(obj *types.TypeName) []oneofField {
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok || obj.Pkg() == nil {
+		return nil
+	}
+
+	var res []oneofField
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if reflect.StructTag(st.Tag(i)).Get("protobuf_oneof") == "" {
+			continue
+		}
+		iface, ok := field.Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+
+		of := oneofField{name: field.Name()}
+		scope := obj.Pkg().Scope()
+		for _, name := range scope.Names() {
+			tn, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || types.IsInterface(tn.Type()) {
+				continue
+			}
+			if types.Implements(types.NewPointer(tn.Type()), iface) {
+				of.variants = append(of.variants, tn)
+			}
+		}
+		res = append(res, of)
+	}
+	return res
+}
+
+// unhandledOneofCases returns oneof variants of the proto message obj that are not handled by fn.
+// A variant is handled when the body mentions its type (type switch case, type assertion, composite literal)
+// or calls the variant's generated getter on varName (e.g. in.GetEmail()).
+func unhandledOneofCases(pass *analysis.Pass, fn *Func, obj *types.TypeName, varName string) []string {
+	oneofs := oneofFields(obj)
+	if len(oneofs) == 0 {
+		return nil
+	}
+
+	mentioned := make(map[*types.TypeName]struct{})
+	mention := func(expr ast.Expr) {
+		t := pass.TypesInfo.TypeOf(expr)
+		if t == nil {
+			return
+		}
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := t.(*types.Named); ok {
+			mentioned[named.Obj()] = struct{}{}
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CaseClause:
+			for _, expr := range x.List {
+				mention(expr)
+			}
+		case *ast.TypeAssertExpr:
+			if x.Type != nil {
+				mention(x.Type)
+			}
+		case *ast.CompositeLit:
+			mention(x)
+		}
+		return true
+	})
+
+	var methodsUsed *UsageLookup
+	if varName != "" {
+		methodsUsed = fn.usageCollector(varName, RecordMethods).Walk(fn.Body)
+	}
+
+	var unhandled []string
+	for _, of := range oneofs {
+		label := of.name
+		if varName != "" {
+			label = varName + "." + of.name
+		}
+		for _, variant := range of.variants {
+			if _, ok := mentioned[variant]; ok || handledByGetter(variant, methodsUsed) {
+				continue
+			}
+			unhandled = append(unhandled, label+": "+variant.Name())
+		}
+	}
+	return unhandled
+}
+
+// handledByGetter checks if any field getter of the oneof variant was called.
+func handledByGetter(variant *types.TypeName, methodsUsed *UsageLookup) bool {
+	st, ok := variant.Type().Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if methodsUsed.LookUp("Get" + st.Field(i).Name()) {
+			return true
+		}
+	}
+	return false
+}
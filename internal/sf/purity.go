@@ -0,0 +1,139 @@
+package sf
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// inputMutation is a statement of a converter mutating its input.
+type inputMutation struct {
+	pos     token.Pos
+	message string
+}
+
+// inputMutations returns statements of the converter mutating its input: writes to fields or elements
+// of the input (e.g. u.Name = ..., users[i].Age++), calls of methods with pointer receivers on it
+// (e.g. u.Normalize()) and deletions from maps or clearings of it. Reassignments of the input variable
+// itself and writes to copies of it (e.g. u.Name = ... of an input passed by value) change nothing
+// of the caller's and are left out. Converters ignored entirely (see Func.ignoredFields) may mutate it.
+func inputMutations(pass *analysis.Pass, fn *Func) []inputMutation {
+	if _, all := fn.ignoredFields(); all {
+		return nil
+	}
+	_, inVar, _, _, err := candidates(fn, pass)
+	if err != nil || inVar == "" || fn.Body == nil {
+		return nil
+	}
+	input := fn.variable(inVar)
+	if input == nil {
+		return nil
+	}
+	// rooted reports whether the expression is the input or a part of it.
+	rooted := func(expr ast.Expr) bool {
+		for {
+			switch x := ast.Unparen(expr).(type) {
+			case *ast.Ident:
+				return fn.object(x) == input
+			case *ast.SelectorExpr:
+				expr = x.X
+			case *ast.IndexExpr:
+				expr = x.X
+			case *ast.StarExpr:
+				expr = x.X
+			default:
+				return false
+			}
+		}
+	}
+	// part reports whether the expression is a part of the input other than the input variable itself.
+	part := func(expr ast.Expr) bool {
+		_, ident := ast.Unparen(expr).(*ast.Ident)
+		return !ident && rooted(expr)
+	}
+
+	// reference reports whether values of the expression's type refer to the caller's data.
+	reference := func(expr ast.Expr) bool {
+		switch pass.TypesInfo.TypeOf(expr).Underlying().(type) {
+		case *types.Pointer, *types.Slice, *types.Map:
+			return true
+		}
+		return false
+	}
+	// shared reports whether writes through the part of the input reach the caller's data:
+	// whether the input or any part of it on the way is a pointer, a slice or a map.
+	shared := func(expr ast.Expr) bool {
+		for {
+			switch x := ast.Unparen(expr).(type) {
+			case *ast.SelectorExpr:
+				expr = x.X
+			case *ast.IndexExpr:
+				expr = x.X
+			case *ast.StarExpr:
+				return true
+			default:
+				return false
+			}
+			if reference(expr) {
+				return true
+			}
+		}
+	}
+
+	var mutations []inputMutation
+	write := func(lhs ast.Expr) {
+		if part(lhs) && shared(lhs) {
+			mutations = append(mutations, inputMutation{
+				pos:     lhs.Pos(),
+				message: "converter function writes " + types.ExprString(lhs) + " of its input",
+			})
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok != token.DEFINE {
+				for _, lhs := range x.Lhs {
+					write(lhs)
+				}
+			}
+		case *ast.IncDecStmt:
+			write(x.X)
+		case *ast.CallExpr:
+			if id, ok := ast.Unparen(x.Fun).(*ast.Ident); ok {
+				_, builtin := fn.object(id).(*types.Builtin)
+				if builtin && (id.Name == "delete" || id.Name == "clear") && len(x.Args) > 0 && rooted(x.Args[0]) {
+					mutations = append(mutations, inputMutation{
+						pos:     x.Pos(),
+						message: "converter function calls " + id.Name + " on " + types.ExprString(x.Args[0]) + " of its input",
+					})
+				}
+				return true
+			}
+			sel, ok := ast.Unparen(x.Fun).(*ast.SelectorExpr)
+			if !ok || !rooted(sel.X) || !reference(sel.X) && !shared(sel.X) {
+				return true
+			}
+			if selection, ok := pass.TypesInfo.Selections[sel]; ok && selection.Kind() == types.MethodVal && pointerReceiver(selection.Obj()) {
+				mutations = append(mutations, inputMutation{
+					pos:     x.Pos(),
+					message: "converter function calls " + types.ExprString(sel) + " with a pointer receiver on its input",
+				})
+			}
+		}
+		return true
+	})
+	return mutations
+}
+
+// pointerReceiver reports whether the method has a pointer receiver, so it may mutate it.
+func pointerReceiver(method types.Object) bool {
+	sig, ok := method.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+	_, pointer := sig.Recv().Type().(*types.Pointer)
+	return pointer
+}
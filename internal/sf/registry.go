@@ -0,0 +1,122 @@
+package sf
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// registry is a composite literal (map, slice or array) whose values are converter functions,
+// e.g. map[string]func(model.Event) db.Event{"created": toDBEvent}.
+type registry struct {
+	lit *ast.CompositeLit
+	sig *types.Signature
+	// funcs holds declared functions referenced from the registry.
+	funcs map[*types.Func]struct{}
+	// lits holds function literals placed directly into the registry.
+	lits map[*ast.FuncLit]struct{}
+}
+
+// findRegistries collects all registries of functions declared in the package.
+func findRegistries(pass *analysis.Pass, in *inspector.Inspector) []*registry {
+	var registries []*registry
+	in.Preorder([]ast.Node{(*ast.CompositeLit)(nil)}, func(n ast.Node) {
+		lit := n.(*ast.CompositeLit)
+
+		var elem types.Type
+		switch t := pass.TypesInfo.TypeOf(lit).Underlying().(type) {
+		case *types.Map:
+			elem = t.Elem()
+		case *types.Slice:
+			elem = t.Elem()
+		case *types.Array:
+			elem = t.Elem()
+		default:
+			return
+		}
+		sig, ok := elem.Underlying().(*types.Signature)
+		if !ok || sig.Params().Len() == 0 || sig.Results().Len() == 0 {
+			return
+		}
+
+		r := &registry{
+			lit:   lit,
+			sig:   sig,
+			funcs: make(map[*types.Func]struct{}),
+			lits:  make(map[*ast.FuncLit]struct{}),
+		}
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			switch v := elt.(type) {
+			case *ast.FuncLit:
+				r.lits[v] = struct{}{}
+			case *ast.Ident:
+				if fn, ok := pass.TypesInfo.Uses[v].(*types.Func); ok {
+					r.funcs[fn] = struct{}{}
+				}
+			case *ast.SelectorExpr:
+				if fn, ok := pass.TypesInfo.Uses[v.Sel].(*types.Func); ok {
+					r.funcs[fn] = struct{}{}
+				}
+			}
+		}
+		registries = append(registries, r)
+	})
+	return registries
+}
+
+// isRegistered reports whether fn is placed in any of the registries.
+func isRegistered(pass *analysis.Pass, registries []*registry, fn *Func) bool {
+	for _, r := range registries {
+		if fn.Lit != nil {
+			if _, ok := r.lits[fn.Lit]; ok {
+				return true
+			}
+			continue
+		}
+		if obj, ok := pass.TypesInfo.Defs[fn.Decl.Name].(*types.Func); ok {
+			if _, ok := r.funcs[obj]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reportUnregisteredConverters reports converters declared in the package with the same signature
+// as a registry's values that are missing from the registry.
+func reportUnregisteredConverters(pass *analysis.Pass, cfg *Config, registries []*registry) {
+	for _, r := range registries {
+		for _, file := range pass.Files {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Recv != nil {
+					continue
+				}
+				obj, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+				if !ok || !types.Identical(obj.Type(), r.sig) {
+					continue
+				}
+				if _, ok := r.funcs[obj]; ok {
+					continue
+				}
+				// Sharing the registry's signature makes it a converter regardless of type names.
+				fn := NewFuncFromDecl(pass, fd)
+				fn.Registered = true
+				if !IsPossibleConverter(fn, pass, cfg) || !cfg.reports(pass, fn, CodeUnregistered) {
+					continue
+				}
+				pass.Report(analysis.Diagnostic{
+					Pos:      r.lit.Pos(),
+					Category: CodeUnregistered,
+					Message:  withCodes(fmt.Sprintf("converter %s is not registered in the registry", fd.Name.Name), CodeUnregistered),
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package sf
+
+import (
+	"fmt"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// pairedConverter is a converter of the package paired with others by its input and output types.
+type pairedConverter struct {
+	check   *funcCheck
+	in, out Candidate
+}
+
+// typePair is the pair of input and output types of a converter.
+type typePair struct {
+	in, out *types.TypeName
+}
+
+// converterPairs returns converters of plain structs (or pointers to them) of the package
+// in the order of the source, along with the first converter of every pair of types,
+// which stands for the others. Converters ignored entirely are left out.
+func converterPairs(pass *analysis.Pass, checks []*funcCheck) ([]pairedConverter, map[typePair]pairedConverter) {
+	var converters []pairedConverter
+	byPair := make(map[typePair]pairedConverter)
+	for _, check := range checks {
+		if !check.converter || check.err != nil {
+			continue
+		}
+		if _, all := check.fn.ignoredFields(); all {
+			continue
+		}
+		in, out, err := Candidates(check.fn, pass)
+		if err != nil || !pairedContainer(in.Container) || !pairedContainer(out.Container) {
+			continue
+		}
+		key := typePair{in.Type, out.Type}
+		if _, ok := byPair[key]; ok {
+			continue
+		}
+		pc := pairedConverter{check: check, in: in, out: out}
+		byPair[key] = pc
+		converters = append(converters, pc)
+	}
+	return converters, byPair
+}
+
+// reportRoundTrips reports converters of the package whose reverse converter of the package drops
+// fields they map, e.g. for ToDB(model.User) db.User and FromDB(db.User) model.User,
+// model.User.Email mapped into db.User.Mail by ToDB is lost on the round trip unless FromDB writes
+// model.User.Email from db.User.Mail. Both directions are checked, so each converter reports
+// the fields lost by its counterpart. It returns the number of findings reported.
+func reportRoundTrips(pass *analysis.Pass, cfg *Config, checks []*funcCheck) int {
+	converters, byPair := converterPairs(pass, checks)
+
+	findings := 0
+	for _, pc := range converters {
+		reverse, ok := byPair[typePair{pc.out.Type, pc.in.Type}]
+		if !ok || !cfg.reports(pass, pc.check.fn, CodeRoundTrip) {
+			continue
+		}
+		mapping, err := ExtractMapping(pc.check.fn, pass)
+		if err != nil {
+			continue
+		}
+		reverseMapping, err := ExtractMapping(reverse.check.fn, pass)
+		if err != nil {
+			continue
+		}
+		lost := lostFields(mapping, reverseMapping)
+		if len(lost) == 0 {
+			continue
+		}
+		reportFunc(pass, cfg, pc.check.filename, pc.check.fn, analysis.Diagnostic{
+			Category: CodeRoundTrip,
+			Message: withCodes(fmt.Sprintf("converter function loses fields on the round trip with %s: %v",
+				reverse.check.fn.Name, lost), CodeRoundTrip),
+		})
+		findings++
+	}
+	return findings
+}
+
+// reportMissingReverse reports converters of the package having no converter of the package
+// in the other direction, e.g. ToDB(model.User) db.User without FromDB(db.User) model.User.
+// It returns the number of findings reported.
+func reportMissingReverse(pass *analysis.Pass, cfg *Config, checks []*funcCheck) int {
+	converters, byPair := converterPairs(pass, checks)
+
+	findings := 0
+	for _, pc := range converters {
+		if _, ok := byPair[typePair{pc.out.Type, pc.in.Type}]; ok || !cfg.reports(pass, pc.check.fn, CodeMissingReverse) {
+			continue
+		}
+		qualifier := types.RelativeTo(pass.Pkg)
+		reportFunc(pass, cfg, pc.check.filename, pc.check.fn, analysis.Diagnostic{
+			Category: CodeMissingReverse,
+			Message: withCodes(fmt.Sprintf("converter function has no reverse converter from %s to %s",
+				types.TypeString(pc.out.Type.Type(), qualifier), types.TypeString(pc.in.Type.Type(), qualifier)),
+				CodeMissingReverse),
+		})
+		findings++
+	}
+	return findings
+}
+
+// lostFields returns input fields of the converter mapped into output fields that the reverse converter
+// does not write the input field back from.
+func lostFields(converter, reverse ConverterMapping) []string {
+	restored := make(map[string][]string, len(reverse.Outputs))
+	for _, out := range reverse.Outputs {
+		restored[out.Field] = out.From
+	}
+
+	var lost []string
+	for _, in := range converter.Inputs {
+		if len(in.To) == 0 {
+			continue
+		}
+		back := false
+		for _, from := range restored[in.Field] {
+			for _, to := range in.To {
+				back = back || from == to
+			}
+		}
+		if !back {
+			lost = append(lost, qualify(converter.In.Var, in.Field))
+		}
+	}
+	return lost
+}
+
+// pairedContainer reports whether converters of structs in the container are paired with reverse ones:
+// plain structs and pointers to them.
+func pairedContainer(container ContainerType) bool {
+	return container == ContainerNone || container == ContainerPointer
+}
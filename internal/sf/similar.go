@@ -0,0 +1,87 @@
+package sf
+
+import (
+	"cmp"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// SimilarStructs is a pair of structurally similar named structs of different layers
+// (e.g. model.User and db.UserRow), likely copied into each other somewhere.
+type SimilarStructs struct {
+	A, B *types.TypeName
+	// Shared contains names of exported fields of A having a field of the same name in B (ignoring case)
+	// of a similar type (see similarTypes).
+	Shared []string
+	// Overlap is the number of shared fields relative to the number of exported fields of the larger struct.
+	Overlap float64
+}
+
+// FindSimilarStructs returns pairs of named structs of different layers sharing at least minShared
+// exported fields with an overlap of at least minOverlap, ordered by decreasing overlap.
+// Every layer lists the named structs of its packages.
+func FindSimilarStructs(layers [][]*types.TypeName, minOverlap float64, minShared int) []SimilarStructs {
+	var pairs []SimilarStructs
+	for i, layer := range layers {
+		for _, other := range layers[i+1:] {
+			for _, a := range layer {
+				for _, b := range other {
+					shared, overlap := structOverlap(a, b)
+					if len(shared) >= max(minShared, 1) && overlap >= minOverlap {
+						pairs = append(pairs, SimilarStructs{A: a, B: b, Shared: shared, Overlap: overlap})
+					}
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(pairs, func(x, y SimilarStructs) int {
+		return cmp.Compare(y.Overlap, x.Overlap)
+	})
+	return pairs
+}
+
+// structOverlap returns exported fields shared by the named structs (see SimilarStructs)
+// and their overlap.
+func structOverlap(a, b *types.TypeName) ([]string, float64) {
+	stA, okA := a.Type().Underlying().(*types.Struct)
+	stB, okB := b.Type().Underlying().(*types.Struct)
+	if !okA || !okB {
+		return nil, 0
+	}
+	fieldsB := make(map[string]*types.Var)
+	for i := 0; i < stB.NumFields(); i++ {
+		if f := stB.Field(i); f.Exported() {
+			fieldsB[strings.ToLower(f.Name())] = f
+		}
+	}
+
+	var shared []string
+	exportedA := 0
+	for i := 0; i < stA.NumFields(); i++ {
+		f := stA.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		exportedA++
+		if g, ok := fieldsB[strings.ToLower(f.Name())]; ok && similarTypes(f.Type(), g.Type()) {
+			shared = append(shared, f.Name())
+		}
+	}
+	larger := max(exportedA, len(fieldsB))
+	if larger == 0 {
+		return nil, 0
+	}
+	return shared, float64(len(shared)) / float64(larger)
+}
+
+// similarTypes reports whether values of the types are likely copied into each other:
+// their underlying types are identical or both numeric (e.g. int and int64).
+func similarTypes(a, b types.Type) bool {
+	if types.Identical(a.Underlying(), b.Underlying()) {
+		return true
+	}
+	basicA, okA := a.Underlying().(*types.Basic)
+	basicB, okB := b.Underlying().(*types.Basic)
+	return okA && okB && basicA.Info()&types.IsNumeric != 0 && basicB.Info()&types.IsNumeric != 0
+}
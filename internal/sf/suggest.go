@@ -0,0 +1,108 @@
+package sf
+
+import (
+	"go/types"
+	"strings"
+)
+
+// minNameSimilarity is the minimal similarity of field names for a suggestion.
+const minNameSimilarity = 0.6
+
+// FieldSuggestion is a likely source of a missing output field.
+type FieldSuggestion struct {
+	// Output is the missing output field.
+	Output string
+	// Input is the input field suggested to be mapped into it.
+	Input string
+}
+
+func (s FieldSuggestion) String() string {
+	return s.Output + ": did you mean " + s.Input + "?"
+}
+
+// suggestSources returns the best matching input field for every missing output field:
+// fields with similar names whose type is convertible to the output field's type.
+// Among equally good matches, input fields not used yet are preferred.
+func suggestSources(in, out *types.Struct, missingOut []string, usedIn *UsageLookup) map[string]string {
+	suggestions := make(map[string]string)
+	for _, name := range missingOut {
+		outField := structField(out, name)
+		if outField == nil {
+			continue
+		}
+
+		var (
+			best      string
+			bestScore float64
+			bestUsed  bool
+		)
+		for i := 0; i < in.NumFields(); i++ {
+			inField := in.Field(i)
+			if !inField.Exported() || !types.ConvertibleTo(inField.Type(), outField.Type()) {
+				continue
+			}
+			score := nameSimilarity(inField.Name(), name)
+			if score < minNameSimilarity {
+				continue
+			}
+			used := usedIn.LookUp(inField.Name())
+			if score > bestScore || score == bestScore && bestUsed && !used {
+				best, bestScore, bestUsed = inField.Name(), score, used
+			}
+		}
+		if best != "" {
+			suggestions[name] = best
+		}
+	}
+	return suggestions
+}
+
+// structField returns the field of the struct with the given name.
+func structField(st *types.Struct, name string) *types.Var {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Name() == name {
+			return st.Field(i)
+		}
+	}
+	return nil
+}
+
+// nameSimilarity returns the similarity of two field names between 0 and 1:
+// 1 for names equal ignoring case, 0.8 if one contains the other,
+// otherwise the normalized Levenshtein distance.
+func nameSimilarity(a, b string) float64 {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	switch {
+	case a == b:
+		return 1
+	case strings.Contains(a, b) || strings.Contains(b, a):
+		return 0.8
+	}
+
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
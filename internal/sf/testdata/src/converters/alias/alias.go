@@ -0,0 +1,43 @@
+package alias
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+type DBSample = dbmodel.Sample
+
+type DBSamplePtr = *dbmodel.Sample
+
+func ConvertSampleToDB(sample model.Sample) DBSample {
+	return DBSample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func ConvertSampleToDBPtr(sample model.Sample) DBSamplePtr {
+	out := &DBSample{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+	out.Price = sample.Price
+	out.Currency = sample.Currency
+	return out
+}
+
+func ConvertSampleToDBPartial(sample model.Sample) DBSample { // want `missing input fields: \[sample.Currency\]\n missing output fields: \[Currency\]`
+	return DBSample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+func ConvertSampleToDBDiscarded(sample model.Sample) DBSample { // want `missing output fields: \[ID Label Price Currency\]`
+	tmp := model.Sample{ID: sample.ID, Label: sample.Label, Price: sample.Price, Currency: sample.Currency}
+	_ = tmp
+	return DBSample{}
+}
@@ -0,0 +1,24 @@
+package api
+
+type User struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+type Profile struct {
+	ID   string
+	Name string
+}
+
+func ToProfile(u User) Profile { // want `User -> Profile: missing input \[u.Email\], missing output \[\]`
+	return Profile{ID: u.ID, Name: u.Name}
+}
+
+func FromProfile(p *Profile) *User { // want `Profile -> User: missing input \[\], missing output \[Email\]`
+	return &User{ID: p.ID, Name: p.Name}
+}
+
+func double(x int) int {
+	return x * 2
+}
@@ -0,0 +1,28 @@
+package baseline
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+// ToSampleDTO leaks Price, which is recorded in the baseline, and Currency, which is not.
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `SF002: converter function is leaking fields:\n missing input fields: \[sample.Currency\]`
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
+
+type Mapper struct{}
+
+// FromSampleDTO leaks fields recorded in the baseline only, keyed by the receiver type.
+func (Mapper) FromSampleDTO(dto SampleDTO) model.Sample {
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+	}
+}
@@ -0,0 +1,19 @@
+package blankreads
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) (result *dbmodel.Sample) { // want `missing input fields: \[sample.ID sample.Label\]\n missing output fields: \[result.ID\]`
+	_ = sample.Label
+	_, _ = sample.ID, result.ID
+
+	result = &dbmodel.Sample{
+		Label:    "const label",
+		Currency: sample.Currency,
+	}
+	result.Price = sample.Price
+
+	return
+}
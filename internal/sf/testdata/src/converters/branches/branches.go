@@ -0,0 +1,57 @@
+package branches
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) dbmodel.Sample { // want `missing output fields: \[Currency\]`
+	if sample.Price == 0 {
+		return dbmodel.Sample{
+			ID:    sample.ID,
+			Label: sample.Label,
+			Price: sample.Price,
+		}
+	}
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) (result model.Sample) { // want `missing output fields: \[result.Label\]`
+	switch sample.Currency {
+	case "USD":
+		result = model.Sample{
+			ID:       sample.ID,
+			Label:    sample.Label,
+			Currency: "USD",
+		}
+	default:
+		result = model.Sample{
+			ID:       sample.ID,
+			Currency: sample.Currency,
+		}
+	}
+	result.Price = sample.Price
+	return result
+}
+
+func ToDBSampleOrDefault(sample model.Sample) dbmodel.Sample {
+	if sample.Label == "" {
+		return dbmodel.Sample{
+			ID:       sample.ID,
+			Label:    "default",
+			Price:    sample.Price,
+			Currency: sample.Currency,
+		}
+	}
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) dbmodel.Sample {
+	b := dbmodel.NewSampleBuilder()
+	b.ID(sample.ID)
+	b.Label(sample.Label)
+	b.WithPrice(sample.Price)
+	b.SetCurrency(sample.Currency)
+	return b.Build()
+}
+
+func ConvertSampleToDBChained(sample model.Sample) dbmodel.Sample {
+	return dbmodel.NewSampleBuilder().
+		ID(sample.ID).
+		Label(sample.Label).
+		WithPrice(sample.Price).
+		SetCurrency(sample.Currency).
+		Build()
+}
+
+func ConvertSampleToDBPartial(sample model.Sample) dbmodel.Sample { // want `missing input fields: \[sample.Currency\]\n missing output fields: \[Currency\]`
+	return dbmodel.NewSampleBuilder().
+		ID(sample.ID).
+		Label(sample.Label).
+		WithPrice(sample.Price).
+		Build()
+}
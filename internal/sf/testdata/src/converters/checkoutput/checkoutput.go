@@ -0,0 +1,24 @@
+package checkoutput
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+// Unread input fields are not reported.
+func ToDBSample(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    "const label",
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) model.Sample { // want `leaking fields:\n missing output fields: \[Currency\]`
+	return model.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
@@ -0,0 +1,21 @@
+package hardcoded
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID       string
+	Label    string
+	Price    int64
+	Currency string
+}
+
+// ToSampleDTO both leaks and hardcodes fields, reported by different checks.
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `SF003: converter function hardcodes output fields instead of mapping input ones: \[Label\]`
+	return SampleDTO{
+		ID:       sample.ID,
+		Label:    "sample",
+		Currency: sample.Currency,
+	}
+}
@@ -0,0 +1,21 @@
+package leaks
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID       string
+	Label    string
+	Price    int64
+	Currency string
+}
+
+// ToSampleDTO both leaks and hardcodes fields, reported by different checks.
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `SF001, SF002: converter function is leaking fields:\n missing input fields: \[sample.Label sample.Price\]\n missing output fields: \[Price\]`
+	return SampleDTO{
+		ID:       sample.ID,
+		Label:    "sample",
+		Currency: sample.Currency,
+	}
+}
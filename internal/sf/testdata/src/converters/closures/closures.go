@@ -0,0 +1,58 @@
+package closures
+
+import (
+	"strings"
+	"sync"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func mapSlice[T, R any](items []T, f func(T) R) []R {
+	out := make([]R, 0, len(items))
+	for _, item := range items {
+		out = append(out, f(item))
+	}
+	return out
+}
+
+func ToDBSample(sample model.Sample) dbmodel.Sample {
+	var out dbmodel.Sample
+	func() {
+		out.ID = sample.ID
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		out.Label = strings.Join(mapSlice([]string{sample.Label}, func(s string) string {
+			return strings.TrimSpace(s)
+		}), "")
+	}()
+	wg.Wait()
+
+	func(s model.Sample) {
+		out.Price = s.Price
+	}(sample)
+
+	setCurrency := func(src *model.Sample) {
+		out.Currency = src.Currency
+	}
+	setCurrency(&sample)
+	return out
+}
+
+func FromDBSample(sample dbmodel.Sample) model.Sample { // want `missing input fields: \[sample.Currency\]`
+	out := model.Sample{ID: sample.ID}
+	func(sample dbmodel.Sample) {
+		// The parameter shadows the input: its fields are not the input's.
+		out.Currency = sample.Currency
+	}(dbmodel.Sample{Currency: "EUR"})
+	apply := func(s dbmodel.Sample) {
+		out.Label = s.Label
+		out.Price = s.Price
+	}
+	apply(sample)
+	return out
+}
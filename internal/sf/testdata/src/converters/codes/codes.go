@@ -0,0 +1,25 @@
+package codes
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+// ToSampleDTO does not read all input fields, but SF002 is disabled.
+func ToSampleDTO(sample model.Sample) SampleDTO {
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
+
+func FromSampleDTO(dto SampleDTO) model.Sample { // want `SF001: converter function is leaking fields:\n missing output fields: \[Price Currency\]`
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+	}
+}
@@ -0,0 +1,17 @@
+package color
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `\x1b\[31mSF002: converter function is leaking fields:`
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
@@ -0,0 +1,46 @@
+package copies
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(in model.Sample) dbmodel.Sample {
+	s := in
+	out := dbmodel.Sample{ID: s.ID, Label: s.Label}
+	out.Price = s.Price
+	out.Currency = in.Currency
+	return out
+}
+
+func ToDBSamplePtr(in *model.Sample) *dbmodel.Sample {
+	tmp := *in
+	src := &tmp
+	return &dbmodel.Sample{ID: tmp.ID, Label: tmp.Label, Price: src.Price, Currency: src.Currency}
+}
+
+func ToDBSamples(in []model.Sample) []dbmodel.Sample {
+	items := in
+	var out []dbmodel.Sample
+	for _, item := range items {
+		out = append(out, dbmodel.Sample{ID: item.ID, Label: item.Label, Price: item.Price, Currency: item.Currency})
+	}
+	return out
+}
+
+func FromDBSample(in *dbmodel.Sample) model.Sample { // want `missing input fields: \[in.Currency\]`
+	var s = *in
+	return model.Sample{ID: s.ID, Label: s.Label, Price: s.Price}
+}
+
+func FromDBSampleShadowed(in *dbmodel.Sample) model.Sample { // want `missing input fields: \[in.Label in.Price in.Currency\]`
+	out := model.Sample{ID: in.ID}
+	if in.ID == "" {
+		in := &dbmodel.Sample{}
+		s := *in
+		out.Label = s.Label
+		out.Price = s.Price
+		out.Currency = s.Currency
+	}
+	return out
+}
@@ -0,0 +1,8 @@
+package dbmodel
+
+type Account struct {
+	ID        string
+	Email     string
+	Nickname  string
+	UpdatedAt int64 //sf:ignore
+}
@@ -0,0 +1,16 @@
+package dbmodel
+
+// Car mimics an ent-generated model.
+type Car struct {
+	config
+	ID    int
+	Model string
+	Edges CarEdges `json:"edges"`
+}
+
+type CarEdges struct {
+	Owner       *Account
+	loadedTypes [1]bool
+}
+
+type config struct{}
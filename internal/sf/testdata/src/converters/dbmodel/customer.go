@@ -0,0 +1,15 @@
+package dbmodel
+
+import (
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Customer struct {
+	ID      int64
+	Name    sql.NullString
+	Age     sql.NullInt64
+	Company pgtype.Text
+	Rating  pgtype.Int8
+}
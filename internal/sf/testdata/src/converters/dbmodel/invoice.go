@@ -0,0 +1,7 @@
+package dbmodel
+
+type Invoice struct {
+	ID     string
+	Amount int64
+	Legacy bool // Deprecated: kept for old readers only.
+}
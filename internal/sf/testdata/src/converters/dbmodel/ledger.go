@@ -0,0 +1,6 @@
+package dbmodel
+
+type LedgerRow struct {
+	ID     string
+	Amount int64
+}
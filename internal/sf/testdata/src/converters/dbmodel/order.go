@@ -0,0 +1,9 @@
+package dbmodel
+
+import "gorm.io/gorm"
+
+type Order struct {
+	gorm.Model
+	Number string
+	Total  int64
+}
@@ -0,0 +1,16 @@
+package dbmodel
+
+// Pet mimics a sqlboiler-generated model.
+type Pet struct {
+	ID   int    `boil:"id" json:"id"`
+	Name string `boil:"name" json:"name"`
+
+	R *petR `boil:"-" json:"-" toml:"-" yaml:"-"`
+	L petL  `boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+type petR struct {
+	Owner *Account
+}
+
+type petL struct{}
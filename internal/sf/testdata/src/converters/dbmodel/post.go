@@ -0,0 +1,7 @@
+package dbmodel
+
+type Post struct {
+	ID      string
+	Title   string
+	Content string
+}
@@ -0,0 +1,33 @@
+package dbmodel
+
+type SampleBuilder struct {
+	sample Sample
+}
+
+func NewSampleBuilder() *SampleBuilder {
+	return &SampleBuilder{}
+}
+
+func (b *SampleBuilder) ID(v string) *SampleBuilder {
+	b.sample.ID = v
+	return b
+}
+
+func (b *SampleBuilder) Label(v string) *SampleBuilder {
+	b.sample.Label = v
+	return b
+}
+
+func (b *SampleBuilder) WithPrice(v int64) *SampleBuilder {
+	b.sample.Price = v
+	return b
+}
+
+func (b *SampleBuilder) SetCurrency(v string) *SampleBuilder {
+	b.sample.Currency = v
+	return b
+}
+
+func (b *SampleBuilder) Build() Sample {
+	return b.sample
+}
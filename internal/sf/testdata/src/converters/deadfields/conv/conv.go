@@ -0,0 +1,35 @@
+package conv
+
+import (
+	"encoding/json"
+
+	"converters/deadfields/db"
+)
+
+type User struct {
+	ID       string
+	Name     string
+	Email    string
+	Nickname string
+}
+
+func ToUserRow(u User) db.UserRow { // want `missing input fields: \[u.Email u.Nickname\]\n missing output fields: \[Email Legacy\]`
+	return db.UserRow{ID: u.ID, Name: u.Name}
+}
+
+func FromUserRow(r db.UserRow) User { // want `missing input fields: \[r.Legacy\]\n missing output fields: \[Nickname\]`
+	return User{ID: r.ID, Name: r.Name, Email: r.Email}
+}
+
+type Document struct {
+	ID   string
+	Data []byte
+}
+
+// Fields of models copied invisibly are not dead.
+func ToBlob(d Document) db.Blob {
+	var b db.Blob
+	data, _ := json.Marshal(d)
+	_ = json.Unmarshal(data, &b)
+	return b
+}
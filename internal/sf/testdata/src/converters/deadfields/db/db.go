@@ -0,0 +1,14 @@
+package db
+
+type UserRow struct {
+	ID     string
+	Name   string
+	Email  string
+	Legacy string
+	Audit  string //sf:ignore
+}
+
+type Blob struct {
+	ID   string
+	Data []byte
+}
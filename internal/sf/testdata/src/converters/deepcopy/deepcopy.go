@@ -0,0 +1,24 @@
+package deepcopy
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"converters/pb"
+)
+
+func RedactEvent(event *pb.Event) *pb.Event {
+	out := proto.Clone(event).(*pb.Event)
+	out.Id = ""
+	return out
+}
+
+func MergeUser(user *pb.User) *pb.User {
+	out := &pb.User{}
+	proto.Merge(out, user)
+	return out
+}
+
+func CloneOtherEvent(event *pb.Event) *pb.Event { // want `missing input fields: \[event.Id event.CreatedAt\]\n missing output fields: \[Id CreatedAt\]`
+	other := &pb.Event{}
+	return proto.Clone(other).(*pb.Event)
+}
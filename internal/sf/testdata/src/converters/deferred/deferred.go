@@ -0,0 +1,30 @@
+package deferred
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) (result dbmodel.Sample) {
+	defer func() {
+		result.Currency = sample.Currency
+	}()
+
+	result = dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+	return result
+}
+
+func FromDBSample(sample dbmodel.Sample) (result model.Sample) { // want `missing input fields: \[sample.Currency\]\n missing output fields: \[result.Currency\]`
+	defer func() {
+		result.Price = sample.Price
+	}()
+
+	return model.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
@@ -0,0 +1,48 @@
+package delegate
+
+import (
+	"errors"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(in model.Sample) dbmodel.Sample {
+	return buildDBSample(in)
+}
+
+func ToDBSampleChecked(in *model.Sample) (dbmodel.Sample, error) {
+	if in.ID == "" {
+		return dbmodel.Sample{}, errors.New("sample without ID")
+	}
+	return buildDBSample(*in), nil
+}
+
+func buildDBSample(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+type User struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+type UserRow struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+func (u User) row() UserRow {
+	return UserRow{ID: u.ID, Name: u.Name}
+}
+
+func ToUserRow(u User) UserRow { // want `missing input fields: \[u.Email\]\n missing output fields: \[Email\]`
+	return u.row()
+}
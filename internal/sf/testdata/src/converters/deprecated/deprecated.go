@@ -0,0 +1,13 @@
+package deprecated
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertInvoiceToDB(invoice model.Invoice) dbmodel.Invoice { // want `missing input fields: \[invoice.Total\]\n missing output fields: \[Legacy\]`
+	return dbmodel.Invoice{
+		ID:     invoice.ID,
+		Amount: invoice.Amount,
+	}
+}
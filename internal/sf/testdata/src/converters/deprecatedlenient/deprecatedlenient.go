@@ -0,0 +1,13 @@
+package deprecatedlenient
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertInvoiceToDB(invoice model.Invoice) dbmodel.Invoice {
+	return dbmodel.Invoice{
+		ID:     invoice.ID,
+		Amount: invoice.Amount,
+	}
+}
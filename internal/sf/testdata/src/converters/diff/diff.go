@@ -0,0 +1,26 @@
+package diff
+
+type Order struct {
+	ID       string
+	Comment  string
+	Discount int // added
+}
+
+type OrderDTO struct {
+	ID       string
+	Discount int // added
+}
+
+// ToOrderDTO is unchanged: only the added field is reported.
+func ToOrderDTO(o Order) OrderDTO { // want `SF001, SF002: converter function is leaking fields:\n missing input fields: \[o.Discount\]\n missing output fields: \[Discount\]`
+	return OrderDTO{
+		ID: o.ID,
+	}
+}
+
+// FromOrderDTO is changed: all its findings are reported.
+func FromOrderDTO(dto OrderDTO) Order { // want `SF001, SF002: converter function is leaking fields:\n missing input fields: \[dto.Discount\]\n missing output fields: \[Comment Discount\]`
+	return Order{
+		ID: dto.ID, // changed
+	}
+}
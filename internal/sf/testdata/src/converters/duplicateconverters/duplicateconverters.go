@@ -0,0 +1,39 @@
+package duplicateconverters
+
+type User struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+type UserRow struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name, Email: u.Email}
+}
+
+func UserToRow(user User) UserRow { // want `SF001, SF002: converter function is leaking fields` `SF015: converter function duplicates ToUserRow converting User into UserRow \(coverage 67% vs 100%\)\n fields mapped by ToUserRow only: \[in.Email out.Email\]`
+	return UserRow{ID: user.ID, Name: user.Name}
+}
+
+// Intentional variants are allowed.
+func ToUserRowRedacted(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name, Email: u.Email}
+}
+
+// Converters of other containers convert other types.
+func ToUserRowPtr(u *User) *UserRow {
+	return &UserRow{ID: u.ID, Name: u.Name, Email: u.Email}
+}
+
+func ToUserRows(users []User) []UserRow {
+	rows := make([]UserRow, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, UserRow{ID: u.ID, Name: u.Name, Email: u.Email})
+	}
+	return rows
+}
@@ -0,0 +1,52 @@
+package duplicates
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) (result dbmodel.Sample) { // want `writes output fields more than once: \[result.Label\]`
+	result = dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Currency: sample.Currency,
+	}
+	result.Price = sample.Price
+	result.Label = sample.Currency
+	return result
+}
+
+func FromDBSample(sample dbmodel.Sample) (result model.Sample) { // want `writes output fields more than once: \[result.Price\]`
+	if sample.Currency == "" {
+		result.Price = 0
+	} else {
+		result.Price = sample.Price
+	}
+	result.ID = sample.ID
+	result.Label = sample.Label
+	result.Currency = sample.Currency
+	result.Price = sample.Price * 100
+	return result
+}
+
+// Conditional overrides, self-updates and early returns are fine.
+func ToDBSampleNormalized(sample model.Sample) *dbmodel.Sample {
+	if sample.Price < 0 {
+		return &dbmodel.Sample{
+			ID:       sample.ID,
+			Label:    sample.Label,
+			Currency: sample.Currency,
+		}
+	}
+	out := &dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+	if out.Label == "" {
+		out.Label = "unknown"
+	}
+	out.Price = out.Price * 100
+	return out
+}
@@ -0,0 +1,56 @@
+package earlyreturn
+
+import (
+	"errors"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+var errNoPrice = errors.New("no price")
+
+func ToDBSample(sample *model.Sample) *dbmodel.Sample {
+	if sample == nil {
+		return nil
+	}
+	return &dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) (model.Sample, error) {
+	if sample.Price == 0 {
+		return model.Sample{}, errNoPrice
+	}
+	if sample.Currency == "" {
+		return model.Sample{ID: sample.ID}, errors.New("no currency")
+	}
+	return model.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}, nil
+}
+
+func ToDBSampleChecked(sample model.Sample) (dbmodel.Sample, error) { // want `missing output fields: \[Label\]`
+	if sample.Price == 0 {
+		return dbmodel.Sample{}, nil
+	}
+	if sample.Label == "" {
+		return dbmodel.Sample{
+			ID:       sample.ID,
+			Price:    sample.Price,
+			Currency: sample.Currency,
+		}, nil
+	}
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package errorconvention
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type User struct {
+	ID   string
+	Age  string
+	Meta any
+}
+
+type Meta struct {
+	Source string
+}
+
+type UserRow struct {
+	ID     int
+	Age    int
+	Source string
+}
+
+func ToUserRow(u User) UserRow { // want `SF019: converter function can fail but returns no error: strconv.Atoi, strconv.Atoi, u.Meta.\(Meta\)`
+	id, _ := strconv.Atoi(u.ID)
+	age, _ := strconv.Atoi(u.Age)
+	return UserRow{ID: id, Age: age, Source: u.Meta.(Meta).Source}
+}
+
+func ToUserRowChecked(u User) (UserRow, error) {
+	id, err := strconv.Atoi(u.ID)
+	if err != nil {
+		return UserRow{}, fmt.Errorf("parsing ID: %w", err)
+	}
+	age, err := strconv.Atoi(u.Age)
+	if err != nil {
+		return UserRow{}, fmt.Errorf("parsing age: %w", err)
+	}
+	meta, _ := u.Meta.(Meta)
+	return UserRow{ID: id, Age: age, Source: meta.Source}, nil
+}
+
+func ToUserRowSwitch(u User) UserRow {
+	row := UserRow{ID: len(u.ID), Age: len(u.Age)}
+	switch meta := u.Meta.(type) {
+	case Meta:
+		row.Source = meta.Source
+	}
+	return row
+}
+
+func ToUserRowPtr(u *User) UserRow { // want `SF020: converter function dereferences its pointer input u without checking it for nil`
+	return UserRow{ID: len(u.ID), Age: len(u.Age), Source: fmt.Sprint(u.Meta)}
+}
+
+func ToUserRowPtrChecked(u *User) UserRow {
+	if u == nil {
+		return UserRow{}
+	}
+	return UserRow{ID: len(u.ID), Age: len(u.Age), Source: fmt.Sprint(u.Meta)}
+}
+
+//nolint:stickyfields // IDs are validated upstream
+func ToUserRowTrusted(u User) UserRow {
+	id, _ := strconv.Atoi(u.ID)
+	return UserRow{ID: id, Age: len(u.Age), Source: u.Meta.(Meta).Source}
+}
+
+//sf:ignore
+func ToUserRowPtrTrusted(u *User) UserRow {
+	return UserRow{ID: len(u.ID), Age: len(u.Age), Source: fmt.Sprint(u.Meta)}
+}
@@ -0,0 +1,15 @@
+package explain
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+// SamplesToDTO takes a single sample, so it's not a converter of slices.
+func SamplesToDTO(sample model.Sample, limit int) []SampleDTO {
+	return nil
+}
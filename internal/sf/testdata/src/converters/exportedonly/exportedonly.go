@@ -0,0 +1,24 @@
+package exportedonly
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) dbmodel.Sample { // want `missing output fields: \[Currency\]`
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price + int64(len(sample.Currency)),
+	}
+}
+
+func convertSampleToDBPartially(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID: sample.ID,
+	}
+}
+
+var ConvertSampleToDBFunc = func(sample model.Sample) dbmodel.Sample { // want `missing output fields: \[Label Price Currency\]`
+	return convertSampleToDBPartially(sample)
+}
@@ -0,0 +1,13 @@
+package facts
+
+type User struct { // want User:`fields:\[ID Name Email\] ignored:\[Email\] deprecated:\[\]`
+	ID    string
+	Name  string
+	Email string //sf:ignore
+
+	password string
+}
+
+type Empty struct {
+	internal int
+}
@@ -0,0 +1,21 @@
+package fieldmap
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertPostToDB(post model.Post) dbmodel.Post {
+	return dbmodel.Post{
+		ID:      post.ID,
+		Title:   post.Title,
+		Content: post.Body,
+	}
+}
+
+func ConvertPostToDBSwapped(post model.Post) (result dbmodel.Post) { // want "unmapped fields: \\[post.Body -> result.Content\\]"
+	result.ID = post.ID
+	result.Title = post.Body
+	result.Content = post.Title
+	return result
+}
@@ -0,0 +1,47 @@
+package funclit
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+var convertSampleToDB = func(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+var convertSampleToDBPartial = func(sample model.Sample) dbmodel.Sample { // want `missing input fields: \[sample.Currency\]`
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+func mapSlice[T, R any](items []T, f func(T, int) R) []R {
+	res := make([]R, 0, len(items))
+	for i, item := range items {
+		res = append(res, f(item, i))
+	}
+	return res
+}
+
+func ConvertSamples(samples []model.Sample) []int {
+	short := func(sample model.Sample) dbmodel.Sample { // want `missing input fields: \[sample.Price sample.Currency\]`
+		return dbmodel.Sample{ID: sample.ID, Label: sample.Label}
+	}
+	_ = short
+
+	converted := mapSlice(samples, func(sample model.Sample, _ int) dbmodel.Sample { // want `missing output fields: \[Price\]`
+		return dbmodel.Sample{
+			ID:       sample.ID,
+			Label:    sample.Label,
+			Currency: sample.Currency + string(rune(sample.Price)),
+		}
+	})
+	return make([]int, len(converted))
+}
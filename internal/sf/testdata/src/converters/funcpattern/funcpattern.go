@@ -0,0 +1,28 @@
+package funcpattern
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) dbmodel.Sample { // want `missing output fields: \[Currency\]`
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price + int64(len(sample.Currency)),
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) model.Sample { // want `missing input fields: \[sample.Currency\]`
+	return model.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+func PatchSample(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID: sample.ID,
+	}
+}
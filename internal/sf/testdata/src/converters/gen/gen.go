@@ -0,0 +1,33 @@
+package gen
+
+type Sample struct {
+	ID      int
+	Name    string
+	Email   string
+	Age     int
+	Tags    []string
+	Comment string
+}
+
+type SampleRow struct {
+	ID       int64
+	Name     string
+	EmailStr string
+	Age      string
+	Tags     []string
+	Created  int
+}
+
+// ToSampleRow converts Sample into SampleRow.
+//
+// TODO: map Age, Created, Comment and remove them from the //sf:ignore directive.
+//
+//sf:ignore Age Created Comment
+func ToSampleRow(in Sample) SampleRow {
+	return SampleRow{
+		ID:       int64(in.ID),
+		Name:     in.Name,
+		EmailStr: in.Email, // TODO: check, matched by a similar name
+		Tags:     in.Tags,
+	}
+}
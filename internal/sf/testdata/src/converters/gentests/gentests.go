@@ -0,0 +1,37 @@
+package gentests
+
+import (
+	"errors"
+	"time"
+)
+
+type User struct {
+	ID      int
+	Name    string
+	Created time.Time
+}
+
+type UserRow struct {
+	ID      int
+	Name    string
+	Created time.Time
+	Version int
+}
+
+//sf:ignore Version
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name, Created: u.Created}
+}
+
+//sf:ignore Version
+func ToUserRowPtr(u *User) (*UserRow, error) {
+	if u == nil {
+		return nil, errors.New("no user")
+	}
+	return &UserRow{ID: u.ID, Name: u.Name, Created: u.Created}, nil
+}
+
+//sf:ignore Version
+func ToUserRowVersion(u User, version int) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name, Created: u.Created}
+}
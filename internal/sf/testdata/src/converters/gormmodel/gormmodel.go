@@ -0,0 +1,47 @@
+package gormmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertOrderToDB(order model.Order) dbmodel.Order {
+	return dbmodel.Order{
+		Number: order.Number,
+		Total:  order.Total,
+	}
+}
+
+func ConvertOrderFromDB(order dbmodel.Order) model.Order { // want `missing input fields: \[order.Total\]`
+	return model.Order{
+		Number: order.Number,
+	}
+}
+
+// User is the domain counterpart of UserRecord: its ID and CreatedAt come from gorm.Model there.
+type User struct {
+	ID        uint
+	CreatedAt time.Time
+	Name      string
+}
+
+type UserRecord struct {
+	gorm.Model
+	Name string
+}
+
+func ConvertUserToDB(user User) UserRecord {
+	return UserRecord{
+		Name: user.Name,
+	}
+}
+
+func ConvertUserFromDB(user UserRecord) User {
+	return User{
+		Name: user.Name,
+	}
+}
@@ -0,0 +1,30 @@
+package hardcoded
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) (result *dbmodel.Sample) { // want `hardcodes output fields instead of mapping input ones: \[result.Label\]`
+	_ = sample.Label
+	_ = sample.ID
+
+	result = &dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    "const label",
+		Currency: sample.Currency,
+	}
+	result.Price = sample.Price
+
+	return
+}
+
+// The input label is mapped, just into another field.
+func ConvertSampleFromDB(sample dbmodel.Sample) model.Sample {
+	return model.Sample{
+		ID:       sample.ID,
+		Label:    "const label",
+		Price:    sample.Price,
+		Currency: sample.Currency + sample.Label,
+	}
+}
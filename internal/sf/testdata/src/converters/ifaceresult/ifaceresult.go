@@ -0,0 +1,23 @@
+package ifaceresult
+
+import (
+	"converters/model"
+	"converters/storage"
+)
+
+func ToRecord(sample model.Sample) storage.Record {
+	return &storage.SampleRecord{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func ToRecordPartial(sample model.Sample) (storage.Record, error) { // want `missing output fields: \[Currency\]`
+	return &storage.SampleRecord{
+		ID:    sample.ID,
+		Label: sample.Label + sample.Currency,
+		Price: sample.Price,
+	}, nil
+}
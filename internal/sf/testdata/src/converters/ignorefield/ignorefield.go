@@ -0,0 +1,22 @@
+package ignorefield
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertAccountToDB(account model.Account) dbmodel.Account {
+	return dbmodel.Account{
+		ID:       account.ID,
+		Email:    account.Email,
+		Nickname: account.Nickname,
+	}
+}
+
+func ConvertAccountToDBPartial(account model.Account) dbmodel.Account { // want "missing input fields: \\[account.Nickname\\]"
+	return dbmodel.Account{
+		ID:       account.ID,
+		Email:    account.Email,
+		Nickname: "",
+	}
+}
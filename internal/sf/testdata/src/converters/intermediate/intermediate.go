@@ -0,0 +1,40 @@
+package intermediate
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) dbmodel.Sample {
+	var base dbmodel.Sample
+	base.ID = sample.ID
+	base.Label = sample.Label
+	var priced = base
+	priced.Price = sample.Price
+	var result dbmodel.Sample
+	result = priced
+	result.Currency = sample.Currency
+	return result
+}
+
+func ToDBSamplePtr(sample model.Sample) *dbmodel.Sample {
+	var base dbmodel.Sample
+	base.ID = sample.ID
+	base.Label = sample.Label
+	out := &base
+	final := *out
+	final.Price = sample.Price
+	final.Currency = sample.Currency
+	return &final
+}
+
+func FromDBSample(sample dbmodel.Sample) (result model.Sample) { // want `missing output fields: \[result.Currency\]`
+	base := model.Sample{ID: sample.ID, Label: sample.Label}
+	base.Price = sample.Price
+	var unrelated model.Sample
+	unrelated.Currency = sample.Currency
+	_ = unrelated
+	var copied = base
+	result = copied
+	return result
+}
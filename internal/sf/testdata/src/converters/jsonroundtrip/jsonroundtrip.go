@@ -0,0 +1,21 @@
+package jsonroundtrip
+
+import (
+	"encoding/json"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) (*dbmodel.Sample, error) { // want `converter field coverage unknown: relies on struct tag compatibility \(JSON round-trip\)`
+	b, err := json.Marshal(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	var out dbmodel.Sample
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,36 @@
+package jsonroundtripskipped
+
+import (
+	"encoding/json"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) (*dbmodel.Sample, error) {
+	b, err := json.Marshal(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	var out dbmodel.Sample
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func ConvertSampleToDBShadowed(sample model.Sample) (*dbmodel.Sample, error) { // want `missing input fields: \[sample.ID sample.Label sample.Price sample.Currency\]`
+	var out dbmodel.Sample
+	{
+		sample := model.Sample{Label: "draft"}
+		b, err := json.Marshal(sample)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &out); err != nil {
+			return nil, err
+		}
+	}
+	return &out, nil
+}
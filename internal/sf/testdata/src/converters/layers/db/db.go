@@ -0,0 +1,6 @@
+package db
+
+type UserRow struct {
+	ID   string
+	Name string
+}
@@ -0,0 +1,16 @@
+package domain
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type Profile struct {
+	ID   string
+	Name string
+}
+
+// Conversions within a layer are unrestricted.
+func ToProfile(u User) Profile {
+	return Profile{ID: u.ID, Name: u.Name}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"converters/layers/db"
+	"converters/layers/domain"
+)
+
+type UserResponse struct {
+	ID   string
+	Name string
+}
+
+func ToUserRow(u domain.User) db.UserRow { // want `SF016: converter function converting converters/layers/domain.User \(domain\) into converters/layers/db.UserRow \(db\) is declared outside of /repo\$`
+	return db.UserRow{ID: u.ID, Name: u.Name}
+}
+
+// Models outside of layers are unrestricted.
+func ToUserResponse(u domain.User) UserResponse {
+	return UserResponse{ID: u.ID, Name: u.Name}
+}
+
+//sf:ignore
+func ToUserRowUnchecked(u domain.User) db.UserRow {
+	return db.UserRow{ID: u.ID}
+}
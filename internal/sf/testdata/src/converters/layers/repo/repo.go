@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"converters/layers/db"
+	"converters/layers/domain"
+)
+
+func ToUserRow(u domain.User) db.UserRow {
+	return db.UserRow{ID: u.ID, Name: u.Name}
+}
+
+func FromUserRow(r db.UserRow) domain.User { // want `SF016: converter function converts converters/layers/db.UserRow \(db\) into converters/layers/domain.User \(domain\): the layering rules don't allow it`
+	return domain.User{ID: r.ID, Name: r.Name}
+}
@@ -0,0 +1,42 @@
+package lenientmode
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+// Unread input fields are not reported.
+func ToDBSample(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    "const label",
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) model.Sample { // want `leaking fields:\n missing output fields: \[Currency\]`
+	return model.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+// Unexported converters are not checked.
+func fromDBSamplePartially(sample dbmodel.Sample) model.Sample {
+	return model.Sample{
+		ID: sample.ID,
+	}
+}
+
+// Explicitly given options take precedence over the preset.
+func (c *Converter) ToModelSample(sample dbmodel.Sample) model.Sample { // want `leaking fields:\n missing output fields: \[Label\]`
+	return model.Sample{
+		ID:       sample.ID,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+type Converter struct{}
@@ -0,0 +1,18 @@
+package limitsfunc
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID     string
+	Name   string
+	Amount int64
+	Unit   string
+}
+
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `missing input fields: \[sample.Label\]\n missing output fields: \[Name Amount Unit\]\n and 2 more missing fields`
+	return SampleDTO{
+		ID: sample.ID,
+	}
+}
@@ -0,0 +1,20 @@
+package limitsperfield
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID     string
+	Name   string
+	Amount int64
+	Unit   string
+}
+
+func ToSampleDTO( // want `and 2 more missing fields \(see -max-issues-per-func\)`
+	sample model.Sample, // want `missing input field sample.Label`
+) SampleDTO {
+	return SampleDTO{
+		ID: sample.ID,
+	} // want `missing output field Name` `missing output field Amount` `missing output field Unit`
+}
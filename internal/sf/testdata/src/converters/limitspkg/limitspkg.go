@@ -0,0 +1,30 @@
+package limitspkg // want `and 1 more findings in the package`
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID       string
+	Label    string
+	Price    int64
+	Currency string
+}
+
+// ToSampleDTO misses only an input field: it's omitted in favor of the missing output field below.
+func ToSampleDTO(sample model.Sample) SampleDTO {
+	return SampleDTO{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: "EUR",
+	}
+}
+
+func FromSampleDTO(dto SampleDTO) model.Sample { // want `missing output fields: \[Currency\]`
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+		Price: dto.Price,
+	}
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"log"
+	"log/slog"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) dbmodel.Sample { // want `missing input fields: \[sample.Currency\]`
+	log.Printf("converting sample %s (%s)", sample.Label, sample.Currency)
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample, logger *slog.Logger) model.Sample { // want `missing input fields: \[sample.Price\]`
+	logger.Info("converting sample", "price", sample.Price)
+	return model.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Currency: sample.Currency,
+	}
+}
@@ -0,0 +1,32 @@
+package mapping
+
+import "strings"
+
+type User struct {
+	ID       string
+	First    string
+	Last     string
+	Email    string
+	Internal string
+}
+
+type Profile struct {
+	ID       string
+	FullName string
+	Email    string
+	Source   string
+	Extra    string
+}
+
+func ToProfile(u User) Profile { // want `ID <- u.ID from \[ID\]; FullName <- u.First \+ " " \+ u.Last from \[First Last\]; Email <- strings.ToLower\(src.Email\) from \[Email\]; Source <- "users" const` `Internal read, to \[\]` `Email read, to \[Email\]` `Last read, to \[FullName\]` `First read, to \[FullName\]` `ID read, to \[ID\]`
+	src := u
+	p := Profile{
+		ID:       u.ID,
+		FullName: u.First + " " + u.Last,
+	}
+	p.Email = strings.ToLower(src.Email)
+	if u.Internal != "" {
+		p.Source = "users"
+	}
+	return p
+}
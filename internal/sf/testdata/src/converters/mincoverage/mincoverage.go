@@ -0,0 +1,21 @@
+package mincoverage
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+// Only Currency is leaking: 6 of 8 fields are used.
+func ToDBSample(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) model.Sample { // want `missing output fields: \[Label Price Currency\]\n coverage: 25%`
+	return model.Sample{
+		ID: sample.ID,
+	}
+}
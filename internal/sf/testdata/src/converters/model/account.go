@@ -0,0 +1,10 @@
+package model
+
+type Account struct {
+	ID    string
+	Email string
+	// Password is never exposed outside of the domain layer.
+	//sf:ignore
+	Password string
+	Nickname string
+}
@@ -0,0 +1,11 @@
+package model
+
+type Car struct {
+	ID    int
+	Model string
+}
+
+type Pet struct {
+	ID   int
+	Name string
+}
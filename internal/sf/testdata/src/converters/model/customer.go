@@ -0,0 +1,9 @@
+package model
+
+type Customer struct {
+	ID      int64
+	Name    string
+	Age     int
+	Company string
+	Rating  int64
+}
@@ -0,0 +1,8 @@
+package model
+
+import "time"
+
+type Event struct {
+	ID        string
+	CreatedAt time.Time
+}
@@ -0,0 +1,10 @@
+package model
+
+type Invoice struct {
+	ID     string
+	Amount int64
+	// Total is the gross amount.
+	//
+	// Deprecated: use Amount instead.
+	Total int64
+}
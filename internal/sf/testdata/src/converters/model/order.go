@@ -0,0 +1,6 @@
+package model
+
+type Order struct {
+	Number string
+	Total  int64
+}
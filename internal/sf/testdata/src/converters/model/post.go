@@ -0,0 +1,7 @@
+package model
+
+type Post struct {
+	ID    string
+	Title string
+	Body  string
+}
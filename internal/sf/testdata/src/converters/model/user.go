@@ -0,0 +1,8 @@
+package model
+
+type User struct {
+	ID    string
+	Name  string
+	Email string
+	Phone string
+}
@@ -0,0 +1,18 @@
+package nameheuristic
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func PostInvoice(invoice model.Invoice) dbmodel.LedgerRow { // want `missing input fields: \[invoice.Amount\]\n.*missing output fields: \[Amount\]`
+	return dbmodel.LedgerRow{
+		ID: invoice.ID,
+	}
+}
+
+// Same type in and out is not a conversion.
+func NormalizeSample(sample model.Sample) model.Sample {
+	sample.Label = ""
+	return sample
+}
@@ -0,0 +1,36 @@
+package naming
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserRow struct {
+	ID   string
+	Name string
+}
+
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name}
+}
+
+func UserFromUserRow(r *UserRow) *User {
+	return &User{ID: r.ID, Name: r.Name}
+}
+
+func ToUserRows(users []User) []UserRow {
+	rows := make([]UserRow, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, UserRow{ID: u.ID, Name: u.Name})
+	}
+	return rows
+}
+
+func ConvertUser(u User) UserRow { // want `SF014: converter function ConvertUser does not follow the naming convention: ToUserRow, UserRowFromUser`
+	return UserRow{ID: u.ID, Name: u.Name}
+}
+
+//nolint:stickyfields // legacy name kept for compatibility
+func MakeRow(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name}
+}
@@ -0,0 +1,13 @@
+package outside
+
+type Event struct {
+	ID string
+}
+
+type EventRow struct {
+	ID string
+}
+
+func ToEventRow(e Event) EventRow { // want `SF014: converter function ToEventRow is declared outside of converter packages \(/convert\$\)`
+	return EventRow{ID: e.ID}
+}
@@ -0,0 +1,24 @@
+package legacy
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+func ToSampleDTO(sample model.Sample) SampleDTO {
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
+
+func FromSampleDTO(dto SampleDTO) model.Sample { // want `SF001: converter function is leaking fields:\n missing output fields: \[Price Currency\]`
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+	}
+}
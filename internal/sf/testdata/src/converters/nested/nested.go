@@ -0,0 +1,24 @@
+package nested
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `SF002: converter function is leaking fields:\n missing input fields: \[sample.Price sample.Currency\]\n\n`
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
+
+func FromSampleDTO(dto SampleDTO) model.Sample {
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+	}
+}
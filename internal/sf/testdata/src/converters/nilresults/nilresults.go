@@ -0,0 +1,52 @@
+package nilresults
+
+import "encoding/json"
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserRow struct {
+	ID   string
+	Name string
+}
+
+func ToUserRow(u User) (row *UserRow) {
+	row.Name = u.Name // want `SF018: row.Name dereferences the named result before it's assigned`
+	row = &UserRow{ID: u.ID}
+	return row
+}
+
+func ToUserRowCopy(u User) (row *UserRow) {
+	row = &UserRow{ID: u.ID + row.ID, Name: u.Name} // want `SF018: row.ID dereferences the named result before it's assigned`
+	return row
+}
+
+func ToUserRowDeref(u User) (row *UserRow) {
+	*row = UserRow{ID: u.ID, Name: u.Name} // want `SF018: \*row dereferences the named result before it's assigned`
+	return
+}
+
+func ToUserRowDecoded(data []byte, u User) (row *UserRow, err error) {
+	if err = json.Unmarshal(data, &row); err != nil {
+		return nil, err
+	}
+	row.ID, row.Name = u.ID, u.Name
+	return row, nil
+}
+
+func ToUserRowDeferred(u User) (row *UserRow) {
+	defer func() {
+		row.Name = u.Name
+	}()
+	row = &UserRow{ID: u.ID}
+	return row
+}
+
+//nolint:stickyfields // panics on purpose, see the recovering caller
+func ToUserRowPanicking(u User) (row *UserRow) {
+	row.Name = u.Name
+	row = &UserRow{ID: u.ID}
+	return row
+}
@@ -0,0 +1,52 @@
+package nolint
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+func ToSampleDTO(sample model.Sample) SampleDTO { //nolint:stickyfields // prices are not exposed
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
+
+// FromSampleDTO is suppressed along with other linters.
+//
+//nolint:errcheck,stickyfields
+func FromSampleDTO(dto SampleDTO) model.Sample {
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+	}
+}
+
+//nolint:all
+func ToSampleDTOAll(sample model.Sample) SampleDTO {
+	return SampleDTO{ID: sample.ID, Label: sample.Label}
+}
+
+func ToSampleDTOBare(sample model.Sample) SampleDTO { //nolint
+	return SampleDTO{ID: sample.ID, Label: sample.Label}
+}
+
+var toSampleDTO = func(sample model.Sample) SampleDTO { //nolint:stickyfields
+	return SampleDTO{ID: sample.ID, Label: sample.Label}
+}
+
+// Directives of other linters don't apply.
+//
+//nolint:errcheck
+func FromSampleDTOOther(dto SampleDTO) model.Sample { // want `SF001: converter function is leaking fields`
+	return model.Sample{ID: dto.ID, Label: dto.Label}
+}
+
+//nolint:stickyfieldsextra
+func FromSampleDTOPrefix(dto SampleDTO) model.Sample { // want `SF001: converter function is leaking fields`
+	return model.Sample{ID: dto.ID, Label: dto.Label}
+}
@@ -0,0 +1,37 @@
+package nullable
+
+import (
+	"database/sql"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertCustomerFromDB(customer dbmodel.Customer) model.Customer {
+	return model.Customer{
+		ID:      customer.ID,
+		Name:    customer.Name.String,
+		Age:     int(customer.Age.Int64),
+		Company: customer.Company.String,
+		Rating:  customer.Rating.Int64,
+	}
+}
+
+func ConvertCustomerToDB(customer model.Customer) (result dbmodel.Customer) {
+	result.ID = customer.ID
+	result.Name = sql.NullString{String: customer.Name, Valid: customer.Name != ""}
+	result.Age.Int64 = int64(customer.Age)
+	result.Age.Valid = true
+	result.Company.String = customer.Company
+	result.Rating.Int64 = customer.Rating
+	return result
+}
+
+func ConvertCustomerFromDBPartial(customer dbmodel.Customer) model.Customer { // want `missing input fields: \[customer.Rating\]`
+	return model.Customer{
+		ID:      customer.ID,
+		Name:    customer.Name.String,
+		Age:     int(customer.Age.Int64),
+		Company: customer.Company.String,
+	}
+}
@@ -0,0 +1,26 @@
+package ormprofiles
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertCarFromDB(car *dbmodel.Car) model.Car {
+	return model.Car{
+		ID:    car.ID,
+		Model: car.Model,
+	}
+}
+
+func ConvertPetFromDB(pet *dbmodel.Pet) model.Pet {
+	return model.Pet{
+		ID:   pet.ID,
+		Name: pet.Name,
+	}
+}
+
+func ConvertPetToDB(pet model.Pet) *dbmodel.Pet { // want `missing output fields: \[Name\]`
+	return &dbmodel.Pet{
+		ID: pet.ID + len(pet.Name),
+	}
+}
@@ -0,0 +1,29 @@
+package partial
+
+type Credentials struct {
+	Login  string
+	Secret string
+	Token  string
+}
+
+type CredentialsRow struct {
+	Login    string
+	Checksum string
+}
+
+// ToCredentialsRow leaves secrets out of rows.
+//
+//sf:partial MissingInput=Secret,Token MissingOutput=Checksum
+func ToCredentialsRow(c Credentials) CredentialsRow {
+	return CredentialsRow{Login: c.Login}
+}
+
+//sf:partial MissingInput=Secret
+func ToCredentialsRowLeaking(c Credentials) CredentialsRow { // want `missing input fields: \[c.Token\]\n missing output fields: \[Checksum\]`
+	return CredentialsRow{Login: c.Login}
+}
+
+//sf:partial MissingInput=Checksum MissingOutput=Secret
+func FromCredentialsRow(row CredentialsRow) Credentials { // want `SF001: converter function is leaking fields:\n missing input fields: \[\]\n missing output fields: \[Token\]`
+	return Credentials{Login: row.Login}
+}
@@ -0,0 +1,12 @@
+package pathfilter
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDBLegacy(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID: sample.ID,
+	}
+}
@@ -0,0 +1,30 @@
+package perfield
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(
+	sample model.Sample, // want `missing input field sample.Currency`
+) dbmodel.Sample {
+	if sample.Price == 0 {
+		return dbmodel.Sample{
+			ID:    sample.ID,
+			Label: sample.Label,
+		} // want `missing output field Price` `missing output field Currency: did you mean sample.Currency\?`
+	}
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	} // want `missing output field Currency: did you mean sample.Currency\?`
+}
+
+func FromDBSample(sample dbmodel.Sample) (result model.Sample) {
+	result.ID = sample.ID
+	result.Label = sample.Label
+	result.Price = sample.Price
+	_ = sample.Currency
+	return result
+} // want `missing output field result.Currency: did you mean sample.Currency\?`
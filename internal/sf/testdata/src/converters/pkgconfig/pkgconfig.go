@@ -0,0 +1,29 @@
+// Package pkgconfig adapts models with methods and tolerates unread input fields.
+//
+//sf:config include-methods disable=SF002
+package pkgconfig
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID    string
+	Label string
+}
+
+type Adapter struct{}
+
+func (Adapter) ToSampleDTO(sample model.Sample) SampleDTO {
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
+
+func (Adapter) FromSampleDTO(dto SampleDTO) model.Sample { // want `SF001: converter function is leaking fields:\n missing output fields: \[Price Currency\]`
+	return model.Sample{
+		ID:    dto.ID,
+		Label: dto.Label,
+	}
+}
@@ -0,0 +1,57 @@
+package precise
+
+import "strings"
+
+type User struct {
+	ID        string
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+type UserDTO struct {
+	ID       string
+	FullName string
+	Email    string
+}
+
+// ToDTO reads the input through a local copy and a helper, and gets the ID set by a helper.
+func ToDTO(u User) UserDTO {
+	src := u
+	out := newDTO(src)
+	out.FullName = fullName(src)
+	if src.Email != "" {
+		out.Email = strings.ToLower(src.Email)
+	} else {
+		out.Email = "unknown"
+	}
+	return out
+}
+
+// newDTO sets the identity of DTOs only.
+//
+//sf:ignore
+func newDTO(u User) UserDTO {
+	return UserDTO{ID: u.ID}
+}
+
+func fullName(u User) string {
+	return u.FirstName + " " + u.LastName
+}
+
+// ToDTOPartial sets the email on one path only.
+func ToDTOPartial(u User) (UserDTO, error) { // want `missing output fields: \[Email\]`
+	if u.ID == "" {
+		return UserDTO{}, nil
+	}
+	if u.Email == "" {
+		return UserDTO{ID: u.ID, FullName: fullName(u)}, nil
+	}
+	return UserDTO{ID: u.ID, FullName: fullName(u), Email: u.Email}, nil
+}
+
+// FromDTO drops the last name.
+func FromDTO(d UserDTO) User { // want `missing output fields: \[LastName\]`
+	first, _, _ := strings.Cut(d.FullName, " ")
+	return User{ID: d.ID, FirstName: first, Email: d.Email}
+}
@@ -0,0 +1,45 @@
+package proto
+
+import (
+	"converters/model"
+	"converters/pb"
+)
+
+func ConvertUserToPB(user model.User) *pb.User {
+	out := &pb.User{
+		Id:   user.ID,
+		Name: user.Name,
+	}
+	if user.Email != "" {
+		out.Contact = &pb.User_Email{Email: user.Email}
+	} else {
+		out.Contact = &pb.User_Phone{Phone: user.Phone}
+	}
+	return out
+}
+
+func ConvertUserFromPB(user *pb.User) model.User {
+	return model.User{
+		ID:    user.GetId(),
+		Name:  user.GetName(),
+		Email: user.GetEmail(),
+		Phone: user.GetPhone(),
+	}
+}
+
+func ConvertUserFromPBSwitch(user *pb.User) (result model.User) { // want `unhandled oneof cases: \[user.Contact: User_Phone\]`
+	result.ID = user.Id
+	result.Name = user.Name
+	switch c := user.Contact.(type) {
+	case *pb.User_Email:
+		result.Email = c.Email
+	}
+	result.Phone = ""
+	return result
+}
+
+func ConvertUserToPBPartial(user model.User) *pb.User { // want `missing output fields: \[Name\]\n unhandled oneof cases: \[Contact: User_Email Contact: User_Phone\]`
+	return &pb.User{
+		Id: user.ID + user.Name + user.Email + user.Phone,
+	}
+}
@@ -0,0 +1,68 @@
+package purity
+
+import "strings"
+
+type User struct {
+	ID     string
+	Name   string
+	Tags   []string
+	Labels map[string]string
+}
+
+func (u *User) Normalize() {
+	u.Name = strings.TrimSpace(u.Name)
+}
+
+func (u User) DisplayName() string {
+	return strings.ToUpper(u.Name)
+}
+
+type UserRow struct {
+	ID     string
+	Name   string
+	Tags   []string
+	Labels map[string]string
+}
+
+func ToUserRow(u *User) UserRow {
+	u.Normalize()                // want `SF017: converter function calls u.Normalize with a pointer receiver on its input`
+	u.Name = u.DisplayName()     // want `SF017: converter function writes u.Name of its input`
+	u.Tags[0] = "first"          // want `SF017: converter function writes u.Tags\[0\] of its input`
+	delete(u.Labels, "internal") // want `SF017: converter function calls delete on u.Labels of its input`
+	return UserRow{ID: u.ID, Name: u.Name, Tags: u.Tags, Labels: u.Labels}
+}
+
+func ToUserRows(users []User) []UserRow {
+	rows := make([]UserRow, 0, len(users))
+	for i := range users {
+		users[i].ID = strings.ToLower(users[i].ID) // want `SF017: converter function writes users\[i\].ID of its input`
+		rows = append(rows, UserRow{ID: users[i].ID, Name: users[i].Name, Tags: users[i].Tags, Labels: users[i].Labels})
+	}
+	return rows
+}
+
+// Pure converters may reassign their input variable and mutate their output.
+func FromUserRow(r *UserRow) User {
+	if r == nil {
+		r = &UserRow{}
+	}
+	u := User{ID: r.ID, Name: r.Name, Tags: r.Tags, Labels: r.Labels}
+	u.Normalize()
+	u.Name += "!"
+	return u
+}
+
+// Inputs passed by value are copies: writing their fields changes nothing of the caller's,
+// unlike writing elements of their slices or maps.
+func ToUserRowCopy(u User) UserRow {
+	u.Normalize()
+	u.Name = u.DisplayName()
+	u.Tags[0] = "first" // want `SF017: converter function writes u.Tags\[0\] of its input`
+	return UserRow{ID: u.ID, Name: u.Name, Tags: u.Tags, Labels: u.Labels}
+}
+
+//nolint:stickyfields // normalizes the user in place on purpose
+func ToUserRowNormalized(u *User) UserRow {
+	u.Normalize()
+	return UserRow{ID: u.ID, Name: u.Name, Tags: u.Tags, Labels: u.Labels}
+}
@@ -0,0 +1,58 @@
+package reassign
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) (result dbmodel.Sample) {
+	tmp := dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+	tmp.Price = sample.Price
+	result = tmp
+	result.Currency = sample.Currency
+	return result
+}
+
+func FromDBSample(sample dbmodel.Sample) *model.Sample { // want `missing input fields: \[sample.Currency\]\n missing output fields: \[Currency\]`
+	base := model.Sample{
+		ID: sample.ID,
+	}
+	base.Label = sample.Label
+	out := &base
+	out.Price = sample.Price
+	return out
+}
+
+func ToDBSampleSpecial(sample model.Sample, special bool) (result *dbmodel.Sample) { // want `missing output fields: \[result.Currency\]`
+	result = &dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+	if special {
+		result = specialSample(sample.ID, sample.Price)
+	}
+	return result
+}
+
+func FromDBSampleWithHelper(sample dbmodel.Sample) (model.Sample, error) {
+	out, err := newSample(sample.ID, sample.Price)
+	if err != nil {
+		return model.Sample{}, err
+	}
+	out.Label = sample.Label
+	out.Currency = sample.Currency
+	return out, nil
+}
+
+func specialSample(id string, price int64) *dbmodel.Sample {
+	return &dbmodel.Sample{ID: id, Label: "special", Price: price}
+}
+
+func newSample(id string, price int64) (model.Sample, error) {
+	return model.Sample{ID: id, Price: price}, nil
+}
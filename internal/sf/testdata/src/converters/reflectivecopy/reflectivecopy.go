@@ -0,0 +1,19 @@
+package reflectivecopy
+
+import (
+	"github.com/jinzhu/copier"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertOrderToDB(order model.Order) (result dbmodel.Order) { // want `converter field coverage unknown: reflective copy via copier.Copy`
+	_ = copier.Copy(&result, &order)
+	return result
+}
+
+// Copies of other values don't cover fields of the input.
+func ConvertOrderToDBAudited(order model.Order, audit, prev *dbmodel.Order) dbmodel.Order { // want `missing input fields: \[order.Total\]\n missing output fields: \[Total\]`
+	_ = copier.Copy(audit, prev)
+	return dbmodel.Order{Number: order.Number}
+}
@@ -0,0 +1,21 @@
+package reflectivecopycovered
+
+import (
+	"github.com/jinzhu/copier"
+
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertOrderToDB(order model.Order) (result dbmodel.Order) {
+	_ = copier.Copy(&result, &order)
+	return result
+}
+
+func ConvertOrderToDBShadowed(order model.Order) (result dbmodel.Order) { // want `missing input fields: \[order.Number order.Total\]`
+	{
+		order := model.Order{Number: "draft"}
+		_ = copier.Copy(&result, &order)
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package registry
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+var invoiceConverters = map[string]func(model.Invoice) dbmodel.LedgerRow{ // want `converter invoiceToLedgerUnregistered is not registered in the registry`
+	"full":  invoiceToLedger,
+	"short": invoiceToLedgerShort,
+	"inline": func(invoice model.Invoice) dbmodel.LedgerRow { // want `missing input fields: \[invoice.Amount\]`
+		return dbmodel.LedgerRow{ID: invoice.ID, Amount: 0}
+	},
+}
+
+func invoiceToLedger(invoice model.Invoice) dbmodel.LedgerRow {
+	return dbmodel.LedgerRow{ID: invoice.ID, Amount: invoice.Amount}
+}
+
+func invoiceToLedgerShort(invoice model.Invoice) dbmodel.LedgerRow { // want `missing output fields: \[Amount\]`
+	return dbmodel.LedgerRow{ID: invoice.ID + string(rune(invoice.Amount))}
+}
+
+func invoiceToLedgerUnregistered(invoice model.Invoice) dbmodel.LedgerRow {
+	return dbmodel.LedgerRow{ID: invoice.ID}
+}
@@ -0,0 +1,15 @@
+package related
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserDTO struct {
+	ID    string
+	Email string
+}
+
+func ToDTO(u User) UserDTO { // want `converter function is leaking fields`
+	return UserDTO{ID: u.ID}
+}
@@ -0,0 +1,110 @@
+package requiredtag
+
+import (
+	"encoding/json"
+
+	"github.com/jinzhu/copier"
+)
+
+type Order struct {
+	ID       string
+	TenantID string `sf:"required"`
+	Note     string
+}
+
+func (o Order) GetTenantID() string {
+	return o.TenantID
+}
+
+type OrderRow struct {
+	ID       string
+	TenantID string `json:"tenant_id" sf:"required"`
+	Note     string
+}
+
+func ToOrderRow(o Order) OrderRow {
+	return OrderRow{ID: o.ID, TenantID: o.TenantID, Note: o.Note}
+}
+
+func ToOrderRowGetter(o Order) OrderRow { // want `missing input fields: \[o.TenantID\]`
+	return OrderRow{ID: o.ID, TenantID: o.GetTenantID(), Note: o.Note}
+}
+
+//sf:ignore TenantID
+func ToOrderRowIgnored(o Order) OrderRow { // want `missing input fields: \[o.TenantID\]\n missing output fields: \[TenantID\]`
+	return OrderRow{ID: o.ID, Note: o.Note}
+}
+
+//sf:partial MissingInput=Note,TenantID MissingOutput=Note,TenantID
+func ToOrderRowPartial(o Order) OrderRow { // want `missing input fields: \[o.TenantID\]\n missing output fields: \[TenantID\]`
+	return OrderRow{ID: o.ID}
+}
+
+//sf:partial MissingInput=Note MissingOutput=Note
+func ToOrderRowPartialTenant(o Order) OrderRow {
+	return OrderRow{ID: o.ID, TenantID: o.TenantID}
+}
+
+//sf:ignore
+func ToOrderRowIgnoredAll(o Order) OrderRow { // want `missing input fields: \[o.TenantID\]\n missing output fields: \[TenantID\]`
+	return OrderRow{ID: o.ID}
+}
+
+//sf:ignore
+func ToOrderRowIgnoredAllTenant(o Order) OrderRow {
+	return OrderRow{TenantID: o.TenantID}
+}
+
+func ToOrderRowNolint(o Order) OrderRow { //nolint:stickyfields // want `missing input fields: \[o.TenantID\]\n missing output fields: \[\]`
+	return OrderRow{ID: o.ID, TenantID: o.GetTenantID()}
+}
+
+func ToOrderRowCopied(o Order) (row OrderRow) { // want `missing input fields: \[o.TenantID\]\n missing output fields: \[row.TenantID\]`
+	_ = copier.Copy(&row, &o)
+	return row
+}
+
+func ToOrderRowCopiedTenant(o Order) (row OrderRow) {
+	_ = copier.Copy(&row, &o)
+	row.TenantID = o.TenantID
+	return row
+}
+
+func ToOrderRowJSON(o Order) (row OrderRow, err error) { // want `missing input fields: \[o.TenantID\]\n missing output fields: \[row.TenantID\]`
+	b, err := json.Marshal(o)
+	if err != nil {
+		return row, err
+	}
+	err = json.Unmarshal(b, &row)
+	return row, err
+}
+
+type Tenancy struct {
+	TenantID string `sf:"required"`
+}
+
+type Invoice struct {
+	Tenancy
+	ID string
+}
+
+type InvoiceRow struct {
+	Tenancy
+	ID string
+}
+
+func ToInvoiceRow(i Invoice) InvoiceRow {
+	return InvoiceRow{Tenancy: i.Tenancy, ID: i.ID}
+}
+
+//sf:ignore Tenancy
+func ToInvoiceRowIgnored(i Invoice) InvoiceRow { // want `missing input fields: \[i.TenantID\]\n missing output fields: \[TenantID\]`
+	return InvoiceRow{ID: i.ID}
+}
+
+//sf:ignore Tenancy
+func ToInvoiceRowPromoted(i Invoice) InvoiceRow {
+	row := InvoiceRow{ID: i.ID}
+	row.TenantID = i.TenantID
+	return row
+}
@@ -0,0 +1,55 @@
+package reverse
+
+type User struct {
+	ID   string
+	Name string
+}
+
+type UserRow struct {
+	ID   string
+	Name string
+}
+
+func ToUserRow(u User) UserRow {
+	return UserRow{ID: u.ID, Name: u.Name}
+}
+
+func FromUserRow(r *UserRow) *User {
+	return &User{ID: r.ID, Name: r.Name}
+}
+
+type Order struct {
+	ID    string
+	Total int
+}
+
+type OrderRow struct {
+	ID    string
+	Total int
+}
+
+func ToOrderRow(o Order) OrderRow { // want `SF012: converter function has no reverse converter from OrderRow to Order`
+	return OrderRow{ID: o.ID, Total: o.Total}
+}
+
+// Converters of collections are not paired.
+func ToOrderRows(orders []Order) []OrderRow {
+	rows := make([]OrderRow, 0, len(orders))
+	for _, o := range orders {
+		rows = append(rows, OrderRow{ID: o.ID, Total: o.Total})
+	}
+	return rows
+}
+
+type Audit struct {
+	ID string
+}
+
+type AuditRow struct {
+	ID string
+}
+
+//sf:ignore
+func ToAuditRow(a Audit) AuditRow {
+	return AuditRow{}
+}
@@ -0,0 +1,66 @@
+package roundtrip
+
+import "strings"
+
+type User struct {
+	ID    string
+	Name  string
+	Email string
+	Age   int
+}
+
+type UserRow struct {
+	ID       string
+	FullName string
+	Mail     string
+	Age      int
+	Version  int
+}
+
+func ToRow(u User) UserRow { // want `SF011: converter function loses fields on the round trip with FromRow: \[u.Email u.Age\]`
+	return UserRow{ID: u.ID, FullName: u.Name, Mail: u.Email, Age: u.Age}
+}
+
+func FromRow(r UserRow) User {
+	return User{
+		ID:    r.ID,
+		Name:  r.FullName,
+		Email: strings.ToLower(r.FullName), // copy-paste bug: Mail never comes back
+	}
+}
+
+type Account struct {
+	ID    string
+	Owner string
+}
+
+type AccountRow struct {
+	ID      string
+	OwnerID string
+}
+
+func ToAccountRow(a *Account) *AccountRow {
+	return &AccountRow{ID: a.ID, OwnerID: a.Owner}
+}
+
+func FromAccountRow(r *AccountRow) *Account {
+	acc := &Account{}
+	acc.ID = r.ID
+	acc.Owner = r.OwnerID
+	return acc
+}
+
+type Event struct {
+	ID   string
+	Kind string
+}
+
+type EventRow struct {
+	ID   string
+	Kind string
+}
+
+// Converters without a reverse converter have no round trips.
+func ToEventRow(e Event) EventRow {
+	return EventRow{ID: e.ID}
+}
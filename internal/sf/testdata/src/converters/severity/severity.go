@@ -0,0 +1,18 @@
+package severity
+
+import (
+	"converters/model"
+)
+
+type SampleDTO struct {
+	ID      string
+	Label   string
+	Comment string
+}
+
+func ToSampleDTO(sample model.Sample) SampleDTO { // want `SF001, SF002: converter function is leaking fields:`
+	return SampleDTO{
+		ID:    sample.ID,
+		Label: sample.Label,
+	}
+}
@@ -0,0 +1,34 @@
+package shadow
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) dbmodel.Sample { // want `missing input fields: \[sample.Currency\]`
+	out := dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+	if out.Price > 0 {
+		// The default sample shadows the input one.
+		sample := struct{ Currency string }{Currency: "EUR"}
+		out.Currency = sample.Currency
+	}
+	return out
+}
+
+func FromDBSample(sample dbmodel.Sample) model.Sample {
+	out := model.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+	for _, currency := range []string{"EUR", "USD"} {
+		if currency == sample.Currency {
+			out.Currency = currency
+		}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package shadowwrites
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+// The preview shadowing the result is written before it, not the result itself.
+func ToDBSample(sample model.Sample) (result dbmodel.Sample) {
+	if sample.Price == 0 {
+		result := dbmodel.Sample{ID: sample.ID}
+		result.Label = "free"
+		preview(result)
+	}
+	result.ID = sample.ID
+	result.Label = sample.Label
+	result.Price = sample.Price
+	result.Currency = sample.Currency
+	return result
+}
+
+// The default shadowing the input doesn't map its label.
+func ToDBSampleDefaults(sample model.Sample) (result dbmodel.Sample) { // want `hardcodes output fields instead of mapping input ones: \[result.Label\]` `missing input fields: \[sample.Label\]`
+	result.ID = sample.ID
+	result.Label = "const label"
+	result.Price = sample.Price
+	result.Currency = sample.Currency
+	if sample.Currency == "" {
+		sample := struct{ Label, Currency string }{Label: "default", Currency: "EUR"}
+		result.Currency = sample.Currency + sample.Label
+	}
+	return result
+}
+
+type Person struct {
+	ID        int64
+	FirstName string
+	LastName  string
+}
+
+type PersonRecord struct {
+	ID        int64
+	FirstName string
+	LastName  string
+}
+
+// Records of anonymous persons are named after a placeholder person shadowing the input.
+//
+//sf:ignore FirstName
+func PersonToRecord(person Person) (record PersonRecord) {
+	record.ID = person.ID
+	record.LastName = person.LastName
+	if person.ID == 0 {
+		person := Person{LastName: "Anonymous"}
+		record.FirstName = person.LastName
+	}
+	return record
+}
+
+func preview(dbmodel.Sample) {}
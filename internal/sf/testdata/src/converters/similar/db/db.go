@@ -0,0 +1,22 @@
+package db
+
+import "time"
+
+type UserRow struct {
+	ID        string
+	NAME      string
+	Email     string
+	CreatedAt time.Time
+	Version   int
+}
+
+type OrderRow struct {
+	ID     string
+	Total  string
+	Status bool
+	Note   string
+}
+
+type TagRow struct {
+	Name string
+}
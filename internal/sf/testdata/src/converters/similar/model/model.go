@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type User struct {
+	ID        string
+	Name      string
+	Email     string
+	CreatedAt time.Time
+}
+
+type Order struct {
+	ID     string
+	Total  int
+	Status string
+	Note   string
+}
+
+type Tag struct {
+	Name string
+}
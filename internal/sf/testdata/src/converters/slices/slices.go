@@ -0,0 +1,60 @@
+package slices
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSamples(samples []model.Sample) []dbmodel.Sample {
+	var out []dbmodel.Sample
+	for _, s := range samples {
+		out = append(out, dbmodel.Sample{
+			ID:       s.ID,
+			Label:    s.Label,
+			Price:    s.Price,
+			Currency: s.Currency,
+		})
+	}
+	return out
+}
+
+func FromDBSamples(samples []*dbmodel.Sample) []*model.Sample { // want `missing input fields: \[samples.Currency\]\n missing output fields: \[Currency\]`
+	out := make([]*model.Sample, 0, len(samples))
+	for i := range samples {
+		s := samples[i]
+		out = append(out, &model.Sample{
+			ID:    s.ID,
+			Label: s.Label,
+			Price: s.Price,
+		})
+	}
+	return out
+}
+
+func ToDBSamplesIndexed(samples []model.Sample) []dbmodel.Sample { // want `missing input fields: \[samples.Label\]\n missing output fields: \[Label\]`
+	result := make([]dbmodel.Sample, len(samples))
+	for i, sample := range samples {
+		result[i] = dbmodel.Sample{
+			ID:       sample.ID,
+			Price:    sample.Price,
+			Currency: sample.Currency,
+		}
+	}
+	return result
+}
+
+func ToDBSamplesValidated(samples []model.Sample) []dbmodel.Sample {
+	for _, s := range samples {
+		_ = s.ID
+	}
+	var out []dbmodel.Sample
+	for _, s := range samples {
+		out = append(out, dbmodel.Sample{
+			ID:       s.ID,
+			Label:    s.Label,
+			Price:    s.Price,
+			Currency: s.Currency,
+		})
+	}
+	return out
+}
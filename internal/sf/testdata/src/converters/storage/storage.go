@@ -0,0 +1,16 @@
+package storage
+
+type Record interface {
+	Key() string
+}
+
+type SampleRecord struct {
+	ID       string
+	Label    string
+	Price    int64
+	Currency string
+}
+
+func (r *SampleRecord) Key() string {
+	return r.ID
+}
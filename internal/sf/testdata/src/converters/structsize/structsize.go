@@ -0,0 +1,52 @@
+package structsize
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type MoneyDTO struct {
+	Amount   int64
+	Currency string
+}
+
+// Too trivial to be checked.
+func MoneyToDTO(money Money) MoneyDTO {
+	return MoneyDTO{Amount: money.Amount}
+}
+
+func ToDBSample(sample model.Sample) dbmodel.Sample { // want `missing output fields: \[Currency\]`
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price + int64(len(sample.Currency)),
+	}
+}
+
+type Profile struct {
+	ID        int64
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	Country   string
+}
+
+type ProfileDTO struct {
+	ID        int64
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	Country   string
+}
+
+// Too large to be checked.
+func ProfileToDTO(profile Profile) ProfileDTO {
+	return ProfileDTO{ID: profile.ID}
+}
@@ -0,0 +1,41 @@
+package suggest
+
+import (
+	"converters/model"
+)
+
+type Person struct {
+	ID        int64
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+type PersonDTO struct {
+	ID       int64
+	Name     string
+	Surname  string
+	EMail    string
+	Verified bool
+}
+
+func PersonToDTO(person Person) PersonDTO { // want `missing output fields: \[Surname EMail Verified\]\n EMail: did you mean person.Email\?`
+	return PersonDTO{
+		ID:   person.ID,
+		Name: person.FirstName + " " + person.LastName,
+	}
+}
+
+func ToSampleDTO(sample model.Sample) (result SampleDTO) { // want `missing output fields: \[result.Currency\]\n result.Currency: did you mean sample.Currency\?`
+	result.ID = sample.ID
+	result.Label = sample.Label
+	result.Price = sample.Price
+	return result
+}
+
+type SampleDTO struct {
+	ID       string
+	Label    string
+	Price    int64
+	Currency string
+}
@@ -0,0 +1,30 @@
+package suppress
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+// ToDBSample converts the sample partially.
+func ToDBSample(sample model.Sample) dbmodel.Sample { // want `missing input fields: \[sample.Currency\]\n missing output fields: \[Price Currency\]`
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label + string(rune(sample.Price)),
+	}
+}
+
+// FromDBSample converts the sample without currency on purpose.
+//
+//sf:ignore Currency
+func FromDBSample(sample dbmodel.Sample) model.Sample {
+	return model.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price,
+	}
+}
+
+//sf:ignore
+func ToDBSampleID(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{ID: sample.ID}
+}
@@ -0,0 +1,32 @@
+package swapped
+
+type Person struct {
+	ID        int64
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+type PersonRecord struct {
+	ID        int64
+	FirstName string
+	LastName  string
+	Contact   string
+}
+
+func PersonToRecord(person Person) (record PersonRecord) { // want `possibly swaps fields: \[record.FirstName = person.LastName \(person.FirstName unused\)\]` `missing input fields: \[person.FirstName\]`
+	record.ID = person.ID
+	record.FirstName = person.LastName
+	record.LastName = person.LastName
+	record.Contact = person.Email
+	return record
+}
+
+func RecordToPerson(record PersonRecord) Person {
+	return Person{
+		ID:        record.ID,
+		FirstName: record.FirstName,
+		LastName:  record.LastName,
+		Email:     record.Contact,
+	}
+}
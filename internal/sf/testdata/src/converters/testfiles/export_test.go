@@ -0,0 +1,14 @@
+package testfiles
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ConvertSampleToDB(sample model.Sample) dbmodel.Sample { // want `missing output fields: \[Currency\]`
+	return dbmodel.Sample{
+		ID:    sample.ID,
+		Label: sample.Label,
+		Price: sample.Price + int64(len(sample.Currency)),
+	}
+}
@@ -0,0 +1,2 @@
+// Package testfiles keeps its converters in test files only.
+package testfiles
@@ -0,0 +1,2 @@
+// Package testfilesskipped keeps its converters in test files only.
+package testfilesskipped
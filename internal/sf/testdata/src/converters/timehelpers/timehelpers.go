@@ -0,0 +1,41 @@
+package timehelpers
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"converters/model"
+	"converters/pb"
+)
+
+func ConvertEventToPB(event model.Event) *pb.Event {
+	return &pb.Event{
+		Id:        event.ID,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+	}
+}
+
+func ConvertEventFromPB(event *pb.Event) model.Event {
+	return model.Event{
+		ID:        event.GetId(),
+		CreatedAt: event.GetCreatedAt().AsTime(),
+	}
+}
+
+func ConvertEventFromPBDirect(event *pb.Event) (result model.Event) {
+	result.ID = event.Id
+	result.CreatedAt = event.CreatedAt.AsTime()
+	return result
+}
+
+func ConvertEventFromPBPartial(event *pb.Event) model.Event { // want `missing input fields: \[event.CreatedAt\]\n missing output fields: \[CreatedAt\]`
+	return model.Event{
+		ID: event.GetId(),
+	}
+}
+
+// timeToPB is a helper between well-known types, not a model converter.
+func timeToPB(t time.Time) *timestamppb.Timestamp {
+	return timestamppb.New(t)
+}
@@ -0,0 +1,19 @@
+package unkeyed
+
+import (
+	"converters/dbmodel"
+	"converters/model"
+)
+
+func ToDBSample(sample model.Sample) dbmodel.Sample {
+	return dbmodel.Sample{
+		ID:       sample.ID,
+		Label:    sample.Label,
+		Price:    sample.Price,
+		Currency: sample.Currency,
+	}
+}
+
+func FromDBSample(sample dbmodel.Sample) *model.Sample {
+	return &model.Sample{sample.ID, sample.Label, sample.Price, sample.Currency} // want `unkeyed composite literal of converters/model.Sample: prefer keyed fields`
+}
@@ -0,0 +1,12 @@
+// Package pgtype is a stub of github.com/jackc/pgx/v5/pgtype used by the analyzer tests.
+package pgtype
+
+type Text struct {
+	String string
+	Valid  bool
+}
+
+type Int8 struct {
+	Int64 int64
+	Valid bool
+}
@@ -0,0 +1,12 @@
+// Package proto is a stub of google.golang.org/protobuf/proto used by the analyzer tests.
+package proto
+
+type Message interface {
+	ProtoReflect()
+}
+
+func Clone(m Message) Message {
+	return m
+}
+
+func Merge(dst, src Message) {}
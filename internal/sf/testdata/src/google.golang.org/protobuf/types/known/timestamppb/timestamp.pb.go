@@ -0,0 +1,19 @@
+// Package timestamppb is a stub of google.golang.org/protobuf/types/known/timestamppb used by the analyzer tests.
+package timestamppb
+
+import "time"
+
+type Timestamp struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func (x *Timestamp) ProtoReflect() {}
+
+func New(t time.Time) *Timestamp {
+	return &Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+func (x *Timestamp) AsTime() time.Time {
+	return time.Unix(x.Seconds, int64(x.Nanos)).UTC()
+}
@@ -0,0 +1,11 @@
+package gorm
+
+import "time"
+
+// Model is a stub of gorm.Model used by the analyzer tests.
+type Model struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time `gorm:"index"`
+}
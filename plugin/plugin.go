@@ -0,0 +1,98 @@
+// Package plugin integrates the stickyfields analyzer with golangci-lint's module plugin system.
+//
+// Add the module to .custom-gcl.yml:
+//
+//	version: v1.62.0
+//	plugins:
+//	  - module: github.com/amberpixels/go-stickyfields
+//	    import: github.com/amberpixels/go-stickyfields/plugin
+//	    version: latest
+//
+// and enable the linter in .golangci.yml, with options keyed by flag names of the analyzer:
+//
+//	linters:
+//	  enable:
+//	    - stickyfields
+//	linters-settings:
+//	  custom:
+//	    stickyfields:
+//	      type: module
+//	      settings:
+//	        config: .stickyfields.yaml
+//	        options:
+//	          mode: strict
+//	          disable: [SF004]
+//	          map: [model.Post.Body=dbmodel.Post.Content]
+//
+// golangci-lint lowercases keys of settings, so field mappings are better given as a list
+// than as a map of fields.
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+func init() {
+	register.Plugin("stickyfields", newPlugin)
+}
+
+// Settings are the settings of the linter in .golangci.yml.
+type Settings struct {
+	// Config is a YAML or TOML configuration file of the analyzer.
+	Config string `json:"config"`
+	// Options are keyed by flag names of the analyzer and take precedence over the Config file.
+	Options map[string]any `json:"options"`
+}
+
+// New creates the analyzers configured by the settings decoded from .golangci.yml.
+func New(settings any) ([]*analysis.Analyzer, error) {
+	s, err := register.DecodeSettings[Settings](settings)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := sf.DefaultConfig()
+	// golangci-lint prints source lines of issues on its own.
+	cfg.Pretty = false
+	analyzer := sf.NewAnalyzer(cfg)
+
+	if err := sf.ApplySettings(&analyzer.Flags, s.Options); err != nil {
+		return nil, err
+	}
+	if s.Config != "" {
+		options, err := sf.ReadConfigFile(s.Config)
+		if err != nil {
+			return nil, err
+		}
+		if err := sf.ApplySettings(&analyzer.Flags, options); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Config, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return []*analysis.Analyzer{analyzer}, nil
+}
+
+// plugin is the linter plugin of golangci-lint.
+type plugin struct {
+	settings any
+}
+
+func newPlugin(settings any) (register.LinterPlugin, error) {
+	return plugin{settings: settings}, nil
+}
+
+func (p plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	return New(p.settings)
+}
+
+// GetLoadMode requires type information, as converters are recognized by their types.
+func (plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,46 @@
+package plugin_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/amberpixels/go-stickyfields/plugin"
+)
+
+func TestNew(t *testing.T) {
+	testdata, err := filepath.Abs(filepath.Join("..", "internal", "sf", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Settings as decoded by golangci-lint, with lowercased keys.
+	analyzers, err := plugin.New(map[string]any{
+		"options": map[string]any{
+			"disable":  []any{"SF002"},
+			"severity": map[string]any{"sf001": "error"},
+			// Regexps may contain commas.
+			"include": []any{`^converters/\w{2,5}$`},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := analysistest.Run(t, testdata, analyzers[0], "converters/codes")
+	if len(results[0].Diagnostics) == 0 {
+		t.Error("no diagnostics of the included package")
+	}
+	for _, d := range results[0].Diagnostics {
+		if d.Category != "SF001" {
+			t.Errorf("unexpected category %q of %q", d.Category, d.Message)
+		}
+	}
+
+	if _, err := plugin.New(map[string]any{"bogus": true}); err == nil {
+		t.Error("unknown setting accepted")
+	}
+	if _, err := plugin.New(map[string]any{"options": map[string]any{"bogus": true}}); err == nil {
+		t.Error("unknown option accepted")
+	}
+}
@@ -0,0 +1,65 @@
+// Package stickyfields provides analyzers of converter functions ensuring all fields are mapped.
+//
+// Analyzer reports all findings. The sub-checks (LeakingFields, HardcodedFields, ...) report
+// findings of some codes only, so drivers can pick and choose among them; All returns them all.
+//
+// Tools of their own can create configured analyzers with NewAnalyzer, or validate single
+// functions from passes of their analyzers with Candidates and ValidateFunc.
+package stickyfields
+
+import (
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/amberpixels/go-stickyfields/internal/sf"
+)
+
+// Analyzer reports all findings of converter functions.
+var Analyzer = sf.Analyzer
+
+// Sub-checks of Analyzer.
+var (
+	LeakingFields          = newCheck("leakingfields")
+	HardcodedFields        = newCheck("hardcodedfields")
+	DuplicateWrites        = newCheck("duplicatewrites")
+	SwappedFields          = newCheck("swappedfields")
+	UnkeyedLiterals        = newCheck("unkeyedliterals")
+	UnregisteredConverters = newCheck("unregisteredconverters")
+	RoundTrips             = newCheck("roundtrips")
+	MissingReverse         = newCheck("missingreverse")
+	Naming                 = newCheck("naming")
+	DuplicateConverters    = newCheck("duplicateconverters")
+	Layering               = newCheck("layering")
+	Purity                 = newCheck("purity")
+	NilResults             = newCheck("nilresults")
+	Errors                 = newCheck("errors")
+)
+
+// All returns the sub-checks of Analyzer.
+func All() []*analysis.Analyzer {
+	return []*analysis.Analyzer{
+		LeakingFields,
+		HardcodedFields,
+		DuplicateWrites,
+		SwappedFields,
+		UnkeyedLiterals,
+		UnregisteredConverters,
+		RoundTrips,
+		MissingReverse,
+		Naming,
+		DuplicateConverters,
+		Layering,
+		Purity,
+		NilResults,
+		Errors,
+	}
+}
+
+// newCheck creates the analyzer of the named sub-check with the default configuration.
+func newCheck(name string) *analysis.Analyzer {
+	for _, check := range sf.Checks {
+		if check.Name == name {
+			return sf.NewCheckAnalyzer(check, sf.DefaultConfig())
+		}
+	}
+	panic("unknown check " + name)
+}
@@ -0,0 +1,220 @@
+// Package stickyfieldstest asserts at run time what stickyfields checks statically: converters set
+// every field of their output. It complements the analyzer where it can't see field accesses,
+// e.g. for reflective copies or converters loaded from plugins:
+//
+//	func TestToUserRow(t *testing.T) {
+//		stickyfieldstest.AssertFullyMapped(t, convert.ToUserRow, stickyfieldstest.Ignore("Version"))
+//	}
+//
+// Inputs are filled with non-zero values by reflection (see Populate), so fields of types that can't be
+// populated that way (e.g. interfaces, or enums validated by the converter) need values given with Use.
+package stickyfieldstest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxDepth limits how many pointers, slices and maps are followed when populating values,
+// for recursive types.
+const maxDepth = 3
+
+// TB is the part of testing.TB used by assertions.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Option customizes how values are populated and checked.
+type Option func(*options)
+
+type options struct {
+	ignored []string
+	values  []reflect.Value
+}
+
+// Ignore excludes output fields from the check, e.g. fields set by the database. Fields of nested
+// structs are given by their path (e.g. Address.Zip), and ignoring a field ignores the fields within it.
+func Ignore(fields ...string) Option {
+	return func(o *options) {
+		o.ignored = append(o.ignored, fields...)
+	}
+}
+
+// Use populates values of the types of the given values with them: fields of interface types get
+// the first value implementing them, fields of other types the value of their type.
+func Use(values ...any) Option {
+	return func(o *options) {
+		for _, v := range values {
+			o.values = append(o.values, reflect.ValueOf(v))
+		}
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// AssertFullyMapped converts a populated input (see Populate) and reports output fields left at their
+// zero values (see ZeroFields). It returns the output for further assertions.
+func AssertFullyMapped[In, Out any](t TB, convert func(In) Out, opts ...Option) Out {
+	t.Helper()
+
+	var in In
+	Populate(&in, opts...)
+	out := convert(in)
+	if zero := ZeroFields(out, opts...); len(zero) > 0 {
+		t.Errorf("converting a populated %T into %T left fields unset: %s", in, out, strings.Join(zero, ", "))
+	}
+	return out
+}
+
+// Populate sets every exported field of the struct v points to, and fields of structs within it,
+// to non-zero values distinct from each other. Pointers, slices and maps get a single element.
+func Populate(v any, opts ...Option) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		panic(fmt.Sprintf("stickyfieldstest: Populate of non-pointer %T", v))
+	}
+	p := &populator{options: newOptions(opts)}
+	p.populate(rv.Elem(), 0)
+}
+
+// populator numbers populated values, so they differ from each other.
+type populator struct {
+	*options
+	n int
+}
+
+func (p *populator) populate(v reflect.Value, depth int) {
+	for _, value := range p.values {
+		if v.Kind() == reflect.Interface && value.Type().Implements(v.Type()) || value.Type() == v.Type() {
+			v.Set(value)
+			return
+		}
+	}
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		p.n++
+		v.Set(reflect.ValueOf(time.Date(2001, 2, 3, 4, 5, p.n, 0, time.UTC)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.n++
+		v.SetInt(int64(p.n%100 + 1))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		p.n++
+		v.SetUint(uint64(p.n%100 + 1))
+	case reflect.Float32, reflect.Float64:
+		p.n++
+		v.SetFloat(float64(p.n) + 0.5)
+	case reflect.Complex64, reflect.Complex128:
+		p.n++
+		v.SetComplex(complex(float64(p.n), 1))
+	case reflect.String:
+		p.n++
+		v.SetString(fmt.Sprintf("stickyfields-%d", p.n))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				p.populate(f, depth)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			p.populate(v.Index(i), depth)
+		}
+	case reflect.Pointer:
+		if depth < maxDepth {
+			v.Set(reflect.New(v.Type().Elem()))
+			p.populate(v.Elem(), depth+1)
+		}
+	case reflect.Slice:
+		if depth < maxDepth {
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			p.populate(v.Index(0), depth+1)
+		}
+	case reflect.Map:
+		if depth < maxDepth {
+			key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			p.populate(key, depth+1)
+			p.populate(elem, depth+1)
+			v.Set(reflect.MakeMap(v.Type()))
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
+
+// ZeroFields returns paths of exported fields of the struct v (or v points to) left at their zero values,
+// e.g. Name or Address.Zip. Fields of embedded structs are named as promoted fields, and all fields are
+// returned for a nil pointer. Structs without exported fields (e.g. time.Time) are checked as a whole.
+func ZeroFields(v any, opts ...Option) []string {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !hasExportedFields(rv.Type()) {
+		panic(fmt.Sprintf("stickyfieldstest: ZeroFields of %T, not a struct with exported fields", v))
+	}
+	var zero []string
+	zeroFields(rv, "", newOptions(opts).ignored, &zero)
+	return zero
+}
+
+func zeroFields(v reflect.Value, prefix string, ignored []string, zero *[]string) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+		} else {
+			v = v.Elem()
+		}
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		f := v.Field(i)
+		if field.Anonymous && hasExportedFields(field.Type) {
+			zeroFields(f, prefix, ignored, zero)
+			continue
+		}
+
+		path := prefix + field.Name
+		switch {
+		case isIgnored(path, ignored):
+		case hasExportedFields(field.Type) && !(f.Kind() == reflect.Pointer && f.IsNil()):
+			zeroFields(f, path+".", ignored, zero)
+		case f.IsZero():
+			*zero = append(*zero, path)
+		}
+	}
+}
+
+// hasExportedFields reports whether t is a struct (or a pointer to one) with exported fields.
+func hasExportedFields(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return slices.ContainsFunc(reflect.VisibleFields(t), func(f reflect.StructField) bool {
+		return f.IsExported()
+	})
+}
+
+// isIgnored reports whether the field or a field containing it is ignored.
+func isIgnored(path string, ignored []string) bool {
+	return slices.ContainsFunc(ignored, func(field string) bool {
+		return path == field || strings.HasPrefix(path, field+".")
+	})
+}
@@ -0,0 +1,115 @@
+package stickyfieldstest_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/amberpixels/go-stickyfields/stickyfieldstest"
+)
+
+type Status interface{ Valid() bool }
+
+type active struct{}
+
+func (active) Valid() bool { return true }
+
+type Base struct {
+	ID      int
+	Created time.Time
+}
+
+type User struct {
+	Base
+	Name    string
+	Age     uint8
+	Tags    []string
+	Address *Address
+	Status  Status
+	secret  string
+}
+
+type Address struct {
+	City, Zip string
+}
+
+type UserRow struct {
+	Base
+	Name    string
+	Age     int
+	Tags    []string
+	Address Address
+	Status  bool
+	Version int
+}
+
+func toUserRow(u User) UserRow {
+	return UserRow{
+		Base:    u.Base,
+		Name:    u.Name,
+		Age:     int(u.Age),
+		Tags:    u.Tags,
+		Address: Address{City: u.Address.City},
+		Status:  u.Status.Valid(),
+	}
+}
+
+// recorder records errors of assertions.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFullyMapped(t *testing.T) {
+	var r recorder
+	out := stickyfieldstest.AssertFullyMapped(&r, toUserRow,
+		stickyfieldstest.Use(active{}), stickyfieldstest.Ignore("Version"))
+	want := []string{"converting a populated stickyfieldstest_test.User into stickyfieldstest_test.UserRow left fields unset: Address.Zip"}
+	if !slices.Equal(r.errors, want) {
+		t.Errorf("errors = %q, want %q", r.errors, want)
+	}
+	if out.Name == "" || out.Created.IsZero() {
+		t.Errorf("output of a populated input = %+v", out)
+	}
+
+	r = recorder{}
+	stickyfieldstest.AssertFullyMapped(&r, toUserRow,
+		stickyfieldstest.Use(active{}), stickyfieldstest.Ignore("Version", "Address"))
+	if len(r.errors) > 0 {
+		t.Errorf("errors = %q, want none", r.errors)
+	}
+}
+
+func TestPopulate(t *testing.T) {
+	var u User
+	stickyfieldstest.Populate(&u)
+	if zero := stickyfieldstest.ZeroFields(u); !slices.Equal(zero, []string{"Status"}) {
+		t.Errorf("zero fields of a populated user = %v, want the interface only", zero)
+	}
+	if u.secret != "" {
+		t.Errorf("unexported field populated: %q", u.secret)
+	}
+	if u.Name == u.Address.City {
+		t.Errorf("populated values are equal: %q", u.Name)
+	}
+}
+
+func TestZeroFields(t *testing.T) {
+	var row *UserRow
+	want := []string{"ID", "Created", "Name", "Age", "Tags", "Address.City", "Address.Zip", "Status", "Version"}
+	if zero := stickyfieldstest.ZeroFields(row); !slices.Equal(zero, want) {
+		t.Errorf("zero fields of nil = %v, want %v", zero, want)
+	}
+
+	u := User{Base: Base{ID: 1}, Name: "Ann"}
+	want = []string{"Created", "Age", "Tags", "Address", "Status"}
+	if zero := stickyfieldstest.ZeroFields(&u); !slices.Equal(zero, want) {
+		t.Errorf("zero fields = %v, want %v", zero, want)
+	}
+}